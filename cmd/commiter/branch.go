@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/samcharles93/commiter/internal/branch"
+	"github.com/samcharles93/commiter/internal/config"
+	"github.com/samcharles93/commiter/internal/llm"
+)
+
+var (
+	branchTicket  string
+	branchPattern string
+)
+
+var branchCmd = &cobra.Command{
+	Use:   "branch",
+	Short: "Suggest a branch name for your changes and switch to it",
+	Long: `Ask the model for a branch name that describes the uncommitted changes,
+following branch_pattern from the config (default "{type}/{slug}"; {ticket}
+is filled from --ticket, or asked for when the pattern has it). Accept or edit
+the name, and a new branch is created and checked out with the working tree and
+index carried over. With -y the suggestion is used as is, and a pattern with
+{ticket} needs --ticket.`,
+	Args: cobra.NoArgs,
+	RunE: runBranch,
+}
+
+func init() {
+	branchCmd.Flags().StringVar(&branchTicket, "ticket", "", "Ticket or issue ID for the {ticket} placeholder")
+	branchCmd.Flags().StringVar(&branchPattern, "pattern", "", "Branch name pattern, e.g. \"{type}/{ticket}-{slug}\" (defaults to branch_pattern in config)")
+}
+
+func runBranch(cmd *cobra.Command, args []string) error {
+	repo, err := openRepo()
+	if err != nil {
+		return fmt.Errorf("error: %w", err)
+	}
+
+	cfg, err := config.Load(projectDir(repo))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: error loading config: %v\n", err)
+		cfg = &config.Config{}
+	}
+
+	// Ask for the ticket before waiting on the model, as the review screen does.
+	pattern := branchPattern
+	if pattern == "" {
+		pattern = cfg.GetBranchPattern()
+	}
+	stdin := bufio.NewReader(cmd.InOrStdin())
+	ticket := branchTicket
+	if ticket == "" && strings.Contains(pattern, "{ticket}") {
+		if bypassMode {
+			return fmt.Errorf("branch pattern %q needs a ticket: pass --ticket", pattern)
+		}
+		fmt.Fprint(cmd.OutOrStdout(), "Ticket (empty for none): ")
+		line, err := stdin.ReadString('\n')
+		if err != nil && line == "" {
+			return fmt.Errorf("no branch created")
+		}
+		ticket = strings.TrimSpace(line)
+	}
+
+	conn, err := llm.ResolveConnection(cfg, providerFlag, modelFlag)
+	if err != nil {
+		return err
+	}
+	if conn.APIKey == "" {
+		return fmt.Errorf("API key for %s not found", conn.Provider)
+	}
+
+	changes, err := branch.Changes(repo)
+	if err != nil {
+		return err
+	}
+
+	var types []string
+	if template := cfg.ResolveDefaultTemplate(); template != nil {
+		types = template.Types
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	suggestion, err := branch.Suggest(ctx, conn.NewProvider(), changes, types, "", config.ReadMemory(projectDir(repo)))
+	if err != nil {
+		return err
+	}
+
+	name := branch.Name(pattern, suggestion, ticket)
+
+	if !bypassMode {
+		fmt.Fprintf(cmd.OutOrStdout(), "Branch name [%s]: ", name)
+		line, err := stdin.ReadString('\n')
+		if err != nil && line == "" {
+			return fmt.Errorf("no branch created")
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			name = line
+		}
+	}
+
+	name, err = repo.CheckBranchName(name)
+	if err != nil {
+		return err
+	}
+	if err := repo.SwitchNewBranch(name); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "✓ Switched to new branch %s\n", name)
+	return nil
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBranchAsksForPatternTicket(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"{\"type\":\"feat\",\"slug\":\"add-file\"}"}}]}`))
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("HOME", t.TempDir())
+
+	repoDir := initRepoForBypassTests(t)
+	writeFile(t, filepath.Join(repoDir, ".commiter.json"), `{"provider":"openai","api_key":"test","base_url":"`+server.URL+`","branch_pattern":"{type}/{ticket}-{slug}"}`)
+	writeFile(t, filepath.Join(repoDir, "a.txt"), "hello\n")
+
+	prevDir, prevTicket, prevBypass := repoDirFlag, branchTicket, bypassMode
+	repoDirFlag, branchTicket = repoDir, ""
+	t.Cleanup(func() { repoDirFlag, branchTicket, bypassMode = prevDir, prevTicket, prevBypass })
+	t.Cleanup(func() { branchCmd.SetIn(nil); branchCmd.SetOut(nil) })
+
+	bypassMode = true
+	if err := runBranch(branchCmd, nil); err == nil || !strings.Contains(err.Error(), "--ticket") {
+		t.Fatalf("expected -y without --ticket to fail, got %v", err)
+	}
+
+	bypassMode = false
+	var out bytes.Buffer
+	branchCmd.SetIn(strings.NewReader("ABC-123\n\n"))
+	branchCmd.SetOut(&out)
+	if err := runBranch(branchCmd, nil); err != nil {
+		t.Fatalf("runBranch() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Ticket") {
+		t.Fatalf("expected a ticket prompt, got %q", out.String())
+	}
+	if got := strings.TrimSpace(runGit(t, repoDir, "branch", "--show-current")); got != "feat/ABC-123-add-file" {
+		t.Fatalf("branch = %q, want feat/ABC-123-add-file", got)
+	}
+	if _, err := os.Stat(filepath.Join(repoDir, "a.txt")); err != nil {
+		t.Fatalf("expected the change to move to the new branch: %v", err)
+	}
+}
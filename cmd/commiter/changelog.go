@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/samcharles93/commiter/internal/changelog"
+	"github.com/samcharles93/commiter/internal/config"
+	"github.com/samcharles93/commiter/internal/llm"
+)
+
+var (
+	changelogVersion string
+	changelogOutput  string
+	changelogAll     bool
+	changelogRewrite bool
+)
+
+var changelogCmd = &cobra.Command{
+	Use:   "changelog [<from> [<to>]]",
+	Short: "Generate a changelog from Conventional Commits history",
+	Long: `Collect the commits after <from> up to <to> (HEAD by default) and render them
+as Keep a Changelog markdown, grouped by type and scope, with BREAKING CHANGE
+notes listed first. <from> defaults to the latest tag; for a --version section
+a tag on <to> is skipped, so the section can be rebuilt after tagging.
+
+With --output the section is prepended to that file; a section for the same
+version is replaced, so the command can be run repeatedly. --rewrite asks the
+configured model to turn the entries into user-facing language.`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: runChangelog,
+}
+
+func init() {
+	changelogCmd.Flags().StringVar(&changelogVersion, "version", changelog.Unreleased, "Version heading for the section; other than Unreleased, today's date is added")
+	changelogCmd.Flags().StringVarP(&changelogOutput, "output", "o", "", "Prepend the section to this file (e.g. CHANGELOG.md) instead of printing it")
+	changelogCmd.Flags().BoolVar(&changelogAll, "all", false, "Include commit types without a section (chore, ci, test, ...) under Other Changes")
+	changelogCmd.Flags().BoolVar(&changelogRewrite, "rewrite", false, "Rewrite the entries into user-facing language with the configured model")
+}
+
+func runChangelog(cmd *cobra.Command, args []string) error {
+	repo, err := openRepo()
+	if err != nil {
+		return fmt.Errorf("error: %w", err)
+	}
+
+	to := "HEAD"
+	if len(args) > 1 {
+		to = args[1]
+	}
+	var from string
+	switch {
+	case len(args) > 0:
+		from = args[0]
+	case changelogVersion != changelog.Unreleased:
+		// The release may already be tagged at <to>; it starts at the tag before.
+		if from, err = repo.PreviousTag(to); err != nil {
+			return err
+		}
+	default:
+		if from, err = repo.LatestTag(to); err != nil {
+			return err
+		}
+	}
+
+	revRange := to
+	if from != "" {
+		revRange = from + ".." + to
+	}
+	commits, err := repo.CommitMessages(revRange)
+	if err != nil {
+		return err
+	}
+
+	var date string
+	if changelogVersion != changelog.Unreleased {
+		date = time.Now().Format(time.DateOnly)
+	}
+	release := changelog.Build(changelogVersion, date, changelog.Parse(commits), changelogAll)
+
+	if changelogRewrite && !release.Empty() {
+		cfg, err := config.Load(projectDir(repo))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: error loading config: %v\n", err)
+			cfg = &config.Config{}
+		}
+		conn, err := llm.ResolveConnection(cfg, providerFlag, modelFlag)
+		if err != nil {
+			return err
+		}
+		if conn.APIKey == "" {
+			return fmt.Errorf("API key for %s not found", conn.Provider)
+		}
+
+		template := cfg.ResolveDefaultTemplate()
+		lang := cfg.ResolveLanguage(template, languageOverride(repo))
+		provider := llm.NewGenericProvider(conn.APIKey, conn.Model, conn.BaseURL).WithDir(projectDir(repo))
+		if err := changelog.Rewrite(context.Background(), provider, &release, lang); err != nil {
+			return err
+		}
+	}
+
+	if changelogOutput == "" {
+		fmt.Fprint(cmd.OutOrStdout(), release.Markdown())
+		return nil
+	}
+
+	existing, err := os.ReadFile(changelogOutput)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to read %s: %w", changelogOutput, err)
+	}
+	if err := os.WriteFile(changelogOutput, []byte(changelog.Prepend(string(existing), release)), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", changelogOutput, err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "✓ Wrote [%s] (%d commits) to %s\n", release.Version, len(commits), changelogOutput)
+	return nil
+}
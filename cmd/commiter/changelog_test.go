@@ -0,0 +1,64 @@
+package main
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/samcharles93/commiter/internal/changelog"
+)
+
+func TestChangelogRebuildsSectionAfterTagging(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repoDir := initRepoForBypassTests(t)
+	runGit(t, repoDir, "tag", "v1.2.0")
+	runGit(t, repoDir, "commit", "--allow-empty", "-m", "feat: add csv export")
+	output := filepath.Join(t.TempDir(), "CHANGELOG.md")
+
+	prevDir, prevVersion, prevOutput := repoDirFlag, changelogVersion, changelogOutput
+	repoDirFlag, changelogVersion, changelogOutput = repoDir, "v1.3.0", output
+	t.Cleanup(func() {
+		repoDirFlag, changelogVersion, changelogOutput = prevDir, prevVersion, prevOutput
+	})
+	run := func() string {
+		t.Helper()
+		changelogCmd.SetOut(io.Discard)
+		if err := runChangelog(changelogCmd, nil); err != nil {
+			t.Fatalf("runChangelog() error = %v", err)
+		}
+		data, err := os.ReadFile(output)
+		if err != nil {
+			t.Fatalf("read changelog: %v", err)
+		}
+		return string(data)
+	}
+
+	first := run()
+	if !strings.Contains(first, "add csv export") {
+		t.Fatalf("expected the feature in the section, got:\n%s", first)
+	}
+
+	// Running again once the release is tagged keeps the same section.
+	runGit(t, repoDir, "tag", "v1.3.0")
+	if second := run(); second != first {
+		t.Fatalf("expected the same changelog after tagging, got:\n%s\nwant:\n%s", second, first)
+	}
+
+	// An unreleased section after the tag has nothing in it.
+	changelogVersion = changelog.Unreleased
+	changelogOutput = ""
+	var out strings.Builder
+	changelogCmd.SetOut(&out)
+	if err := runChangelog(changelogCmd, nil); err != nil {
+		t.Fatalf("runChangelog() error = %v", err)
+	}
+	if strings.Contains(out.String(), "add csv export") {
+		t.Fatalf("expected the tagged feature to be left out of Unreleased, got:\n%s", out.String())
+	}
+}
@@ -18,8 +18,11 @@ var configCmd = &cobra.Command{
 }
 
 func runConfig(cmd *cobra.Command, args []string) error {
+	// The project config is merged in when run inside a repository.
+	repo, _ := openRepo()
+
 	// Load current config
-	cfg, err := config.Load()
+	cfg, err := config.Load(projectDir(repo))
 	if err != nil {
 		fmt.Printf("Warning: error loading config: %v\n", err)
 		cfg = &config.Config{}
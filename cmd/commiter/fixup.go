@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+
+	"github.com/samcharles93/commiter/internal/config"
+	"github.com/samcharles93/commiter/internal/ui"
+)
+
+var autosquashFlag bool
+
+var fixupCmd = &cobra.Command{
+	Use:   "fixup",
+	Short: "Turn staged changes into fixup! commits for earlier commits",
+	Long: `Blame the lines each staged hunk changes and offer a "git commit --fixup" for
+the unpushed commit that wrote them. With --autosquash the fixups are folded
+into their targets straight away.`,
+	RunE: runFixup,
+}
+
+func init() {
+	fixupCmd.Flags().BoolVar(&autosquashFlag, "autosquash", false, "Run git rebase --autosquash after creating the fixups")
+}
+
+func runFixup(cmd *cobra.Command, args []string) error {
+	repo, err := openRepo()
+	if err != nil {
+		return fmt.Errorf("error: %w", err)
+	}
+
+	cfg, err := config.Load(projectDir(repo))
+	if err != nil {
+		fmt.Printf("Warning: error loading config: %v\n", err)
+		cfg = &config.Config{}
+	}
+
+	operation, err := repo.InProgress()
+	if err != nil {
+		return fmt.Errorf("error: %w", err)
+	}
+	if operation.InProgress() {
+		return fmt.Errorf("%s: finish it before creating fixups", operation.Title())
+	}
+
+	plan, err := repo.PlanFixups()
+	if err != nil {
+		return fmt.Errorf("error: %w", err)
+	}
+	if len(plan.Targets) == 0 {
+		if len(plan.Unmatched) == 0 {
+			fmt.Println("No staged changes. Stage the fix first.")
+		} else {
+			fmt.Println("No staged hunk changes lines from an unpushed commit.")
+		}
+		return nil
+	}
+
+	opts := ui.FixupOptions{
+		Commit:        commitOptions(cfg),
+		PreHooks:      cfg.PreCommitHooks,
+		PostHooks:     cfg.PostCommitHooks,
+		HookTimeout:   time.Duration(cfg.GetHookTimeoutSeconds()) * time.Second,
+		HooksDisabled: noHooksFlag,
+		Autosquash:    autosquashFlag,
+	}
+
+	if !bypassMode {
+		p := tea.NewProgram(ui.NewFixupModel(repo, plan, opts))
+		if _, err := p.Run(); err != nil {
+			return fmt.Errorf("error running fixup: %w", err)
+		}
+		return nil
+	}
+
+	// Bypass mode fixes up every target without asking.
+	warn := func(warning string) { fmt.Fprintf(os.Stderr, "Warning: %s\n", warning) }
+	created, err := repo.CommitFixups(plan, plan.Targets, opts.Commit, opts.PreCommit(repo), opts.PostCommit(repo, warn))
+	if err != nil {
+		return fmt.Errorf("created %d fixup commits before failing: %w", created, err)
+	}
+	for _, target := range plan.Targets {
+		fmt.Printf("✓ fixup! %s\n", target.Commit.Subject)
+	}
+
+	if opts.Autosquash {
+		if err := repo.Autosquash(plan.Targets); err != nil {
+			return err
+		}
+		fmt.Println("✓ Squashed into their targets")
+	}
+	return nil
+}
@@ -6,39 +6,98 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 
+	"github.com/samcharles93/commiter/internal/config"
 	"github.com/samcharles93/commiter/internal/git"
+	"github.com/samcharles93/commiter/internal/llm"
 	"github.com/samcharles93/commiter/internal/ui"
 )
 
 var (
-	historyLimit int
+	historyLimit  int
+	historyFilter git.HistoryFilter
+	historyGraph  bool
 )
 
 var historyCmd = &cobra.Command{
-	Use:   "history",
+	Use:   "history [<revision-range>...] [-- <path>...]",
 	Short: "Browse commit history",
-	Long:  `Browse and search recent commit history with diffs.`,
-	RunE:  runHistory,
+	Long: `Browse and search commit history with diffs, and reword unpushed commits.
+
+Commits are loaded --limit at a time, and more are read as you scroll to the
+end of the list. The filters are passed to git log, so they apply to the whole
+history rather than the loaded page:
+
+  commiter history main..feature
+  commiter history --author-filter alice --since "2 weeks ago" -- internal/git
+  commiter history --grep "^fix" --pickaxe parseCommitLog
+
+--graph draws the branches and merges beside the list, and --all includes
+every branch and tag rather than only the current one:
+
+  commiter history --graph --all`,
+	RunE: runHistory,
 }
 
 func init() {
-	historyCmd.Flags().IntVarP(&historyLimit, "limit", "n", 50, "Number of commits to show")
+	historyCmd.Flags().IntVarP(&historyLimit, "limit", "n", 50, "Number of commits to load at a time")
+	// --author is the commit option, applied to rewords, reverts and picks.
+	historyCmd.Flags().StringVar(&historyFilter.Author, "author-filter", "", "Only commits whose author matches this pattern")
+	historyCmd.Flags().StringVar(&historyFilter.Since, "since", "", "Only commits newer than this date, e.g. \"2 weeks ago\"")
+	historyCmd.Flags().StringVar(&historyFilter.Until, "until", "", "Only commits older than this date")
+	historyCmd.Flags().StringVar(&historyFilter.Grep, "grep", "", "Only commits whose message matches this pattern")
+	historyCmd.Flags().StringVar(&historyFilter.Pickaxe, "pickaxe", "", "Only commits that add or remove this string (git log -S)")
+	historyCmd.Flags().BoolVar(&historyFilter.All, "all", false, "Include commits from every branch and tag")
+	historyCmd.Flags().BoolVar(&historyGraph, "graph", false, "Draw the commit graph beside the list")
 }
 
 func runHistory(cmd *cobra.Command, args []string) error {
-	// Get commit history
-	commits, err := git.GetCommitHistory(historyLimit)
+	repo, err := openRepo()
+	if err != nil {
+		return err
+	}
+
+	if historyLimit < 1 {
+		return fmt.Errorf("--limit must be at least 1")
+	}
+	filter := historyFilter
+	filter.Graph = historyGraph
+	if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+		filter.Revs, filter.Paths = args[:dash], args[dash:]
+	} else {
+		filter.Revs = args
+	}
+
+	// Get the first page of commit history
+	commits, err := repo.CommitHistory(filter, 0, historyLimit)
 	if err != nil {
-		return fmt.Errorf("failed to get commit history: %w", err)
+		return err
 	}
 
 	if len(commits) == 0 {
-		fmt.Println("No commits found.")
+		if filter.String() != "" {
+			fmt.Println("No commits match the filters.")
+		} else {
+			fmt.Println("No commits found.")
+		}
 		return nil
 	}
 
+	// Rewording is offered when a provider is configured
+	opts := ui.HistoryOptions{Language: languageOverride(repo), Filter: filter, PageSize: historyLimit, Graph: historyGraph}
+	cfg, err := config.Load(projectDir(repo))
+	if err != nil {
+		cfg = &config.Config{}
+	}
+	opts.Config = cfg
+	opts.Commit = commitOptions(cfg)
+	if conn, err := llm.ResolveConnection(cfg, providerFlag, modelFlag); err == nil && conn.APIKey != "" {
+		opts.Provider = llm.NewGenericProvider(conn.APIKey, conn.Model, conn.BaseURL).WithDir(projectDir(repo))
+		opts.ProviderName = conn.Provider
+		opts.ModelName = conn.Model
+	}
+
 	// Create and run history TUI
-	m := ui.NewHistoryModel(commits)
+	m := ui.NewHistoryModel(repo, commits, opts)
 	p := tea.NewProgram(m)
 	if _, err := p.Run(); err != nil {
 		return fmt.Errorf("error running history browser: %w", err)
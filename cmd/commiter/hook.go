@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/samcharles93/commiter/internal/config"
+	"github.com/samcharles93/commiter/internal/hooks"
+	"github.com/samcharles93/commiter/internal/lint"
+	"github.com/samcharles93/commiter/internal/llm"
+)
+
+var hookCmd = &cobra.Command{
+	Use:   "hook",
+	Short: "Install commiter as a git prepare-commit-msg hook",
+	Long: `Let plain "git commit" and IDE commit buttons use commiter: the installed
+prepare-commit-msg hook fills in a generated message for you to edit. Merges,
+amends, messages given with -m and rebases are left alone.`,
+}
+
+var hookInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install the prepare-commit-msg hook (honours core.hooksPath)",
+	Args:  cobra.NoArgs,
+	RunE:  runHookInstall,
+}
+
+var hookUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Remove the hook and restore the one it replaced",
+	Args:  cobra.NoArgs,
+	RunE:  runHookUninstall,
+}
+
+var hookPrepareCmd = &cobra.Command{
+	Use:    hooks.PrepareCommitMsg + " <file> [<source> [<commit>]]",
+	Short:  "Entry point for the installed git hook",
+	Args:   cobra.RangeArgs(1, 3),
+	Hidden: true,
+	RunE:   runHookPrepare,
+}
+
+func init() {
+	hookCmd.AddCommand(hookInstallCmd, hookUninstallCmd, hookPrepareCmd)
+}
+
+func runHookInstall(cmd *cobra.Command, args []string) error {
+	repo, err := openRepo()
+	if err != nil {
+		return fmt.Errorf("error: %w", err)
+	}
+	dir, err := repo.HooksDir()
+	if err != nil {
+		return err
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		exe = "commiter"
+	}
+	if err := hooks.Install(dir, exe); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Installed %s hook in %s\n", hooks.PrepareCommitMsg, dir)
+	return nil
+}
+
+func runHookUninstall(cmd *cobra.Command, args []string) error {
+	repo, err := openRepo()
+	if err != nil {
+		return fmt.Errorf("error: %w", err)
+	}
+	dir, err := repo.HooksDir()
+	if err != nil {
+		return err
+	}
+
+	if err := hooks.Uninstall(dir); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Removed %s hook from %s\n", hooks.PrepareCommitMsg, dir)
+	return nil
+}
+
+// runHookPrepare fills the message file for a plain "git commit". Failing to
+// generate a message never blocks the commit; the editor just opens empty.
+func runHookPrepare(cmd *cobra.Command, args []string) error {
+	// git names where the message came from: message (-m/-F), template,
+	// merge, squash or commit (-c/-C/--amend). Only an empty source is ours.
+	if len(args) > 1 && args[1] != "" {
+		return nil
+	}
+	if err := prepareCommitMessage(args[0]); err != nil {
+		fmt.Fprintf(os.Stderr, "commiter: no message generated: %v\n", err)
+	}
+	return nil
+}
+
+func prepareCommitMessage(file string) error {
+	repo, err := openRepo()
+	if err != nil {
+		return err
+	}
+	operation, err := repo.InProgress()
+	if err != nil {
+		return err
+	}
+	if operation.InProgress() {
+		return nil
+	}
+
+	existing, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	if lint.CleanMessage(string(existing)) != "" {
+		// Something else already wrote a message.
+		return nil
+	}
+
+	diff, err := repo.GetStagedDiff()
+	if err != nil {
+		return err
+	}
+	if len(diff) == 0 {
+		return nil
+	}
+
+	cfg, err := config.Load(projectDir(repo))
+	if err != nil {
+		cfg = &config.Config{}
+	}
+	conn, err := llm.ResolveConnection(cfg, providerFlag, modelFlag)
+	if err != nil {
+		return err
+	}
+	if conn.APIKey == "" {
+		return fmt.Errorf("API key for %s not found", conn.Provider)
+	}
+
+	provider := conn.NewProvider().WithDir(projectDir(repo))
+	template := cfg.ResolveDefaultTemplate()
+	template = template.WithLanguage(cfg.ResolveLanguage(template, languageOverride(repo)))
+	generate := func(ctx context.Context, history []llm.Message) (string, error) {
+		ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+		return provider.GenerateMessage(ctx, string(diff), history, template)
+	}
+
+	fmt.Fprintln(os.Stderr, "commiter: generating commit message...")
+	rules := lint.RulesFor(template, config.ReadMemory(projectDir(repo)))
+	message, _, err := lint.GenerateWithRepair(context.Background(), generate, nil, rules, cfg.GetLintRepairAttempts())
+	if err != nil {
+		return err
+	}
+
+	// Keep git's comment block below the message.
+	return os.WriteFile(file, []byte(message+"\n"+string(existing)), 0o644)
+}
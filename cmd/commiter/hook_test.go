@@ -0,0 +1,125 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHookPrepareFillsEmptyMessagesOnly(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"feat: add a file"}}]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv("HOME", t.TempDir())
+
+	repoDir := initRepoForBypassTests(t)
+	writeFile(t, filepath.Join(repoDir, ".commiter.json"), `{"provider":"openai","api_key":"test","base_url":"`+server.URL+`"}`)
+	writeFile(t, filepath.Join(repoDir, "a.txt"), "hello\n")
+	runGit(t, repoDir, "add", "a.txt")
+
+	prevDir := repoDirFlag
+	repoDirFlag = repoDir
+	t.Cleanup(func() { repoDirFlag = prevDir })
+
+	comments := "\n# Please enter the commit message for your changes.\n"
+	file := filepath.Join(t.TempDir(), "COMMIT_EDITMSG")
+
+	// A message from -m is left alone, without asking the model.
+	writeFile(t, file, "fix: typed by hand\n"+comments)
+	if err := runHookPrepare(hookPrepareCmd, []string{file, "message"}); err != nil {
+		t.Fatalf("runHookPrepare() error = %v", err)
+	}
+	if requests != 0 {
+		t.Fatalf("expected no generation for -m, got %d requests", requests)
+	}
+
+	writeFile(t, file, comments)
+	if err := runHookPrepare(hookPrepareCmd, []string{file}); err != nil {
+		t.Fatalf("runHookPrepare() error = %v", err)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("read message: %v", err)
+	}
+	if string(data) != "feat: add a file\n"+comments {
+		t.Fatalf("unexpected message file: %q", data)
+	}
+
+	// During a merge or rebase the prepared message is kept.
+	runGit(t, repoDir, "commit", "-m", "feat: add a file")
+	head := strings.TrimSpace(runGit(t, repoDir, "rev-parse", "HEAD"))
+	writeFile(t, filepath.Join(repoDir, ".git", "MERGE_HEAD"), head+"\n")
+	writeFile(t, filepath.Join(repoDir, "b.txt"), "merge\n")
+	runGit(t, repoDir, "add", "b.txt")
+	writeFile(t, file, comments)
+	if err := runHookPrepare(hookPrepareCmd, []string{file}); err != nil {
+		t.Fatalf("runHookPrepare() error = %v", err)
+	}
+	if data, _ := os.ReadFile(file); string(data) != comments || requests != 1 {
+		t.Fatalf("expected no generation during a merge, got %q after %d requests", data, requests)
+	}
+}
+
+func TestHookPrepareReadsProjectFilesFromRepoRoot(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"feat: add a file"}}]}`))
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("HOME", t.TempDir())
+
+	// The working directory is another project; -C points at the repository.
+	otherDir := t.TempDir()
+	writeFile(t, filepath.Join(otherDir, ".commiter.json"), `{"provider":"openai","api_key":"test","base_url":"http://127.0.0.1:1"}`)
+	writeFile(t, filepath.Join(otherDir, "MEMORY.md"), "Other project's notes.\n")
+	writeFile(t, filepath.Join(otherDir, "SYSTEM.md"), "Other project's prompt.\n")
+	t.Chdir(otherDir)
+
+	repoDir := initRepoForBypassTests(t)
+	writeFile(t, filepath.Join(repoDir, ".commiter.json"), `{"provider":"openai","api_key":"test","base_url":"`+server.URL+`"}`)
+	writeFile(t, filepath.Join(repoDir, "MEMORY.md"), "Use British spelling.\n")
+	writeFile(t, filepath.Join(repoDir, "SYSTEM.md"), "You write commit messages for this repository.\n")
+	if err := os.MkdirAll(filepath.Join(repoDir, "sub"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	writeFile(t, filepath.Join(repoDir, "sub", "a.txt"), "hello\n")
+	runGit(t, repoDir, "add", "sub/a.txt")
+
+	prevDir := repoDirFlag
+	repoDirFlag = filepath.Join(repoDir, "sub")
+	t.Cleanup(func() { repoDirFlag = prevDir })
+
+	file := filepath.Join(t.TempDir(), "COMMIT_EDITMSG")
+	writeFile(t, file, "\n# Please enter the commit message for your changes.\n")
+	if err := runHookPrepare(hookPrepareCmd, []string{file}); err != nil {
+		t.Fatalf("runHookPrepare() error = %v", err)
+	}
+	for _, want := range []string{"Use British spelling.", "You write commit messages for this repository."} {
+		if !strings.Contains(string(body), want) {
+			t.Fatalf("expected %q from the repository in the request, got %s", want, body)
+		}
+	}
+	if strings.Contains(string(body), "Other project") {
+		t.Fatalf("expected nothing from the working directory's project, got %s", body)
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/samcharles93/commiter/internal/config"
+	"github.com/samcharles93/commiter/internal/lint"
+)
+
+var (
+	lintFormat   string
+	lintStdin    bool
+	lintFile     string
+	lintTemplate string
+	lintStrict   bool
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint [<range>]",
+	Short: "Check commit messages against the configured template",
+	Long: `Validate commit messages against the default template (or --template): its
+types, the Conventional Commits format, the subject length and the body rules.
+Without a range, the commits on HEAD that are on no remote branch are checked.
+Subjects git writes itself for fixups, squashes, merges and reverts are skipped.
+
+With --file or --stdin a single message is checked instead, so the command
+works as a commit-msg hook:
+
+    commiter lint --file "$1"
+
+The command exits non-zero when any message has errors, or any issue at all
+with --strict.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runLint,
+}
+
+func init() {
+	lintCmd.Flags().StringVar(&lintFormat, "format", lint.FormatText, "Report format: "+strings.Join(lint.Formats, ", "))
+	lintCmd.Flags().BoolVar(&lintStdin, "stdin", false, "Check one message read from standard input")
+	lintCmd.Flags().StringVar(&lintFile, "file", "", "Check one message read from a file, such as a commit-msg hook's argument")
+	lintCmd.Flags().StringVar(&lintTemplate, "template", "", "Template to check against (defaults to the configured default template)")
+	lintCmd.Flags().BoolVar(&lintStrict, "strict", false, "Fail on warnings as well as errors")
+}
+
+func runLint(cmd *cobra.Command, args []string) error {
+	if !slices.Contains(lint.Formats, lintFormat) {
+		return fmt.Errorf("unknown format %q (use one of: %s)", lintFormat, strings.Join(lint.Formats, ", "))
+	}
+	single := lintStdin || lintFile != ""
+	if lintStdin && lintFile != "" {
+		return errors.New("--stdin and --file cannot be used together")
+	}
+	if single && len(args) > 0 {
+		return errors.New("a range cannot be combined with --stdin or --file")
+	}
+
+	// A commit-msg hook runs inside the repository, but a message from a file
+	// or stdin can be checked without one.
+	repo, repoErr := openRepo()
+	if repoErr != nil && !single {
+		return fmt.Errorf("error: %w", repoErr)
+	}
+
+	cfg, err := config.Load(projectDir(repo))
+	if err != nil {
+		// Keep stdout clean for the JSON and GitHub formats.
+		fmt.Fprintf(os.Stderr, "Warning: error loading config: %v\n", err)
+		cfg = &config.Config{}
+	}
+
+	template := cfg.ResolveDefaultTemplate()
+	if lintTemplate != "" {
+		template = cfg.FindTemplate(lintTemplate)
+		if template == nil {
+			return fmt.Errorf("unknown template %q", lintTemplate)
+		}
+	}
+	if template == nil {
+		fmt.Fprintln(os.Stderr, "Note: no default template is configured, so only the generic message rules apply. Pass --template to pick one.")
+	}
+
+	lang := languageFlag
+	if repoErr == nil {
+		lang = languageOverride(repo)
+	}
+	template = template.WithLanguage(cfg.ResolveLanguage(template, lang))
+	rules := lint.RulesFor(template, config.ReadMemory(projectDir(repo)))
+
+	var results []lint.Result
+	if single {
+		var data []byte
+		if lintStdin {
+			data, err = io.ReadAll(cmd.InOrStdin())
+		} else {
+			data, err = os.ReadFile(lintFile)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read commit message: %w", err)
+		}
+		results = append(results, lint.Check("", lint.CleanMessage(string(data)), rules))
+	} else {
+		var revRange string
+		if len(args) > 0 {
+			revRange = args[0]
+		}
+		messages, err := repo.CommitMessages(revRange)
+		if err != nil {
+			return err
+		}
+		for _, message := range messages {
+			results = append(results, lint.Check(message.Hash, message.Message, rules))
+		}
+	}
+
+	if err := lint.WriteReport(cmd.OutOrStdout(), lintFormat, results); err != nil {
+		return err
+	}
+	if lint.Failed(results, lintStrict) {
+		return errors.New("commit message lint failed")
+	}
+	return nil
+}
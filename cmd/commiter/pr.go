@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/samcharles93/commiter/internal/clipboard"
+	"github.com/samcharles93/commiter/internal/config"
+	"github.com/samcharles93/commiter/internal/llm"
+	"github.com/samcharles93/commiter/internal/pr"
+)
+
+var (
+	prBase string
+	prCopy bool
+)
+
+var prCmd = &cobra.Command{
+	Use:   "pr",
+	Short: "Write a pull request title and description for the current branch",
+	Long: `Compare the current branch with its base (--base, pr_base_branch in the config,
+or the remote's default branch) from their merge base, and generate a pull
+request title and markdown description from the commits and the diff. A
+.github/pull_request_template.md is filled in when present.
+
+Nothing is pushed or opened: the result is printed, or copied with --copy.`,
+	Args: cobra.NoArgs,
+	RunE: runPR,
+}
+
+func init() {
+	prCmd.Flags().StringVar(&prBase, "base", "", "Branch the pull request targets (defaults to pr_base_branch or the remote's default branch)")
+	prCmd.Flags().BoolVarP(&prCopy, "copy", "c", false, "Copy the result to the clipboard instead of printing it")
+}
+
+func runPR(cmd *cobra.Command, args []string) error {
+	repo, err := openRepo()
+	if err != nil {
+		return fmt.Errorf("error: %w", err)
+	}
+
+	cfg, err := config.Load(projectDir(repo))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: error loading config: %v\n", err)
+		cfg = &config.Config{}
+	}
+
+	conn, err := llm.ResolveConnection(cfg, providerFlag, modelFlag)
+	if err != nil {
+		return err
+	}
+	// A local, OpenAI-compatible server usually needs no key.
+	if conn.APIKey == "" && (conn.BaseURL == llm.DefaultOpenAIAPIURL || conn.BaseURL == llm.DefaultDeepSeekAPIURL) {
+		return fmt.Errorf("API key for %s not found", conn.Provider)
+	}
+
+	base := prBase
+	if base == "" {
+		base = cfg.PRBaseBranch
+	}
+	if base == "" {
+		if base, err = repo.DefaultBranch(); err != nil {
+			return err
+		}
+	}
+
+	branch, err := pr.Gather(repo, base)
+	if err != nil {
+		return err
+	}
+	if len(branch.Commits) == 0 {
+		return fmt.Errorf("no commits on this branch since %s", base)
+	}
+	template, err := pr.FindTemplate(projectDir(repo))
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Describing %d commits since %s...\n", len(branch.Commits), base)
+	lang := cfg.ResolveLanguage(cfg.ResolveDefaultTemplate(), languageOverride(repo))
+	description, err := pr.Generate(context.Background(), conn.NewProvider(), branch, template, lang, config.ReadMemory(projectDir(repo)))
+	if err != nil {
+		return err
+	}
+
+	if prCopy {
+		method, err := clipboard.Copy(description.String())
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "%s: %s\n", clipboard.Status("pull request description", method), description.Title)
+		return nil
+	}
+	fmt.Fprint(cmd.OutOrStdout(), description.String())
+	return nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPRFillsRootTemplateFromSubdirectory(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"Add a file\n\n## Summary\nAdds a file."}}]}`))
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("HOME", t.TempDir())
+
+	repoDir := initRepoForBypassTests(t)
+	writeFile(t, filepath.Join(repoDir, ".commiter.json"), `{"provider":"openai","api_key":"test","base_url":"`+server.URL+`"}`)
+	if err := os.MkdirAll(filepath.Join(repoDir, ".github"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	writeFile(t, filepath.Join(repoDir, ".github", "pull_request_template.md"), "## Summary\n\n## Rollout plan\n")
+	runGit(t, repoDir, "add", ".github")
+	runGit(t, repoDir, "commit", "-m", "chore: add pull request template")
+	runGit(t, repoDir, "branch", "base")
+	runGit(t, repoDir, "checkout", "-b", "feature")
+	if err := os.MkdirAll(filepath.Join(repoDir, "sub"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	writeFile(t, filepath.Join(repoDir, "sub", "a.txt"), "hello\n")
+	runGit(t, repoDir, "add", "sub/a.txt")
+	runGit(t, repoDir, "commit", "-m", "feat: add a file")
+
+	prevDir, prevBase := repoDirFlag, prBase
+	repoDirFlag, prBase = filepath.Join(repoDir, "sub"), "base"
+	t.Cleanup(func() { repoDirFlag, prBase = prevDir, prevBase })
+
+	var out bytes.Buffer
+	prCmd.SetOut(&out)
+	t.Cleanup(func() { prCmd.SetOut(nil) })
+	if err := runPR(prCmd, nil); err != nil {
+		t.Fatalf("runPR() error = %v", err)
+	}
+	if !strings.Contains(string(body), "Rollout plan") {
+		t.Fatalf("expected the repository's template in the request, got %s", body)
+	}
+	if !strings.HasPrefix(out.String(), "Add a file\n") {
+		t.Fatalf("unexpected output %q", out.String())
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+
+	"github.com/samcharles93/commiter/internal/config"
+	"github.com/samcharles93/commiter/internal/git"
+	"github.com/samcharles93/commiter/internal/llm"
+	"github.com/samcharles93/commiter/internal/release"
+	"github.com/samcharles93/commiter/internal/ui"
+)
+
+var (
+	releasePre    string
+	releaseDryRun bool
+)
+
+var releaseCmd = &cobra.Command{
+	Use:   "release",
+	Short: "Bump the semantic version and create an annotated release tag",
+	Long: `Find the latest semver tag, read the commits since then with the template's
+types, and compute the next version: major for breaking changes, minor for
+features and patch for fixes. The tag message is written by the model and
+reviewed before the annotated tag is created.
+
+Use --pre rc to cut a pre-release (v1.3.0-rc.1, then -rc.2, ...); running
+without --pre afterwards promotes it to v1.3.0.`,
+	Args: cobra.NoArgs,
+	RunE: runRelease,
+}
+
+func init() {
+	releaseCmd.Flags().StringVar(&releasePre, "pre", "", "Pre-release identifier, e.g. rc or beta")
+	releaseCmd.Flags().BoolVar(&releaseDryRun, "dry-run", false, "Print the next version and its notes without creating a tag")
+}
+
+func runRelease(cmd *cobra.Command, args []string) error {
+	repo, err := openRepo()
+	if err != nil {
+		return fmt.Errorf("error: %w", err)
+	}
+
+	cfg, err := config.Load(projectDir(repo))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: error loading config: %v\n", err)
+		cfg = &config.Config{}
+	}
+
+	template := cfg.ResolveDefaultTemplate()
+	var types []string
+	if template != nil {
+		types = template.Types
+	}
+	plan, err := release.PlanRelease(repo, types, releasePre)
+	if err != nil {
+		return err
+	}
+
+	if releaseDryRun {
+		previous := ", first release"
+		if plan.Previous != "" {
+			previous = " since " + plan.Previous
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Next version: %s (%s bump, %d commits%s)\n\n%s",
+			plan.Tag(), plan.Bump, len(plan.Commits), previous, plan.Notes())
+		return nil
+	}
+
+	conn, err := llm.ResolveConnection(cfg, providerFlag, modelFlag)
+	if err != nil {
+		return err
+	}
+	if conn.APIKey == "" {
+		return fmt.Errorf("API key for %s not found", conn.Provider)
+	}
+	provider := llm.NewGenericProvider(conn.APIKey, conn.Model, conn.BaseURL).WithDir(projectDir(repo))
+
+	commit := commitOptions(cfg)
+	opts := ui.ReleaseOptions{
+		Language:     cfg.ResolveLanguage(template, languageOverride(repo)),
+		Tag:          git.TagOptions{Sign: commit.Sign, SigningKey: commit.SigningKey},
+		ProviderName: conn.Provider,
+		ModelName:    conn.Model,
+	}
+
+	if !bypassMode {
+		p := tea.NewProgram(ui.NewReleaseModel(provider, repo, plan, opts))
+		if _, err := p.Run(); err != nil {
+			return fmt.Errorf("error running release: %w", err)
+		}
+		return nil
+	}
+
+	notes, err := release.GenerateNotes(context.Background(), provider, plan, opts.Language, config.ReadMemory(projectDir(repo)))
+	if err != nil {
+		return err
+	}
+	if err := repo.CreateTag(plan.Tag(), notes, opts.Tag); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Tagged %s\n\n%s\n", plan.Tag(), notes)
+	return nil
+}
@@ -1,7 +1,15 @@
 package main
 
 import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/spf13/cobra"
+
+	"github.com/samcharles93/commiter/internal/git"
 )
 
 var (
@@ -11,6 +19,18 @@ var (
 	bypassMode    bool
 	customMessage string
 	noHooksFlag   bool
+	languageFlag  string
+	repoDirFlag   string
+	gitTraceFlag  string
+
+	// Commit option flags
+	signFlag     bool
+	signoffFlag  bool
+	authorFlag   string
+	dateFlag     string
+	trailerFlags []string
+	noVerifyFlag bool
+	amendFlag    bool
 )
 
 // rootCmd represents the base command
@@ -30,6 +50,18 @@ func init() {
 	rootCmd.PersistentFlags().BoolVarP(&bypassMode, "bypass", "y", false, "Bypass interactive mode and commit immediately")
 	rootCmd.PersistentFlags().StringVarP(&customMessage, "message", "m", "", "Use custom commit message (skips LLM generation)")
 	rootCmd.PersistentFlags().BoolVar(&noHooksFlag, "no-hooks", false, "Skip configured pre/post commit hooks for this run")
+	rootCmd.PersistentFlags().StringVarP(&repoDirFlag, "dir", "C", "", "Run as if commiter was started in this directory")
+	rootCmd.PersistentFlags().StringVar(&gitTraceFlag, "git-trace", "", "Append the timing of every git call to this file")
+	rootCmd.PersistentFlags().StringVar(&languageFlag, "lang", "", "Commit message language (e.g., en, de); overrides config and git config commiter.language")
+
+	// Commit flags
+	rootCmd.PersistentFlags().BoolVarP(&signFlag, "sign", "S", false, "GPG/SSH-sign the commit (defaults to sign_commits in config)")
+	rootCmd.PersistentFlags().BoolVarP(&signoffFlag, "signoff", "s", false, "Add a Signed-off-by trailer (defaults to signoff in config)")
+	rootCmd.PersistentFlags().StringVar(&authorFlag, "author", "", "Override the commit author, e.g. \"Jane Doe <jane@example.com>\"")
+	rootCmd.PersistentFlags().StringVar(&dateFlag, "date", "", "Override the author date")
+	rootCmd.PersistentFlags().StringArrayVar(&trailerFlags, "trailer", nil, "Add a \"Key: value\" trailer (repeatable)")
+	rootCmd.PersistentFlags().BoolVar(&noVerifyFlag, "no-verify", false, "Skip the repository's git commit hooks")
+	rootCmd.Flags().BoolVar(&amendFlag, "amend", false, "Amend the last commit, regenerating its message from the whole amended commit")
 
 	// Set the run function
 	rootCmd.RunE = runStart
@@ -37,9 +69,57 @@ func init() {
 	// Add subcommands
 	rootCmd.AddCommand(configCmd)
 	rootCmd.AddCommand(historyCmd)
+	rootCmd.AddCommand(splitCmd)
+	rootCmd.AddCommand(fixupCmd)
+	rootCmd.AddCommand(lintCmd)
+	rootCmd.AddCommand(changelogCmd)
+	rootCmd.AddCommand(releaseCmd)
+	rootCmd.AddCommand(prCmd)
+	rootCmd.AddCommand(hookCmd)
+	rootCmd.AddCommand(branchCmd)
 }
 
 // Execute runs the root command.
 func Execute() error {
 	return rootCmd.Execute()
 }
+
+// openRepo opens the repository selected by -C, recording git call timings
+// when --git-trace is set.
+func openRepo() (*git.Repo, error) {
+	var runner git.Runner = git.ExecRunner{}
+	if gitTraceFlag != "" {
+		traceFile, err := os.OpenFile(gitTraceFlag, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open git trace file: %w", err)
+		}
+		var mu sync.Mutex
+		runner = git.TimingRunner{
+			Runner: runner,
+			Observe: func(cmd git.Command, elapsed time.Duration, err error) {
+				mu.Lock()
+				defer mu.Unlock()
+				status := "ok"
+				if err != nil {
+					status = "failed"
+				}
+				fmt.Fprintf(traceFile, "%s\t%s\t%s\tgit %s\n", time.Now().Format(time.RFC3339), elapsed.Round(time.Microsecond), status, strings.Join(cmd.Args, " "))
+			},
+		}
+	}
+	return git.Open(repoDirFlag, runner)
+}
+
+// projectDir returns the root of the opened repository, where the project's
+// .commiter.json, MEMORY.md and SYSTEM.md are read from. Without a repository
+// it is the -C directory itself.
+func projectDir(repo *git.Repo) string {
+	if repo == nil {
+		return repoDirFlag
+	}
+	root, err := repo.Root()
+	if err != nil {
+		return repoDirFlag
+	}
+	return root
+}
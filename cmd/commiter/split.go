@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+
+	"github.com/samcharles93/commiter/internal/config"
+	"github.com/samcharles93/commiter/internal/llm"
+	"github.com/samcharles93/commiter/internal/split"
+	"github.com/samcharles93/commiter/internal/ui"
+)
+
+var splitCmd = &cobra.Command{
+	Use:   "split",
+	Short: "Split your changes into several commits",
+	Long: `Ask the model to group all uncommitted changes, staged or not, into a series
+of logical commits. Review and adjust the grouping, then commit each group in turn.`,
+	RunE: runSplit,
+}
+
+func runSplit(cmd *cobra.Command, args []string) error {
+	repo, err := openRepo()
+	if err != nil {
+		return fmt.Errorf("error: %w", err)
+	}
+
+	cfg, err := config.Load(projectDir(repo))
+	if err != nil {
+		fmt.Printf("Warning: error loading config: %v\n", err)
+		cfg = &config.Config{}
+	}
+
+	conn, err := llm.ResolveConnection(cfg, providerFlag, modelFlag)
+	if err != nil {
+		return err
+	}
+	if conn.APIKey == "" {
+		return fmt.Errorf("API key for %s not found", conn.Provider)
+	}
+
+	operation, err := repo.InProgress()
+	if err != nil {
+		return fmt.Errorf("error: %w", err)
+	}
+	if operation.InProgress() {
+		return fmt.Errorf("%s: finish it before splitting changes", operation.Title())
+	}
+
+	plan, err := split.BuildPlan(repo)
+	if err != nil {
+		return fmt.Errorf("error: %w", err)
+	}
+	if len(plan.Units) == 0 {
+		fmt.Println("No changes detected.")
+		return nil
+	}
+
+	template := cfg.ResolveDefaultTemplate()
+	template = template.WithLanguage(cfg.ResolveLanguage(template, languageOverride(repo)))
+
+	provider := llm.NewGenericProvider(conn.APIKey, conn.Model, conn.BaseURL).WithDir(projectDir(repo))
+	m := ui.NewSplitModel(provider, repo, plan, ui.SplitOptions{
+		Template:      template,
+		Commit:        commitOptions(cfg),
+		PreHooks:      cfg.PreCommitHooks,
+		PostHooks:     cfg.PostCommitHooks,
+		HookTimeout:   time.Duration(cfg.GetHookTimeoutSeconds()) * time.Second,
+		HooksDisabled: noHooksFlag,
+	})
+
+	p := tea.NewProgram(m)
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("error running split: %w", err)
+	}
+
+	return nil
+}
@@ -13,93 +13,94 @@ import (
 	"github.com/samcharles93/commiter/internal/config"
 	"github.com/samcharles93/commiter/internal/git"
 	"github.com/samcharles93/commiter/internal/hooks"
+	"github.com/samcharles93/commiter/internal/lint"
 	"github.com/samcharles93/commiter/internal/llm"
 	"github.com/samcharles93/commiter/internal/ui"
 )
 
-const (
-	DefaultDeepSeekAPIURL = "https://api.deepseek.com/v1/chat/completions"
-	DefaultOpenAIAPIURL   = "https://api.openai.com/v1/chat/completions"
-)
+// repoLanguageKey is the git config key for a repository-local language override.
+const repoLanguageKey = "commiter.language"
 
 func runStart(cmd *cobra.Command, args []string) error {
+	repo, err := openRepo()
+	if err != nil {
+		return fmt.Errorf("error: %w", err)
+	}
+
 	// Load config
-	cfg, err := config.Load()
+	cfg, err := config.Load(projectDir(repo))
 	if err != nil {
 		fmt.Printf("Warning: error loading config: %v\n", err)
 		cfg = &config.Config{}
 	}
 
-	// Determine provider
-	providerName := "deepseek"
-	if cfg.Provider != "" {
-		providerName = cfg.Provider
-	}
-	if providerFlag != "" {
-		providerName = providerFlag
-	}
-
-	// Get API key, model, and baseURL based on provider
-	var apiKey, model, baseURL string
-	switch strings.ToLower(providerName) {
-	case "openai":
-		apiKey = os.Getenv("OPENAI_API_KEY")
-		model = "gpt-4o"
-		baseURL = DefaultOpenAIAPIURL
-	case "deepseek":
-		apiKey = os.Getenv("DEEPSEEK_API_KEY")
-		model = "deepseek-chat"
-		baseURL = DefaultDeepSeekAPIURL
-	default:
-		return fmt.Errorf("unknown provider %q", providerName)
-	}
-
-	// Override with config values
-	if cfg.APIKey != "" {
-		apiKey = cfg.APIKey
-	}
-	if cfg.Model != "" {
-		model = cfg.Model
-	}
-	if cfg.BaseURL != "" {
-		baseURL = cfg.BaseURL
-	}
-
-	// Override with flags
-	if modelFlag != "" {
-		model = modelFlag
+	conn, err := llm.ResolveConnection(cfg, providerFlag, modelFlag)
+	if err != nil {
+		return err
 	}
+	apiKey, model, baseURL, providerName := conn.APIKey, conn.Model, conn.BaseURL, conn.Provider
 
 	// Check if bypass mode
 	if bypassMode {
-		return runBypassMode(args, apiKey, model, baseURL, providerName, cfg, noHooksFlag)
+		return runBypassMode(repo, args, apiKey, model, baseURL, providerName, cfg, noHooksFlag)
 	}
 
 	// Interactive mode
-	return runInteractiveMode(apiKey, model, baseURL, providerName, cfg, noHooksFlag)
+	return runInteractiveMode(repo, apiKey, model, baseURL, providerName, cfg, noHooksFlag)
 }
 
-func runBypassMode(files []string, apiKey, model, baseURL, providerName string, cfg *config.Config, hooksDisabled bool) error {
+func runBypassMode(repo *git.Repo, files []string, apiKey, model, baseURL, providerName string, cfg *config.Config, hooksDisabled bool) error {
 	if cfg == nil {
 		cfg = &config.Config{}
 	}
 
-	if len(files) == 0 {
+	if len(files) == 0 && !amendFlag {
 		return fmt.Errorf("no files specified for bypass mode")
 	}
+	if amendFlag && !repo.CanAmend() {
+		return fmt.Errorf("there is no commit to amend")
+	}
 
 	// Stage files
-	if err := git.StageFiles(files); err != nil {
+	if err := repo.StageFiles(files); err != nil {
 		return fmt.Errorf("failed to stage files: %w", err)
 	}
 
-	// Get diff
-	diff, err := git.GetStagedDiff()
+	// Get diff; an amend describes the whole amended commit
+	diff, err := repo.GetStagedDiff()
 	if err != nil {
 		return fmt.Errorf("failed to get staged diff: %w", err)
 	}
+	var lastCommit *git.CommitInfo
+	if amendFlag {
+		if lastCommit, err = repo.GetLastCommit(); err != nil {
+			return err
+		}
+		if diff, err = repo.GetAmendDiff(); err != nil {
+			return err
+		}
+	}
+
+	operation, err := repo.InProgress()
+	if err != nil {
+		return fmt.Errorf("failed to inspect repository state: %w", err)
+	}
+	if operation.InProgress() && amendFlag {
+		return fmt.Errorf("%s: cannot amend until it is finished", strings.ToLower(operation.Title()))
+	}
+	if operation.InProgress() {
+		changes, err := repo.ListChanges()
+		if err != nil {
+			return fmt.Errorf("failed to read changes: %w", err)
+		}
+		for _, change := range changes {
+			if change.Conflict {
+				return fmt.Errorf("%s: resolve the conflict in %s before committing", strings.ToLower(operation.Title()), change.Path)
+			}
+		}
+	}
 
-	if len(diff) == 0 {
+	if len(diff) == 0 && !operation.InProgress() && !amendFlag {
 		return fmt.Errorf("no changes to commit")
 	}
 
@@ -112,45 +113,73 @@ func runBypassMode(files []string, apiKey, model, baseURL, providerName string,
 			return fmt.Errorf("API key for %s not found", providerName)
 		}
 
-		provider := llm.NewGenericProvider(apiKey, model, baseURL)
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
+		provider := llm.NewGenericProvider(apiKey, model, baseURL).WithDir(projectDir(repo))
+		template := cfg.ResolveDefaultTemplate()
+		template = template.WithLanguage(cfg.ResolveLanguage(template, languageOverride(repo)))
+		generate := func(ctx context.Context, history []llm.Message) (string, error) {
+			ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+			defer cancel()
+			return provider.GenerateMessage(ctx, string(diff), history, template)
+		}
 
-		message, err = provider.GenerateMessage(ctx, string(diff), nil, cfg.ResolveDefaultTemplate())
+		var issues []lint.Issue
+		rules := lint.RulesFor(template, config.ReadMemory(projectDir(repo)))
+		history := ui.OperationHistory(operation)
+		if amendFlag {
+			history = ui.AmendHistory(lastCommit)
+		}
+		message, issues, err = lint.GenerateWithRepair(context.Background(), generate, history, rules, cfg.GetLintRepairAttempts())
 		if err != nil {
 			return fmt.Errorf("failed to generate commit message: %w", err)
 		}
+		for _, issue := range issues {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", issue)
+		}
 	}
 
 	hookTimeout := time.Duration(cfg.GetHookTimeoutSeconds()) * time.Second
 	if !hooksDisabled {
 		if err := hooks.Run(context.Background(), hooks.RunOptions{
 			Phase:         hooks.PhasePreCommit,
+			Dir:           repo.Dir(),
 			Commands:      cfg.PreCommitHooks,
 			Timeout:       hookTimeout,
 			CommitMessage: message,
-			IsAmend:       false,
+			IsAmend:       amendFlag,
 		}); err != nil {
 			return fmt.Errorf("pre-commit hook failed: %w", err)
 		}
 	}
 
-	// Commit
-	if err := git.Commit(message); err != nil {
+	// Commit, amend, or conclude the operation in progress
+	switch {
+	case amendFlag:
+		err = repo.AmendCommit(message, commitOptions(cfg))
+	case operation.InProgress():
+		err = repo.ContinueOperation(operation, message, commitOptions(cfg))
+	default:
+		err = repo.Commit(message, commitOptions(cfg))
+	}
+	if err != nil {
 		return fmt.Errorf("failed to commit: %w", err)
 	}
 
 	// Print success
-	fmt.Println("✓ Committed:")
+	if amendFlag {
+		fmt.Println("✓ Amended:")
+	} else {
+		fmt.Println("✓ Committed:")
+	}
 	fmt.Println(message)
 
 	if !hooksDisabled {
 		if err := hooks.Run(context.Background(), hooks.RunOptions{
 			Phase:         hooks.PhasePostCommit,
+			Dir:           repo.Dir(),
 			Commands:      cfg.PostCommitHooks,
 			Timeout:       hookTimeout,
 			CommitMessage: message,
-			IsAmend:       false,
+			IsAmend:       amendFlag,
 		}); err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: post-commit hook failed: %v\n", err)
 		}
@@ -159,25 +188,27 @@ func runBypassMode(files []string, apiKey, model, baseURL, providerName string,
 	return nil
 }
 
-func runInteractiveMode(apiKey, model, baseURL, providerName string, cfg *config.Config, hooksDisabled bool) error {
+func runInteractiveMode(repo *git.Repo, apiKey, model, baseURL, providerName string, cfg *config.Config, hooksDisabled bool) error {
 	// Get staged diff
-	diff, err := git.GetStagedDiff()
+	diff, err := repo.GetStagedDiff()
 	if err != nil {
 		return fmt.Errorf("error: %w", err)
 	}
 
-	var files []git.ChangedFile
-	if len(diff) == 0 {
-		// No staged changes, list unstaged
-		unstaged, err := git.ListUnstagedChanges()
-		if err != nil {
-			return fmt.Errorf("error: %w", err)
-		}
-		if len(unstaged) == 0 {
-			fmt.Println("No changes detected.")
-			return nil
-		}
-		files = unstaged
+	files, err := repo.ListChanges()
+	if err != nil {
+		return fmt.Errorf("error: %w", err)
+	}
+	operation, err := repo.InProgress()
+	if err != nil {
+		return fmt.Errorf("error: %w", err)
+	}
+	if amendFlag && (operation.InProgress() || !repo.CanAmend()) {
+		return fmt.Errorf("there is no commit to amend")
+	}
+	if len(diff) == 0 && len(files) == 0 && !operation.InProgress() && !amendFlag {
+		fmt.Println("No changes detected.")
+		return nil
 	}
 
 	if apiKey == "" {
@@ -185,8 +216,17 @@ func runInteractiveMode(apiKey, model, baseURL, providerName string, cfg *config
 	}
 
 	// Create provider and model
-	provider := llm.NewGenericProvider(apiKey, model, baseURL)
-	m := ui.NewModel(provider, files, string(diff), providerName, model, cfg, hooksDisabled)
+	provider := llm.NewGenericProvider(apiKey, model, baseURL).WithDir(projectDir(repo))
+	m := ui.NewModel(provider, files, string(diff), cfg, ui.Options{
+		Repo:          repo,
+		ProviderName:  providerName,
+		ModelName:     model,
+		HooksDisabled: hooksDisabled,
+		Language:      languageOverride(repo),
+		Commit:        commitOptions(cfg),
+		Operation:     operation,
+		Amend:         amendFlag,
+	})
 
 	// Run TUI
 	p := tea.NewProgram(m)
@@ -196,3 +236,38 @@ func runInteractiveMode(apiKey, model, baseURL, providerName string, cfg *config
 
 	return nil
 }
+
+// languageOverride returns the --lang flag value, falling back to the
+// repository's commiter.language git config.
+func languageOverride(repo *git.Repo) string {
+	if languageFlag != "" {
+		return languageFlag
+	}
+	lang, err := repo.ConfigValue(repoLanguageKey)
+	if err != nil {
+		return ""
+	}
+	return lang
+}
+
+// commitOptions combines the commit defaults from config with the command-line flags.
+func commitOptions(cfg *config.Config) git.CommitOptions {
+	opts := git.CommitOptions{
+		Sign:       cfg.SignCommits,
+		SigningKey: cfg.SigningKey,
+		Signoff:    cfg.Signoff,
+		Author:     authorFlag,
+		Date:       dateFlag,
+		Trailers:   append(append([]string(nil), cfg.CommitTrailers...), trailerFlags...),
+		NoVerify:   noVerifyFlag,
+	}
+
+	flags := rootCmd.PersistentFlags()
+	if flags.Changed("sign") {
+		opts.Sign = signFlag
+	}
+	if flags.Changed("signoff") {
+		opts.Signoff = signoffFlag
+	}
+	return opts
+}
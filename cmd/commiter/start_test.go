@@ -11,6 +11,7 @@ import (
 	"testing"
 
 	"github.com/samcharles93/commiter/internal/config"
+	"github.com/samcharles93/commiter/internal/git"
 )
 
 func TestRunBypassModePreHookFailureBlocksCommit(t *testing.T) {
@@ -32,15 +33,13 @@ func TestRunBypassModePreHookFailureBlocksCommit(t *testing.T) {
 		customMessage = prevCustomMessage
 	})
 
-	withWorkingDir(t, repoDir, func() {
-		err := runBypassMode([]string{"a.txt"}, "", "", "", "openai", cfg, false)
-		if err == nil {
-			t.Fatal("expected pre-hook failure, got nil")
-		}
-		if !strings.Contains(err.Error(), "pre-commit hook failed") {
-			t.Fatalf("expected pre-hook failure error, got: %v", err)
-		}
-	})
+	err := runBypassMode(git.NewRepo(repoDir, nil), []string{"a.txt"}, "", "", "", "openai", cfg, false)
+	if err == nil {
+		t.Fatal("expected pre-hook failure, got nil")
+	}
+	if !strings.Contains(err.Error(), "pre-commit hook failed") {
+		t.Fatalf("expected pre-hook failure error, got: %v", err)
+	}
 
 	if commits := commitCount(t, repoDir); commits != 1 {
 		t.Fatalf("expected no new commit after pre-hook failure, got commit count %d", commits)
@@ -67,12 +66,10 @@ func TestRunBypassModePostHookFailureWarnsOnly(t *testing.T) {
 	})
 
 	stderrOutput := captureStderr(t, func() {
-		withWorkingDir(t, repoDir, func() {
-			err := runBypassMode([]string{"b.txt"}, "", "", "", "openai", cfg, false)
-			if err != nil {
-				t.Fatalf("expected commit success, got error: %v", err)
-			}
-		})
+		err := runBypassMode(git.NewRepo(repoDir, nil), []string{"b.txt"}, "", "", "", "openai", cfg, false)
+		if err != nil {
+			t.Fatalf("expected commit success, got error: %v", err)
+		}
 	})
 
 	if commits := commitCount(t, repoDir); commits != 2 {
@@ -103,17 +100,41 @@ func TestRunBypassModeNoHooksSkipsConfiguredHooks(t *testing.T) {
 		customMessage = prevCustomMessage
 	})
 
-	withWorkingDir(t, repoDir, func() {
-		if err := runBypassMode([]string{"c.txt"}, "", "", "", "openai", cfg, true); err != nil {
-			t.Fatalf("expected commit success with hooks disabled, got: %v", err)
-		}
-	})
+	if err := runBypassMode(git.NewRepo(repoDir, nil), []string{"c.txt"}, "", "", "", "openai", cfg, true); err != nil {
+		t.Fatalf("expected commit success with hooks disabled, got: %v", err)
+	}
 
 	if commits := commitCount(t, repoDir); commits != 2 {
 		t.Fatalf("expected commit success when hooks are disabled, got commit count %d", commits)
 	}
 }
 
+func TestRunBypassModeAmendsMessageOnly(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repoDir := initRepoForBypassTests(t)
+
+	prevCustomMessage, prevAmend := customMessage, amendFlag
+	customMessage = "chore: seed the repository"
+	amendFlag = true
+	t.Cleanup(func() {
+		customMessage, amendFlag = prevCustomMessage, prevAmend
+	})
+
+	if err := runBypassMode(git.NewRepo(repoDir, nil), nil, "", "", "", "openai", &config.Config{}, true); err != nil {
+		t.Fatalf("runBypassMode() error = %v", err)
+	}
+
+	if commits := commitCount(t, repoDir); commits != 1 {
+		t.Fatalf("expected the root commit to be amended, got commit count %d", commits)
+	}
+	if subject := strings.TrimSpace(runGit(t, repoDir, "log", "-1", "--format=%s")); subject != customMessage {
+		t.Fatalf("unexpected subject %q", subject)
+	}
+}
+
 func initRepoForBypassTests(t *testing.T) string {
 	t.Helper()
 
@@ -136,26 +157,6 @@ func writeFile(t *testing.T, path, content string) {
 	}
 }
 
-func withWorkingDir(t *testing.T, dir string, fn func()) {
-	t.Helper()
-
-	original, err := os.Getwd()
-	if err != nil {
-		t.Fatalf("get working directory: %v", err)
-	}
-
-	if err := os.Chdir(dir); err != nil {
-		t.Fatalf("change working directory: %v", err)
-	}
-	defer func() {
-		if err := os.Chdir(original); err != nil {
-			t.Fatalf("restore working directory: %v", err)
-		}
-	}()
-
-	fn()
-}
-
 func runGit(t *testing.T, dir string, args ...string) string {
 	t.Helper()
 
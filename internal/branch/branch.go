@@ -0,0 +1,149 @@
+// Package branch suggests branch names for uncommitted work.
+package branch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/samcharles93/commiter/internal/config"
+	"github.com/samcharles93/commiter/internal/git"
+	"github.com/samcharles93/commiter/internal/llm"
+)
+
+const (
+	// maxDiffChars caps how much of the diff is sent to the model.
+	maxDiffChars = 20000
+	// maxSlugLength keeps names readable in prompts and PR lists.
+	maxSlugLength = 50
+)
+
+const suggestSystemPrompt = `You name git branches for work in progress.
+Pick the change type and a short slug of 2-5 lowercase words joined by
+hyphens that says what the work does, such as "add-csv-export".
+
+Reply with JSON only, in this shape:
+{"type": "feat", "slug": "add-csv-export"}`
+
+// Suggestion is the model's proposal for a branch name.
+type Suggestion struct {
+	Type string `json:"type"`
+	Slug string `json:"slug"`
+}
+
+// Changes returns the uncommitted work to name a branch after: the diff
+// against HEAD (or the staged diff before the first commit) and the paths of
+// untracked files.
+func Changes(repo *git.Repo) (string, error) {
+	diff, err := repo.GetWorkingDiff()
+	if err != nil {
+		if diff, err = repo.GetStagedDiff(); err != nil {
+			return "", err
+		}
+	}
+
+	files, err := repo.ListChanges()
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	b.Write(diff)
+	for _, file := range files {
+		if file.Untracked {
+			fmt.Fprintf(&b, "\nNew untracked file: %s", file.Path)
+		}
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// Suggest asks the model for a branch type and slug. types limits the type to
+// the template's commit types; hint is optional extra context, such as a
+// drafted commit message; memory is the project's MEMORY.md, if any.
+func Suggest(ctx context.Context, completer llm.Completer, diff string, types []string, hint, memory string) (Suggestion, error) {
+	if strings.TrimSpace(diff) == "" {
+		return Suggestion{}, fmt.Errorf("no changes to name a branch after")
+	}
+
+	system := suggestSystemPrompt
+	if len(types) > 0 {
+		system += "\n\nThe type must be one of: " + strings.Join(types, ", ") + "."
+	}
+	if memory != "" {
+		system += "\n\nUser Preferences:\n" + memory
+	}
+
+	if len(diff) > maxDiffChars {
+		diff = diff[:maxDiffChars] + "\n...[truncated]"
+	}
+	prompt := "Suggest a branch name for these changes:\n\n" + diff
+	if hint = strings.TrimSpace(hint); hint != "" {
+		prompt += "\n\nThe drafted commit message is:\n" + hint
+	}
+
+	reply, err := completer.Complete(ctx, []llm.Message{
+		{Role: "system", Content: system},
+		{Role: "user", Content: prompt},
+	})
+	if err != nil {
+		return Suggestion{}, fmt.Errorf("failed to suggest a branch name: %w", err)
+	}
+
+	start := strings.Index(reply, "{")
+	end := strings.LastIndex(reply, "}")
+	if start < 0 || end < start {
+		return Suggestion{}, fmt.Errorf("model did not return a branch name")
+	}
+	var suggestion Suggestion
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &suggestion); err != nil {
+		return Suggestion{}, fmt.Errorf("failed to parse branch name: %w", err)
+	}
+	suggestion.Type = Slugify(suggestion.Type)
+	suggestion.Slug = Slugify(suggestion.Slug)
+	if suggestion.Slug == "" {
+		return Suggestion{}, fmt.Errorf("model returned an empty branch name")
+	}
+	return suggestion, nil
+}
+
+var (
+	placeholderPattern = regexp.MustCompile(`\{(type|ticket|slug)\}`)
+	slugUnsafe         = regexp.MustCompile(`[^a-z0-9]+`)
+	repeatedSeparators = regexp.MustCompile(`([-_.])[-_.]+`)
+)
+
+// Slugify lowercases s and joins its words with hyphens.
+func Slugify(s string) string {
+	slug := strings.Trim(slugUnsafe.ReplaceAllString(strings.ToLower(s), "-"), "-")
+	if len(slug) > maxSlugLength {
+		slug = strings.TrimRight(slug[:maxSlugLength], "-")
+	}
+	return slug
+}
+
+// Name fills a pattern such as "{type}/{ticket}-{slug}". Empty placeholders
+// are dropped along with the separators around them, so without a ticket the
+// example gives "feat/add-csv-export".
+func Name(pattern string, suggestion Suggestion, ticket string) string {
+	if strings.TrimSpace(pattern) == "" {
+		pattern = config.DefaultBranchPattern
+	}
+	values := map[string]string{
+		"type":   suggestion.Type,
+		"ticket": strings.TrimSpace(ticket),
+		"slug":   suggestion.Slug,
+	}
+	name := placeholderPattern.ReplaceAllStringFunc(pattern, func(placeholder string) string {
+		return values[strings.Trim(placeholder, "{}")]
+	})
+
+	var segments []string
+	for _, segment := range strings.Split(name, "/") {
+		segment = repeatedSeparators.ReplaceAllString(segment, "$1")
+		if segment = strings.Trim(segment, "-_. "); segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return strings.Join(segments, "/")
+}
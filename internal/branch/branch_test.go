@@ -0,0 +1,76 @@
+package branch
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/samcharles93/commiter/internal/llm"
+)
+
+type stubCompleter struct {
+	reply    string
+	messages []llm.Message
+}
+
+func (s *stubCompleter) Complete(_ context.Context, messages []llm.Message) (string, error) {
+	s.messages = messages
+	return s.reply, nil
+}
+
+func TestName(t *testing.T) {
+	suggestion := Suggestion{Type: "feat", Slug: "add-csv-export"}
+	tests := []struct {
+		pattern string
+		ticket  string
+		want    string
+	}{
+		{"", "", "feat/add-csv-export"},
+		{"{type}/{ticket}-{slug}", "ABC-123", "feat/ABC-123-add-csv-export"},
+		{"{type}/{ticket}-{slug}", "", "feat/add-csv-export"},
+		{"{ticket}/{slug}", "", "add-csv-export"},
+		{"sam/{type}--{slug}", "", "sam/feat-add-csv-export"},
+	}
+	for _, tt := range tests {
+		if got := Name(tt.pattern, suggestion, tt.ticket); got != tt.want {
+			t.Fatalf("Name(%q, %q) = %q, want %q", tt.pattern, tt.ticket, got, tt.want)
+		}
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	if got := Slugify("  Add CSV export (v2)!  "); got != "add-csv-export-v2" {
+		t.Fatalf("Slugify() = %q", got)
+	}
+	long := Slugify(strings.Repeat("word ", 30))
+	if len(long) > maxSlugLength || strings.HasSuffix(long, "-") {
+		t.Fatalf("expected a trimmed slug of at most %d chars, got %q", maxSlugLength, long)
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	completer := &stubCompleter{reply: "Sure:\n```json\n{\"type\": \"Feat\", \"slug\": \"Add CSV Export\"}\n```"}
+
+	suggestion, err := Suggest(context.Background(), completer, "diff --git a/export.go b/export.go", []string{"feat", "fix"}, "feat: add csv export", "Use British spelling.")
+	if err != nil {
+		t.Fatalf("Suggest() error = %v", err)
+	}
+	if suggestion != (Suggestion{Type: "feat", Slug: "add-csv-export"}) {
+		t.Fatalf("unexpected suggestion %+v", suggestion)
+	}
+	if !strings.Contains(completer.messages[0].Content, "one of: feat, fix") || !strings.Contains(completer.messages[0].Content, "Use British spelling.") {
+		t.Fatalf("expected the template types and memory in the prompt, got %q", completer.messages[0].Content)
+	}
+	if !strings.Contains(completer.messages[1].Content, "feat: add csv export") {
+		t.Fatalf("expected the drafted message as a hint, got %q", completer.messages[1].Content)
+	}
+
+	if _, err := Suggest(context.Background(), completer, "  ", nil, "", ""); err == nil {
+		t.Fatal("expected an error without changes")
+	}
+	completer.reply = `{"type": "feat", "slug": "!!!"}`
+	if _, err := Suggest(context.Background(), completer, "diff", nil, "", ""); err == nil {
+		t.Fatal("expected an error for an empty slug")
+	}
+}
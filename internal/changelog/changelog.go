@@ -0,0 +1,276 @@
+// Package changelog turns Conventional Commits history into
+// Keep a Changelog markdown.
+package changelog
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/samcharles93/commiter/internal/git"
+	"github.com/samcharles93/commiter/internal/lint"
+)
+
+// Unreleased is the version heading for changes that have not been tagged.
+const Unreleased = "Unreleased"
+
+// Header starts a new changelog file.
+const Header = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/).
+`
+
+// sectionTitles maps commit types to section headings, in display order.
+var sectionTitles = []struct{ Type, Title string }{
+	{"feat", "Features"},
+	{"fix", "Fixes"},
+	{"perf", "Performance"},
+	{"refactor", "Refactoring"},
+	{"revert", "Reverts"},
+	{"docs", "Documentation"},
+}
+
+// otherTitle collects every other commit when all types are included.
+const otherTitle = "Other Changes"
+
+// Entry is one commit's line in the changelog.
+type Entry struct {
+	Hash        string
+	Type        string
+	Scope       string
+	Description string
+	// Breaking is the breaking change note; empty if the commit breaks nothing.
+	Breaking string
+}
+
+// Section is a group of entries under one heading.
+type Section struct {
+	Title   string
+	Entries []Entry
+}
+
+// Release is one version's block of the changelog.
+type Release struct {
+	Version string
+	// Date is "YYYY-MM-DD", left empty for unreleased changes.
+	Date     string
+	Breaking []Entry
+	Sections []Section
+}
+
+// Parse reads the entries from commit messages. Messages that do not follow
+// Conventional Commits become entries without a type.
+func Parse(commits []git.CommitMessage) []Entry {
+	var entries []Entry
+	for _, commit := range commits {
+		subject, body, _ := strings.Cut(strings.TrimSpace(commit.Message), "\n")
+		if subject == "" || strings.HasPrefix(subject, "fixup! ") || strings.HasPrefix(subject, "squash! ") {
+			continue
+		}
+
+		entry := Entry{Hash: commit.Hash, Description: subject}
+		if header, ok := lint.ParseHeader(subject); ok {
+			entry.Type = header.Type
+			entry.Scope = header.Scope
+			entry.Description = header.Description
+			if header.Breaking {
+				entry.Breaking = header.Description
+			}
+		}
+		if note := breakingNote(body); note != "" {
+			entry.Breaking = note
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// breakingNote returns the text of a BREAKING CHANGE footer, which runs until
+// a blank line.
+func breakingNote(body string) string {
+	var note []string
+	inNote := false
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if !inNote {
+			for _, token := range []string{"BREAKING CHANGE:", "BREAKING-CHANGE:"} {
+				if rest, ok := strings.CutPrefix(trimmed, token); ok {
+					inNote = true
+					note = append(note, strings.TrimSpace(rest))
+				}
+			}
+			continue
+		}
+		if trimmed == "" {
+			break
+		}
+		note = append(note, trimmed)
+	}
+	return strings.TrimSpace(strings.Join(note, " "))
+}
+
+// Build groups entries into a release. Entries are expected oldest first and
+// are listed newest first within each section, ordered by scope. Commit types
+// without a section are left out unless all is set.
+func Build(version, date string, entries []Entry, all bool) Release {
+	release := Release{Version: version, Date: date}
+
+	byTitle := map[string][]Entry{}
+	for _, entry := range slices.Backward(entries) {
+		if entry.Breaking != "" {
+			release.Breaking = append(release.Breaking, entry)
+		}
+		title := sectionTitle(entry.Type)
+		if title == "" {
+			if !all {
+				continue
+			}
+			title = otherTitle
+		}
+		byTitle[title] = append(byTitle[title], entry)
+	}
+
+	titles := make([]string, 0, len(sectionTitles)+1)
+	for _, section := range sectionTitles {
+		titles = append(titles, section.Title)
+	}
+	titles = append(titles, otherTitle)
+
+	for _, title := range titles {
+		entries := byTitle[title]
+		if len(entries) == 0 {
+			continue
+		}
+		// Unscoped entries first, then by scope; the sort is stable so each
+		// scope keeps the newest-first order.
+		slices.SortStableFunc(entries, func(a, b Entry) int {
+			return cmp.Compare(strings.ToLower(a.Scope), strings.ToLower(b.Scope))
+		})
+		release.Sections = append(release.Sections, Section{Title: title, Entries: entries})
+	}
+	return release
+}
+
+func sectionTitle(commitType string) string {
+	for _, section := range sectionTitles {
+		if section.Type == commitType {
+			return section.Title
+		}
+	}
+	return ""
+}
+
+// Empty reports whether the release has nothing to list.
+func (r Release) Empty() bool {
+	return len(r.Breaking) == 0 && len(r.Sections) == 0
+}
+
+// Heading returns the release's "## [version] - date" line.
+func (r Release) Heading() string {
+	heading := "## [" + r.Version + "]"
+	if r.Date != "" {
+		heading += " - " + r.Date
+	}
+	return heading
+}
+
+// Markdown renders the release block.
+func (r Release) Markdown() string {
+	var b strings.Builder
+	b.WriteString(r.Heading() + "\n")
+
+	if r.Empty() {
+		b.WriteString("\nNo notable changes.\n")
+		return b.String()
+	}
+
+	if len(r.Breaking) > 0 {
+		b.WriteString("\n### BREAKING CHANGES\n\n")
+		for _, entry := range r.Breaking {
+			b.WriteString(formatLine(entry, entry.Breaking))
+		}
+	}
+	for _, section := range r.Sections {
+		fmt.Fprintf(&b, "\n### %s\n\n", section.Title)
+		for _, entry := range section.Entries {
+			b.WriteString(formatLine(entry, entry.Description))
+		}
+	}
+	return b.String()
+}
+
+func formatLine(entry Entry, text string) string {
+	line := "- "
+	if entry.Scope != "" {
+		line += "**" + entry.Scope + ":** "
+	}
+	line += text
+	if len(entry.Hash) >= 7 {
+		line += " (" + entry.Hash[:7] + ")"
+	}
+	return line + "\n"
+}
+
+// Prepend adds the release to an existing changelog. A section for the same
+// version is replaced, so running it again gives the same file. Otherwise the
+// release goes above the newest version (below any Unreleased section), or
+// after the header of a new file.
+func Prepend(existing string, release Release) string {
+	block := release.Markdown()
+	if strings.TrimSpace(existing) == "" {
+		return Header + "\n" + block
+	}
+
+	lines := strings.SplitAfter(existing, "\n")
+	first, start := -1, -1
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "## ") {
+			continue
+		}
+		version := headingVersion(line)
+		// Unreleased changes stay at the top, above every version.
+		if first < 0 && (release.Version == Unreleased || !strings.EqualFold(version, Unreleased)) {
+			first = i
+		}
+		if version == release.Version {
+			start = i
+			break
+		}
+	}
+
+	if start < 0 {
+		if first < 0 {
+			return strings.TrimRight(existing, "\n") + "\n\n" + block
+		}
+		// Insert above the newest existing version.
+		return strings.Join(lines[:first], "") + block + "\n" + strings.Join(lines[first:], "")
+	}
+
+	end := len(lines)
+	for i := start + 1; i < len(lines); i++ {
+		if strings.HasPrefix(lines[i], "## ") {
+			end = i
+			break
+		}
+	}
+	rest := strings.Join(lines[end:], "")
+	if rest != "" {
+		block += "\n"
+	}
+	return strings.Join(lines[:start], "") + block + rest
+}
+
+// headingVersion reads the version from "## [1.2.0] - 2024-01-01" or
+// "## 1.2.0".
+func headingVersion(line string) string {
+	heading := strings.TrimSpace(strings.TrimPrefix(line, "## "))
+	if rest, ok := strings.CutPrefix(heading, "["); ok {
+		version, _, _ := strings.Cut(rest, "]")
+		return version
+	}
+	version, _, _ := strings.Cut(heading, " ")
+	return version
+}
@@ -0,0 +1,129 @@
+package changelog
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/samcharles93/commiter/internal/git"
+	"github.com/samcharles93/commiter/internal/llm"
+)
+
+type stubCompleter struct {
+	reply    string
+	messages []llm.Message
+}
+
+func (s *stubCompleter) Complete(_ context.Context, messages []llm.Message) (string, error) {
+	s.messages = messages
+	return s.reply, nil
+}
+
+func sampleCommits() []git.CommitMessage {
+	return []git.CommitMessage{
+		{Hash: "1111111aaaa", Message: "feat(ui): add diff viewer"},
+		{Hash: "2222222bbbb", Message: "fix: handle empty repos"},
+		{Hash: "3333333cccc", Message: "chore: bump deps"},
+		{Hash: "4444444dddd", Message: "feat(api)!: drop v1 endpoints"},
+		{Hash: "5555555eeee", Message: "feat: add config export\n\nBREAKING CHANGE: the config file moved\nto the XDG directory.\n\nRefs: #12"},
+		{Hash: "6666666ffff", Message: "fixup! feat: add config export"},
+		{Hash: "7777777aaaa", Message: "Update readme"},
+	}
+}
+
+func TestParseReadsHeadersAndBreakingNotes(t *testing.T) {
+	entries := Parse(sampleCommits())
+	if len(entries) != 6 {
+		t.Fatalf("expected fixups to be skipped, got %d entries", len(entries))
+	}
+	if entries[0].Type != "feat" || entries[0].Scope != "ui" || entries[0].Description != "add diff viewer" {
+		t.Fatalf("unexpected entry: %+v", entries[0])
+	}
+	if entries[3].Breaking != "drop v1 endpoints" {
+		t.Fatalf("expected the ! marker to mark a breaking change, got %+v", entries[3])
+	}
+	if entries[4].Breaking != "the config file moved to the XDG directory." {
+		t.Fatalf("expected the footer note, got %q", entries[4].Breaking)
+	}
+	if entries[5].Type != "" || entries[5].Description != "Update readme" {
+		t.Fatalf("expected a plain entry for a non-conventional commit, got %+v", entries[5])
+	}
+}
+
+func TestBuildRendersKeepAChangelogMarkdown(t *testing.T) {
+	release := Build("1.2.0", "2026-01-02", Parse(sampleCommits()), false)
+
+	want := `## [1.2.0] - 2026-01-02
+
+### BREAKING CHANGES
+
+- the config file moved to the XDG directory. (5555555)
+- **api:** drop v1 endpoints (4444444)
+
+### Features
+
+- add config export (5555555)
+- **api:** drop v1 endpoints (4444444)
+- **ui:** add diff viewer (1111111)
+
+### Fixes
+
+- handle empty repos (2222222)
+`
+	if got := release.Markdown(); got != want {
+		t.Fatalf("Markdown() =\n%s\nwant\n%s", got, want)
+	}
+
+	all := Build(Unreleased, "", Parse(sampleCommits()), true)
+	if got := all.Markdown(); !strings.Contains(got, "### Other Changes\n\n- Update readme (7777777)\n- bump deps (3333333)\n") {
+		t.Fatalf("expected other types with --all:\n%s", got)
+	}
+}
+
+func TestPrependIsIdempotent(t *testing.T) {
+	release := Build(Unreleased, "", Parse(sampleCommits()[:2]), false)
+
+	created := Prepend("", release)
+	if !strings.HasPrefix(created, Header+"\n## [Unreleased]\n") {
+		t.Fatalf("expected a new file with the header:\n%s", created)
+	}
+	if again := Prepend(created, release); again != created {
+		t.Fatalf("expected a second run to change nothing:\n%s", again)
+	}
+
+	existing := Header + "\n## [Unreleased]\n\n- hand-written note\n\n## [1.0.0] - 2025-01-01\n\n- first release\n"
+	updated := Prepend(existing, release)
+	if strings.Contains(updated, "hand-written note") || !strings.Contains(updated, "## [1.0.0] - 2025-01-01\n\n- first release\n") {
+		t.Fatalf("expected only the Unreleased section to be replaced:\n%s", updated)
+	}
+
+	versioned := Prepend(existing, Build("1.1.0", "2025-06-01", Parse(sampleCommits()[:1]), false))
+	unreleased := strings.Index(versioned, "## [Unreleased]")
+	added := strings.Index(versioned, "## [1.1.0]")
+	previous := strings.Index(versioned, "## [1.0.0]")
+	if unreleased < 0 || !(unreleased < added && added < previous) {
+		t.Fatalf("expected the new version between Unreleased and 1.0.0:\n%s", versioned)
+	}
+}
+
+func TestRewriteReplacesEntryText(t *testing.T) {
+	release := Build("1.2.0", "", Parse(sampleCommits()), false)
+	completer := &stubCompleter{reply: "```json\n" + `{"entries": [{"id": "b1", "text": "Settings now live in your XDG config folder"}, {"id": "e3", "text": "See changes side by side"}, {"id": "x9", "text": "ignored"}]}` + "\n```"}
+
+	if err := Rewrite(context.Background(), completer, &release, "de"); err != nil {
+		t.Fatalf("Rewrite() error = %v", err)
+	}
+
+	markdown := release.Markdown()
+	if !strings.Contains(markdown, "- Settings now live in your XDG config folder (5555555)") ||
+		!strings.Contains(markdown, "- **ui:** See changes side by side (1111111)") ||
+		!strings.Contains(markdown, "- handle empty repos (2222222)") {
+		t.Fatalf("unexpected rewritten release:\n%s", markdown)
+	}
+	if prompt := completer.messages[1].Content; !strings.Contains(prompt, "e3 (ui): add diff viewer") {
+		t.Fatalf("unexpected prompt:\n%s", prompt)
+	}
+	if !strings.Contains(completer.messages[0].Content, "German") {
+		t.Fatal("expected the language instruction in the system prompt")
+	}
+}
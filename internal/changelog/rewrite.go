@@ -0,0 +1,92 @@
+package changelog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/samcharles93/commiter/internal/config"
+	"github.com/samcharles93/commiter/internal/llm"
+)
+
+const rewriteSystemPrompt = `You edit changelog entries for the people who use a piece of software.
+Each entry was taken from a commit message. Rewrite it as a short, user-facing
+sentence: say what changed for the user, drop implementation details and
+internal names, and keep it to one line without a trailing period.
+
+Reply with JSON only, in this shape:
+{"entries": [{"id": "e1", "text": "rewritten entry"}]}
+Keep every id; return the original text when it is already clear.`
+
+type rewriteReply struct {
+	Entries []struct {
+		ID   string `json:"id"`
+		Text string `json:"text"`
+	} `json:"entries"`
+}
+
+// Rewrite asks the model to turn the release's entries into user-facing
+// language. Entries the model leaves out keep their text.
+func Rewrite(ctx context.Context, completer llm.Completer, release *Release, language string) error {
+	if release.Empty() {
+		return nil
+	}
+
+	// Every line of the release gets an id: "b" for breaking notes and "e" for
+	// section entries.
+	texts := map[string]*string{}
+	var prompt strings.Builder
+	prompt.WriteString("Rewrite these changelog entries:\n")
+	add := func(id, scope string, text *string) {
+		texts[id] = text
+		if scope != "" {
+			fmt.Fprintf(&prompt, "%s (%s): %s\n", id, scope, *text)
+		} else {
+			fmt.Fprintf(&prompt, "%s: %s\n", id, *text)
+		}
+	}
+	for i := range release.Breaking {
+		entry := &release.Breaking[i]
+		add(fmt.Sprintf("b%d", i+1), entry.Scope, &entry.Breaking)
+	}
+	n := 0
+	for s := range release.Sections {
+		for i := range release.Sections[s].Entries {
+			n++
+			entry := &release.Sections[s].Entries[i]
+			add(fmt.Sprintf("e%d", n), entry.Scope, &entry.Description)
+		}
+	}
+
+	system := rewriteSystemPrompt
+	if language != "" && !config.IsEnglish(language) {
+		system += fmt.Sprintf("\n\nWrite the entries in %s.", config.LanguageName(language))
+	}
+
+	reply, err := completer.Complete(ctx, []llm.Message{
+		{Role: "system", Content: system},
+		{Role: "user", Content: prompt.String()},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to rewrite changelog: %w", err)
+	}
+
+	start := strings.Index(reply, "{")
+	end := strings.LastIndex(reply, "}")
+	if start < 0 || end < start {
+		return fmt.Errorf("model did not return changelog entries")
+	}
+	var parsed rewriteReply
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &parsed); err != nil {
+		return fmt.Errorf("failed to parse changelog entries: %w", err)
+	}
+
+	for _, entry := range parsed.Entries {
+		text := strings.TrimSpace(entry.Text)
+		if target, ok := texts[strings.TrimSpace(entry.ID)]; ok && text != "" && !strings.Contains(text, "\n") {
+			*target = text
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+// Package clipboard copies text to the system clipboard. Over SSH, or where no
+// clipboard tool is installed, it asks the terminal to do it with an OSC52
+// escape sequence instead.
+package clipboard
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	native "github.com/atotto/clipboard"
+	"github.com/aymanbagabas/go-osc52/v2"
+)
+
+// Method is how text reached the clipboard.
+type Method string
+
+const (
+	// Native means a system clipboard tool (pbcopy, xclip, wl-copy, ...) took
+	// the text.
+	Native Method = "clipboard"
+	// OSC52 means the text was sent to the terminal, which copies it if it
+	// supports the sequence; there is no way to confirm that it did.
+	OSC52 Method = "OSC52"
+)
+
+var (
+	writeNative  = native.WriteAll
+	openTerminal = func() (io.WriteCloser, error) {
+		return os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+	}
+)
+
+// Copy puts text on the clipboard and reports how.
+func Copy(text string) (Method, error) {
+	if !remote() && !native.Unsupported {
+		if err := writeNative(text); err == nil {
+			return Native, nil
+		}
+	}
+
+	seq := osc52.New(text)
+	switch {
+	case os.Getenv("TMUX") != "":
+		seq = seq.Tmux()
+	case strings.HasPrefix(os.Getenv("TERM"), "screen"):
+		seq = seq.Screen()
+	}
+
+	// Write to the controlling terminal so the sequence neither lands in
+	// redirected output nor interleaves with a running TUI's frames.
+	var out io.Writer = os.Stderr
+	if tty, err := openTerminal(); err == nil {
+		defer tty.Close()
+		out = tty
+	}
+	if _, err := seq.WriteTo(out); err != nil {
+		return "", fmt.Errorf("failed to copy to the clipboard: %w", err)
+	}
+	return OSC52, nil
+}
+
+// remote reports whether this is an SSH session, where the local clipboard
+// belongs to the wrong machine.
+func remote() bool {
+	return os.Getenv("SSH_TTY") != "" || os.Getenv("SSH_CONNECTION") != ""
+}
+
+// Status is a short confirmation for what was copied, such as
+// "✓ Copied hash" or "✓ Copied hash via the terminal (OSC52)".
+func Status(what string, method Method) string {
+	if method == OSC52 {
+		return "✓ Copied " + what + " via the terminal (OSC52)"
+	}
+	return "✓ Copied " + what
+}
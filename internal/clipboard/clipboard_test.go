@@ -0,0 +1,96 @@
+package clipboard
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	native "github.com/atotto/clipboard"
+)
+
+type nopCloser struct{ *bytes.Buffer }
+
+func (nopCloser) Close() error { return nil }
+
+func stubClipboard(t *testing.T, nativeErr error) (*bytes.Buffer, *string) {
+	t.Helper()
+	var tty bytes.Buffer
+	var copied string
+	oldNative, oldTerminal := writeNative, openTerminal
+	t.Cleanup(func() { writeNative, openTerminal = oldNative, oldTerminal })
+	writeNative = func(text string) error {
+		copied = text
+		return nativeErr
+	}
+	openTerminal = func() (io.WriteCloser, error) { return nopCloser{&tty}, nil }
+	for _, key := range []string{"SSH_TTY", "SSH_CONNECTION", "TMUX", "TERM"} {
+		t.Setenv(key, "")
+	}
+	return &tty, &copied
+}
+
+func TestCopyUsesOSC52OverSSH(t *testing.T) {
+	tty, copied := stubClipboard(t, nil)
+	t.Setenv("SSH_CONNECTION", "10.0.0.1 22 10.0.0.2 22")
+
+	method, err := Copy("abc123")
+	if err != nil || method != OSC52 {
+		t.Fatalf("Copy() = %q, %v; want OSC52", method, err)
+	}
+	if *copied != "" {
+		t.Fatalf("expected the native clipboard to be skipped over SSH, got %q", *copied)
+	}
+	encoded := base64.StdEncoding.EncodeToString([]byte("abc123"))
+	if got := tty.String(); got != "\x1b]52;c;"+encoded+"\x07" {
+		t.Fatalf("unexpected sequence %q", got)
+	}
+}
+
+func TestCopyWrapsOSC52ForTmux(t *testing.T) {
+	tty, _ := stubClipboard(t, nil)
+	t.Setenv("SSH_TTY", "/dev/pts/1")
+	t.Setenv("TMUX", "/tmp/tmux-1000/default,1,0")
+
+	if _, err := Copy("abc123"); err != nil {
+		t.Fatalf("Copy() error = %v", err)
+	}
+	if got := tty.String(); !strings.HasPrefix(got, "\x1bPtmux;") {
+		t.Fatalf("expected a tmux passthrough sequence, got %q", got)
+	}
+}
+
+func TestCopyFallsBackWhenNativeFails(t *testing.T) {
+	if native.Unsupported {
+		t.Skip("no native clipboard tool installed")
+	}
+	tty, copied := stubClipboard(t, errors.New("no display"))
+
+	method, err := Copy("abc123")
+	if err != nil || method != OSC52 || *copied != "abc123" || tty.Len() == 0 {
+		t.Fatalf("Copy() = %q, %v; native got %q, tty %q", method, err, *copied, tty.String())
+	}
+}
+
+func TestCopyPrefersNative(t *testing.T) {
+	if native.Unsupported {
+		t.Skip("no native clipboard tool installed")
+	}
+	tty, copied := stubClipboard(t, nil)
+
+	method, err := Copy("abc123")
+	if err != nil || method != Native || *copied != "abc123" || tty.Len() != 0 {
+		t.Fatalf("Copy() = %q, %v; native got %q, tty %q", method, err, *copied, tty.String())
+	}
+}
+
+func TestStatus(t *testing.T) {
+	if got := Status("hash", Native); got != "✓ Copied hash" {
+		t.Fatalf("unexpected status %q", got)
+	}
+	if got := Status("hash", OSC52); got != "✓ Copied hash via the terminal (OSC52)" {
+		t.Fatalf("unexpected status %q", got)
+	}
+}
@@ -11,11 +11,19 @@ import (
 
 const (
 	ConfigFileName            = ".commiter.json"
+	MemoryFileName            = "MEMORY.md"
+	SystemPromptFileName      = "SYSTEM.md"
 	DefaultHookTimeoutSeconds = 30
+	DefaultLintRepairAttempts = 2
+	DefaultSubjectMaxLength   = 72
+	DefaultBodyWrapWidth      = 72
+	DefaultBranchPattern      = "{type}/{slug}"
 )
 
-// Load loads the configuration from disk
-func Load() (*Config, error) {
+// Load loads the configuration from disk, preferring the project's
+// .commiter.json in dir (the repository root; empty means the working
+// directory) over the one in the home directory.
+func Load(dir string) (*Config, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return nil, err
@@ -23,7 +31,7 @@ func Load() (*Config, error) {
 
 	homePath := filepath.Join(home, ConfigFileName)
 	paths := []string{
-		ConfigFileName,
+		filepath.Join(dir, ConfigFileName),
 		homePath,
 	}
 
@@ -110,6 +118,46 @@ func (c *Config) GetHookTimeoutSeconds() int {
 	return c.HookTimeoutSeconds
 }
 
+// GetLintRepairAttempts safely returns how many corrective follow-ups may be
+// sent when a generated message violates template rules.
+func (c *Config) GetLintRepairAttempts() int {
+	if c == nil || c.LintRepairAttempts == nil {
+		return DefaultLintRepairAttempts
+	}
+	if *c.LintRepairAttempts < 0 {
+		return 0
+	}
+	return *c.LintRepairAttempts
+}
+
+// GetBranchPattern returns the pattern for suggested branch names.
+func (c *Config) GetBranchPattern() string {
+	if c == nil || strings.TrimSpace(c.BranchPattern) == "" {
+		return DefaultBranchPattern
+	}
+	return c.BranchPattern
+}
+
+// ReadMemory returns the user preference notes from MEMORY.md in dir, if
+// present.
+func ReadMemory(dir string) string {
+	data, err := os.ReadFile(filepath.Join(dir, MemoryFileName))
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// ReadSystemPrompt returns the system prompt from SYSTEM.md in dir, if
+// present.
+func ReadSystemPrompt(dir string) string {
+	data, err := os.ReadFile(filepath.Join(dir, SystemPromptFileName))
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
 // FindTemplate returns a template by name.
 func (c *Config) FindTemplate(name string) *CommitTemplate {
 	if c == nil {
@@ -153,6 +201,22 @@ func (t CommitTemplate) ConfigValue() string {
 	return normalizeTemplateValue(t.Name)
 }
 
+// GetSubjectMaxLength returns the subject length limit for this template.
+func (t CommitTemplate) GetSubjectMaxLength() int {
+	if t.SubjectMaxLength <= 0 {
+		return DefaultSubjectMaxLength
+	}
+	return t.SubjectMaxLength
+}
+
+// GetBodyWrapWidth returns the body wrap width for this template.
+func (t CommitTemplate) GetBodyWrapWidth() int {
+	if t.BodyWrapWidth <= 0 {
+		return DefaultBodyWrapWidth
+	}
+	return t.BodyWrapWidth
+}
+
 // DisplayName returns the template label shown in the UI.
 func (t CommitTemplate) DisplayName() string {
 	if strings.TrimSpace(t.Name) != "" {
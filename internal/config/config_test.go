@@ -29,7 +29,7 @@ func TestLoadAndSaveUseLoadedConfigPath(t *testing.T) {
 		_ = os.Chdir(originalWD)
 	})
 
-	cfg, err := Load()
+	cfg, err := Load("")
 	if err != nil {
 		t.Fatalf("load config: %v", err)
 	}
@@ -128,3 +128,51 @@ func setHomeForConfigTest(t *testing.T, home string) {
 		}
 	})
 }
+
+func TestGetLintRepairAttemptsDefaultsAndOverrides(t *testing.T) {
+	cfg := &Config{}
+	if got := cfg.GetLintRepairAttempts(); got != DefaultLintRepairAttempts {
+		t.Fatalf("expected default %d, got %d", DefaultLintRepairAttempts, got)
+	}
+
+	disabled := 0
+	cfg.LintRepairAttempts = &disabled
+	if got := cfg.GetLintRepairAttempts(); got != 0 {
+		t.Fatalf("expected repairs to be disabled, got %d", got)
+	}
+}
+
+func TestResolveLanguagePrecedence(t *testing.T) {
+	cfg := &Config{Language: "fr"}
+	tmpl := &CommitTemplate{Key: "simple", Language: "es"}
+
+	if got := cfg.ResolveLanguage(nil); got != "fr" {
+		t.Fatalf("expected global language, got %q", got)
+	}
+	if got := cfg.ResolveLanguage(tmpl); got != "es" {
+		t.Fatalf("expected template language to beat global, got %q", got)
+	}
+	if got := cfg.ResolveLanguage(tmpl, "", "de"); got != "de" {
+		t.Fatalf("expected repository override to beat template, got %q", got)
+	}
+	if got := cfg.ResolveLanguage(tmpl, "it", "de"); got != "it" {
+		t.Fatalf("expected flag override to win, got %q", got)
+	}
+}
+
+func TestLanguageName(t *testing.T) {
+	tests := map[string]string{
+		"":        "English",
+		"de":      "German",
+		"de-AT":   "German",
+		"Klingon": "Klingon",
+	}
+	for input, want := range tests {
+		if got := LanguageName(input); got != want {
+			t.Fatalf("LanguageName(%q) = %q, want %q", input, got, want)
+		}
+	}
+	if IsEnglish("de") || !IsEnglish("en-GB") {
+		t.Fatal("unexpected IsEnglish result")
+	}
+}
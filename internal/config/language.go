@@ -0,0 +1,80 @@
+package config
+
+import "strings"
+
+// languageNames maps common language codes to the names used in prompts.
+var languageNames = map[string]string{
+	"en": "English",
+	"de": "German",
+	"fr": "French",
+	"es": "Spanish",
+	"it": "Italian",
+	"nl": "Dutch",
+	"pt": "Portuguese",
+	"pl": "Polish",
+	"sv": "Swedish",
+	"da": "Danish",
+	"no": "Norwegian",
+	"fi": "Finnish",
+	"cs": "Czech",
+	"ru": "Russian",
+	"uk": "Ukrainian",
+	"tr": "Turkish",
+	"ja": "Japanese",
+	"ko": "Korean",
+	"zh": "Chinese",
+}
+
+// LanguageName returns a human-readable language name for a code such as
+// "de" or "de-AT". Unknown values are returned unchanged.
+func LanguageName(lang string) string {
+	lang = strings.TrimSpace(lang)
+	if lang == "" {
+		return "English"
+	}
+	code := strings.ToLower(lang)
+	if idx := strings.IndexAny(code, "-_"); idx > 0 {
+		code = code[:idx]
+	}
+	if name, ok := languageNames[code]; ok {
+		return name
+	}
+	return lang
+}
+
+// IsEnglish reports whether lang is empty or refers to English.
+func IsEnglish(lang string) bool {
+	return LanguageName(lang) == "English" || strings.EqualFold(strings.TrimSpace(lang), "english")
+}
+
+// ResolveLanguage picks the commit message language. The first non-empty
+// override wins (CLI flag, then repository setting), followed by the
+// template's language and finally the global setting.
+func (c *Config) ResolveLanguage(tmpl *CommitTemplate, overrides ...string) string {
+	for _, lang := range overrides {
+		if lang = strings.TrimSpace(lang); lang != "" {
+			return lang
+		}
+	}
+	if tmpl != nil && strings.TrimSpace(tmpl.Language) != "" {
+		return strings.TrimSpace(tmpl.Language)
+	}
+	if c != nil {
+		return strings.TrimSpace(c.Language)
+	}
+	return ""
+}
+
+// WithLanguage returns a copy of the template that targets lang. A nil
+// template yields a prompt-less template carrying only the language.
+func (t *CommitTemplate) WithLanguage(lang string) *CommitTemplate {
+	if t == nil {
+		if lang == "" {
+			return nil
+		}
+		return &CommitTemplate{Language: lang}
+	}
+	clone := *t
+	clone.Language = lang
+	return &clone
+}
@@ -7,19 +7,30 @@ type Config struct {
 	BaseURL            string           `json:"base_url"`
 	Provider           string           `json:"provider"`
 	DefaultTemplate    string           `json:"default_template,omitempty"`
+	Language           string           `json:"language,omitempty"`
 	ConfirmQuit        *bool            `json:"confirm_quit,omitempty"`
 	Templates          []CommitTemplate `json:"templates,omitempty"`
 	PreCommitHooks     []string         `json:"pre_commit_hooks,omitempty"`
 	PostCommitHooks    []string         `json:"post_commit_hooks,omitempty"`
 	HookTimeoutSeconds int              `json:"hook_timeout_seconds,omitempty"`
+	LintRepairAttempts *int             `json:"lint_repair_attempts,omitempty"`
+	SignCommits        bool             `json:"sign_commits,omitempty"`
+	SigningKey         string           `json:"signing_key,omitempty"`
+	Signoff            bool             `json:"signoff,omitempty"`
+	CommitTrailers     []string         `json:"commit_trailers,omitempty"`
+	PRBaseBranch       string           `json:"pr_base_branch,omitempty"`
+	BranchPattern      string           `json:"branch_pattern,omitempty"`
 	sourcePath         string           `json:"-"`
 }
 
 // CommitTemplate represents a commit message template
 type CommitTemplate struct {
-	Key    string   `json:"key,omitempty"`
-	Name   string   `json:"name"`
-	Types  []string `json:"types,omitempty"`
-	Format string   `json:"format"`
-	Prompt string   `json:"prompt"`
+	Key              string   `json:"key,omitempty"`
+	Name             string   `json:"name"`
+	Types            []string `json:"types,omitempty"`
+	Format           string   `json:"format"`
+	Prompt           string   `json:"prompt"`
+	SubjectMaxLength int      `json:"subject_max_length,omitempty"`
+	BodyWrapWidth    int      `json:"body_wrap_width,omitempty"`
+	Language         string   `json:"language,omitempty"`
 }
@@ -0,0 +1,90 @@
+package git
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const authorHistoryDepth = 1000
+
+// Author is a commit author seen in the repository history.
+type Author struct {
+	Name  string
+	Email string
+	// PathCommits counts commits that touched the paths passed to RecentAuthors.
+	PathCommits int
+	Commits     int
+}
+
+// String formats the author as "Name <email>".
+func (a Author) String() string {
+	return fmt.Sprintf("%s <%s>", a.Name, a.Email)
+}
+
+// RecentAuthors returns the distinct authors of recent commits, ranked by how
+// often they touched paths and then by overall activity. The current user is
+// left out.
+func (r *Repo) RecentAuthors(paths []string) ([]Author, error) {
+	if !r.CanAmend() {
+		return nil, nil
+	}
+
+	authors := map[string]*Author{}
+	var order []string
+	count := func(args []string, pathScoped bool) error {
+		out, err := r.run(args...)
+		if err != nil {
+			return fmt.Errorf("failed to read commit authors: %w", err)
+		}
+		for _, line := range strings.Split(string(out), "\n") {
+			name, email, ok := strings.Cut(line, "\x00")
+			if !ok || strings.TrimSpace(email) == "" {
+				continue
+			}
+			key := strings.ToLower(strings.TrimSpace(email))
+			author, exists := authors[key]
+			if !exists {
+				author = &Author{Name: strings.TrimSpace(name), Email: strings.TrimSpace(email)}
+				authors[key] = author
+				order = append(order, key)
+			}
+			if pathScoped {
+				author.PathCommits++
+			} else {
+				author.Commits++
+			}
+		}
+		return nil
+	}
+
+	logArgs := []string{"log", fmt.Sprintf("--max-count=%d", authorHistoryDepth), "--format=%an%x00%ae"}
+	if err := count(logArgs, false); err != nil {
+		return nil, err
+	}
+	if len(paths) > 0 {
+		scoped := append(append(slices.Clone(logArgs), "--"), paths...)
+		if err := count(scoped, true); err != nil {
+			return nil, err
+		}
+	}
+
+	self, _ := r.ConfigValue("user.email")
+	self = strings.ToLower(self)
+
+	ranked := make([]Author, 0, len(order))
+	for _, key := range order {
+		if key == self {
+			continue
+		}
+		ranked = append(ranked, *authors[key])
+	}
+	slices.SortStableFunc(ranked, func(a, b Author) int {
+		if c := cmp.Compare(b.PathCommits, a.PathCommits); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.Commits, a.Commits)
+	})
+	return ranked, nil
+}
@@ -0,0 +1,49 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecentAuthorsRanksByTouchedPaths(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repoDir := t.TempDir()
+	runGit(t, repoDir, "init")
+	runGit(t, repoDir, "config", "user.email", "me@example.com")
+	runGit(t, repoDir, "config", "user.name", "Me")
+
+	commitAs := func(author, file, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repoDir, file), []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", file, err)
+		}
+		runGit(t, repoDir, "add", file)
+		runGit(t, repoDir, "commit", "-m", "chore: update "+file, "--author", author)
+	}
+
+	commitAs("Busy Bee <busy@example.com>", "other.txt", "1\n")
+	commitAs("Busy Bee <busy@example.com>", "other.txt", "2\n")
+	commitAs("Busy Bee <BUSY@example.com>", "other.txt", "3\n")
+	commitAs("Ui Owner <ui@example.com>", "ui.go", "1\n")
+	commitAs("Me <me@example.com>", "ui.go", "2\n")
+
+	authors, err := NewRepo(repoDir, nil).RecentAuthors([]string{"ui.go"})
+	if err != nil {
+		t.Fatalf("RecentAuthors returned error: %v", err)
+	}
+	if len(authors) != 2 {
+		t.Fatalf("expected two deduplicated authors without the current user, got %+v", authors)
+	}
+	if authors[0].Email != "ui@example.com" || authors[0].PathCommits != 1 {
+		t.Fatalf("expected the author of the touched file first, got %+v", authors)
+	}
+	if !strings.EqualFold(authors[1].Email, "busy@example.com") || authors[1].Commits != 3 {
+		t.Fatalf("expected case-insensitive dedupe of busy@example.com, got %+v", authors[1])
+	}
+}
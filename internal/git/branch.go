@@ -0,0 +1,70 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CurrentBranch returns the name of the checked out branch, or "" when HEAD
+// is detached.
+func (r *Repo) CurrentBranch() (string, error) {
+	out, err := r.run("symbolic-ref", "--quiet", "--short", "HEAD")
+	if err != nil {
+		if _, headErr := r.run("rev-parse", "--verify", "--quiet", "HEAD"); headErr == nil {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read the current branch: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// DefaultBranch guesses the branch pull requests target: the remote's HEAD
+// when known, otherwise main or master.
+func (r *Repo) DefaultBranch() (string, error) {
+	if out, err := r.run("symbolic-ref", "--quiet", "--short", "refs/remotes/origin/HEAD"); err == nil {
+		return strings.TrimSpace(string(out)), nil
+	}
+	for _, name := range []string{"origin/main", "origin/master", "main", "master"} {
+		if _, err := r.run("rev-parse", "--verify", "--quiet", name+"^{commit}"); err == nil {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("could not find a default branch; pass one with --base")
+}
+
+// MergeBase returns the best common ancestor of two revisions.
+func (r *Repo) MergeBase(a, b string) (string, error) {
+	out, err := r.run("merge-base", a, b)
+	if err != nil {
+		return "", fmt.Errorf("failed to find the merge base of %s and %s: %w", a, b, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// GetRangeDiff returns the diff between two revisions.
+func (r *Repo) GetRangeDiff(from, to string) ([]byte, error) {
+	out, err := r.run("diff", "--no-color", "--no-ext-diff", from, to, "--")
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff %s..%s: %w", shortHash(from), shortHash(to), err)
+	}
+	return out, nil
+}
+
+// CheckBranchName validates a new branch name with "git check-ref-format
+// --branch" and returns it as git would store it.
+func (r *Repo) CheckBranchName(name string) (string, error) {
+	out, err := r.run("check-ref-format", "--branch", name)
+	if err != nil {
+		return "", fmt.Errorf("%q is not a valid branch name", name)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// SwitchNewBranch creates a branch at HEAD and switches to it. The index and
+// working tree come along unchanged.
+func (r *Repo) SwitchNewBranch(name string) error {
+	if _, err := r.run("switch", "-c", name); err != nil {
+		return fmt.Errorf("failed to create branch %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBranchHelpers(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repoDir := t.TempDir()
+	runGit(t, repoDir, "init", "-b", "main")
+	runGit(t, repoDir, "config", "user.email", "test@example.com")
+	runGit(t, repoDir, "config", "user.name", "Test User")
+	runGit(t, repoDir, "commit", "--allow-empty", "-m", "chore: init")
+	base := strings.TrimSpace(runGit(t, repoDir, "rev-parse", "HEAD"))
+	runGit(t, repoDir, "checkout", "-b", "feature")
+	runGit(t, repoDir, "commit", "--allow-empty", "-m", "feat: work")
+	runGit(t, repoDir, "checkout", "main")
+	runGit(t, repoDir, "commit", "--allow-empty", "-m", "fix: on main")
+	runGit(t, repoDir, "checkout", "feature")
+
+	repo := NewRepo(repoDir, ExecRunner{})
+	if branch, err := repo.CurrentBranch(); err != nil || branch != "feature" {
+		t.Fatalf("CurrentBranch() = %q, %v", branch, err)
+	}
+	if branch, err := repo.DefaultBranch(); err != nil || branch != "main" {
+		t.Fatalf("DefaultBranch() = %q, %v", branch, err)
+	}
+	if mergeBase, err := repo.MergeBase("main", "HEAD"); err != nil || mergeBase != base {
+		t.Fatalf("MergeBase() = %q, %v; want %s", mergeBase, err, base)
+	}
+
+	runGit(t, repoDir, "update-ref", "refs/remotes/origin/trunk", "main")
+	runGit(t, repoDir, "symbolic-ref", "refs/remotes/origin/HEAD", "refs/remotes/origin/trunk")
+	if branch, err := repo.DefaultBranch(); err != nil || branch != "origin/trunk" {
+		t.Fatalf("expected the remote's HEAD, got %q, %v", branch, err)
+	}
+
+	runGit(t, repoDir, "checkout", "--detach")
+	if branch, err := repo.CurrentBranch(); err != nil || branch != "" {
+		t.Fatalf("CurrentBranch() when detached = %q, %v", branch, err)
+	}
+}
+
+func TestSwitchNewBranchKeepsStagedChanges(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repoDir := t.TempDir()
+	runGit(t, repoDir, "init", "-b", "main")
+	runGit(t, repoDir, "config", "user.email", "test@example.com")
+	runGit(t, repoDir, "config", "user.name", "Test User")
+	runGit(t, repoDir, "commit", "--allow-empty", "-m", "chore: init")
+	if err := os.WriteFile(filepath.Join(repoDir, "export.go"), []byte("package export\n"), 0o644); err != nil {
+		t.Fatalf("write export.go: %v", err)
+	}
+	runGit(t, repoDir, "add", "export.go")
+
+	repo := NewRepo(repoDir, ExecRunner{})
+	if _, err := repo.CheckBranchName("feat/bad..name"); err == nil {
+		t.Fatal("expected an invalid branch name to be rejected")
+	}
+	name, err := repo.CheckBranchName("feat/add-export")
+	if err != nil {
+		t.Fatalf("CheckBranchName() error = %v", err)
+	}
+	if err := repo.SwitchNewBranch(name); err != nil {
+		t.Fatalf("SwitchNewBranch() error = %v", err)
+	}
+	if branch, err := repo.CurrentBranch(); err != nil || branch != "feat/add-export" {
+		t.Fatalf("CurrentBranch() = %q, %v", branch, err)
+	}
+	if staged := runGit(t, repoDir, "diff", "--cached", "--name-only"); strings.TrimSpace(staged) != "export.go" {
+		t.Fatalf("expected export.go to stay staged, got %q", staged)
+	}
+	if err := repo.SwitchNewBranch(name); err == nil {
+		t.Fatal("expected switching to an existing branch name to fail")
+	}
+}
@@ -0,0 +1,375 @@
+package git
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FileDiff is the section of a unified diff that belongs to one file.
+type FileDiff struct {
+	OldPath string
+	NewPath string
+	// Header holds the raw lines before the first hunk ("diff --git", "index", "---", "+++", ...).
+	Header []string
+	Hunks  []Hunk
+	Binary bool
+}
+
+// Hunk is a single "@@" block of a file diff.
+type Hunk struct {
+	// ID identifies the hunk by file and content, so it survives line shifts elsewhere in the file.
+	ID       string
+	OldStart int
+	OldLines int
+	NewStart int
+	NewLines int
+	Section  string
+	Lines    []DiffLine
+}
+
+// DiffLine is one line of a hunk. Kind is ' ', '+', '-' or '\\' for "\ No newline at end of file".
+type DiffLine struct {
+	Kind byte
+	Text string
+}
+
+// IsChange reports whether the line adds or removes content.
+func (l DiffLine) IsChange() bool {
+	return l.Kind == '+' || l.Kind == '-'
+}
+
+// Path returns the path the diff applies to, preferring the new path.
+func (f FileDiff) Path() string {
+	if f.NewPath != "" && f.NewPath != "/dev/null" {
+		return f.NewPath
+	}
+	return f.OldPath
+}
+
+// IsDeletion reports whether the diff deletes the file.
+func (f FileDiff) IsDeletion() bool {
+	for _, line := range f.Header {
+		if strings.HasPrefix(line, "deleted file mode") {
+			return true
+		}
+	}
+	return false
+}
+
+// Header returns the hunk's "@@ -a,b +c,d @@" line.
+func (h Hunk) Header() string {
+	header := fmt.Sprintf("@@ -%s +%s @@", hunkRange(h.OldStart, h.OldLines), hunkRange(h.NewStart, h.NewLines))
+	if h.Section != "" {
+		header += " " + h.Section
+	}
+	return header
+}
+
+// ChangeCount returns the number of added and removed lines in the hunk.
+func (h Hunk) ChangeCount() int {
+	count := 0
+	for _, line := range h.Lines {
+		if line.IsChange() {
+			count++
+		}
+	}
+	return count
+}
+
+// ParseDiff splits unified diff output from git into files and hunks.
+func ParseDiff(diff string) ([]FileDiff, error) {
+	var files []FileDiff
+	var file *FileDiff
+	var hunk *Hunk
+
+	flushHunk := func() {
+		if file != nil && hunk != nil {
+			file.Hunks = append(file.Hunks, *hunk)
+		}
+		hunk = nil
+	}
+	flushFile := func() {
+		flushHunk()
+		if file != nil {
+			assignHunkIDs(file)
+			files = append(files, *file)
+		}
+		file = nil
+	}
+
+	lines := strings.Split(strings.TrimSuffix(diff, "\n"), "\n")
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			flushFile()
+			file = &FileDiff{Header: []string{line}}
+			file.OldPath, file.NewPath = parseDiffGitPaths(line)
+		case file == nil:
+			if strings.TrimSpace(line) != "" {
+				return nil, fmt.Errorf("unexpected diff line before file header: %q", line)
+			}
+		case strings.HasPrefix(line, "@@"):
+			flushHunk()
+			parsed, err := parseHunkHeader(line)
+			if err != nil {
+				return nil, err
+			}
+			hunk = &parsed
+		case hunk != nil:
+			if line == "" {
+				// Some tools strip the trailing space of empty context lines.
+				hunk.Lines = append(hunk.Lines, DiffLine{Kind: ' '})
+				continue
+			}
+			switch line[0] {
+			case ' ', '+', '-', '\\':
+				hunk.Lines = append(hunk.Lines, DiffLine{Kind: line[0], Text: line[1:]})
+			default:
+				return nil, fmt.Errorf("unexpected line in hunk of %s: %q", file.Path(), line)
+			}
+		default:
+			file.Header = append(file.Header, line)
+			switch {
+			case strings.HasPrefix(line, "--- "):
+				file.OldPath = trimDiffPath(strings.TrimPrefix(line, "--- "), "a/")
+			case strings.HasPrefix(line, "+++ "):
+				file.NewPath = trimDiffPath(strings.TrimPrefix(line, "+++ "), "b/")
+			case strings.HasPrefix(line, "Binary files ") || line == "GIT binary patch":
+				file.Binary = true
+			}
+		}
+	}
+	flushFile()
+
+	return files, nil
+}
+
+// Selection chooses what to include in a patch. Keys are hunk IDs; values are
+// the indexes into Hunk.Lines of the changed lines to keep. A nil set selects
+// the whole hunk.
+type Selection map[string]map[int]bool
+
+// Patch builds a patch containing only the selected hunks and lines. Unselected
+// additions are dropped and unselected removals are kept as context, so the
+// result applies cleanly to the diff's preimage. It returns nil when nothing is
+// selected.
+func (f FileDiff) Patch(selection Selection) []byte {
+	var hunks []Hunk
+	partial := false
+	delta := 0
+
+	for _, hunk := range f.Hunks {
+		lines, ok := selection[hunk.ID]
+		if !ok {
+			partial = true
+			continue
+		}
+
+		out := Hunk{OldStart: hunk.OldStart, Section: hunk.Section}
+		keepPrev := true
+		for i, line := range hunk.Lines {
+			switch line.Kind {
+			case '\\':
+				if keepPrev {
+					out.Lines = append(out.Lines, line)
+				}
+				continue
+			case ' ':
+				out.Lines = append(out.Lines, line)
+				keepPrev = true
+			case '+':
+				keepPrev = lines == nil || lines[i]
+				if keepPrev {
+					out.Lines = append(out.Lines, line)
+				} else {
+					partial = true
+				}
+			case '-':
+				keepPrev = true
+				if lines == nil || lines[i] {
+					out.Lines = append(out.Lines, line)
+				} else {
+					partial = true
+					out.Lines = append(out.Lines, DiffLine{Kind: ' ', Text: line.Text})
+				}
+			}
+		}
+
+		changed := false
+		for _, line := range out.Lines {
+			switch line.Kind {
+			case ' ':
+				out.OldLines++
+				out.NewLines++
+			case '-':
+				out.OldLines++
+				changed = true
+			case '+':
+				out.NewLines++
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+
+		out.NewStart = out.OldStart + delta
+		if out.OldLines == 0 {
+			out.NewStart++
+		}
+		if out.NewLines == 0 {
+			out.NewStart--
+		}
+		delta += out.NewLines - out.OldLines
+		hunks = append(hunks, out)
+	}
+
+	if len(hunks) == 0 {
+		return nil
+	}
+
+	var b bytes.Buffer
+	for _, line := range f.patchHeader(partial) {
+		b.WriteString(line + "\n")
+	}
+	for _, hunk := range hunks {
+		b.WriteString(hunk.Header() + "\n")
+		for _, line := range hunk.Lines {
+			b.WriteByte(line.Kind)
+			b.WriteString(line.Text + "\n")
+		}
+	}
+	return b.Bytes()
+}
+
+// patchHeader returns the file header for a patch. A partially staged deletion
+// has to become a modification, otherwise git would remove the whole file.
+func (f FileDiff) patchHeader(partial bool) []string {
+	if !partial || !f.IsDeletion() {
+		return f.Header
+	}
+
+	header := make([]string, 0, len(f.Header))
+	for _, line := range f.Header {
+		switch {
+		case strings.HasPrefix(line, "deleted file mode"):
+			continue
+		case strings.HasPrefix(line, "+++ "):
+			line = "+++ b/" + f.OldPath
+		}
+		header = append(header, line)
+	}
+	return header
+}
+
+// ApplyCached applies a patch to the index without touching the work tree.
+func (r *Repo) ApplyCached(patch []byte) error {
+	if len(patch) == 0 {
+		return nil
+	}
+
+	if _, err := r.runWith(Command{
+		Args:  []string{"apply", "--cached", "--whitespace=nowarn", "-"},
+		Stdin: bytes.NewReader(patch),
+	}); err != nil {
+		return fmt.Errorf("failed to apply patch to index: %w", err)
+	}
+	return nil
+}
+
+func assignHunkIDs(file *FileDiff) {
+	seen := map[string]int{}
+	for i := range file.Hunks {
+		sum := sha1.New()
+		sum.Write([]byte(file.Path()))
+		for _, line := range file.Hunks[i].Lines {
+			sum.Write([]byte{'\n', line.Kind})
+			sum.Write([]byte(line.Text))
+		}
+		id := hex.EncodeToString(sum.Sum(nil))[:12]
+
+		// Identical hunks in one file are told apart by their order.
+		seen[id]++
+		if n := seen[id]; n > 1 {
+			id = fmt.Sprintf("%s-%d", id, n)
+		}
+		file.Hunks[i].ID = id
+	}
+}
+
+func parseHunkHeader(line string) (Hunk, error) {
+	rest, ok := strings.CutPrefix(line, "@@ -")
+	if !ok {
+		return Hunk{}, fmt.Errorf("invalid hunk header %q", line)
+	}
+	ranges, section, ok := strings.Cut(rest, " @@")
+	if !ok {
+		return Hunk{}, fmt.Errorf("invalid hunk header %q", line)
+	}
+	oldRange, newRange, ok := strings.Cut(ranges, " +")
+	if !ok {
+		return Hunk{}, fmt.Errorf("invalid hunk header %q", line)
+	}
+
+	hunk := Hunk{Section: strings.TrimPrefix(section, " ")}
+	var err error
+	if hunk.OldStart, hunk.OldLines, err = parseHunkRange(oldRange); err != nil {
+		return Hunk{}, fmt.Errorf("invalid hunk header %q: %w", line, err)
+	}
+	if hunk.NewStart, hunk.NewLines, err = parseHunkRange(newRange); err != nil {
+		return Hunk{}, fmt.Errorf("invalid hunk header %q: %w", line, err)
+	}
+	return hunk, nil
+}
+
+func parseHunkRange(value string) (int, int, error) {
+	startText, countText, hasCount := strings.Cut(value, ",")
+	start, err := strconv.Atoi(startText)
+	if err != nil {
+		return 0, 0, err
+	}
+	if !hasCount {
+		return start, 1, nil
+	}
+	count, err := strconv.Atoi(countText)
+	if err != nil {
+		return 0, 0, err
+	}
+	return start, count, nil
+}
+
+func hunkRange(start, count int) string {
+	if count == 1 {
+		return strconv.Itoa(start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// parseDiffGitPaths reads the paths from a "diff --git a/x b/x" line. The ---
+// and +++ lines override them when present; this covers mode-only and binary
+// changes that have neither.
+func parseDiffGitPaths(line string) (string, string) {
+	rest := strings.TrimPrefix(line, "diff --git ")
+	if strings.HasPrefix(rest, `"`) {
+		return "", ""
+	}
+	if idx := strings.Index(rest, " b/"); idx >= 0 {
+		return strings.TrimPrefix(rest[:idx], "a/"), rest[idx+3:]
+	}
+	return "", ""
+}
+
+func trimDiffPath(path, prefix string) string {
+	path = strings.TrimSuffix(path, "\t")
+	if path == "/dev/null" {
+		return path
+	}
+	if unquoted, err := strconv.Unquote(path); err == nil && strings.HasPrefix(path, `"`) {
+		path = unquoted
+	}
+	return strings.TrimPrefix(path, prefix)
+}
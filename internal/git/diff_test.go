@@ -0,0 +1,194 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const sampleDiff = `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1,4 +1,5 @@ package main
+ one
+-two
++TWO
++two and a half
+ three
+ four
+@@ -10,3 +11,3 @@ func main() {
+ ten
+-eleven
++ELEVEN
+ twelve
+diff --git a/logo.png b/logo.png
+index 3333333..4444444 100644
+Binary files a/logo.png and b/logo.png differ
+`
+
+func TestParseDiff(t *testing.T) {
+	files, err := ParseDiff(sampleDiff)
+	if err != nil {
+		t.Fatalf("ParseDiff returned error: %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("expected 2 files, got %d", len(files))
+	}
+
+	file := files[0]
+	if file.Path() != "main.go" || len(file.Hunks) != 2 {
+		t.Fatalf("unexpected file: %+v", file)
+	}
+	first := file.Hunks[0]
+	if first.OldStart != 1 || first.OldLines != 4 || first.NewStart != 1 || first.NewLines != 5 || first.Section != "package main" {
+		t.Fatalf("unexpected hunk header: %+v", first)
+	}
+	if first.ChangeCount() != 3 {
+		t.Fatalf("expected 3 changed lines, got %d", first.ChangeCount())
+	}
+	if first.ID == "" || first.ID == file.Hunks[1].ID {
+		t.Fatalf("expected distinct hunk IDs, got %q and %q", first.ID, file.Hunks[1].ID)
+	}
+	if !files[1].Binary || files[1].Path() != "logo.png" {
+		t.Fatalf("expected binary logo.png, got %+v", files[1])
+	}
+}
+
+func TestHunkIDsAreStableAcrossLineShifts(t *testing.T) {
+	shifted := strings.Replace(sampleDiff, "@@ -10,3 +11,3 @@", "@@ -20,3 +21,3 @@", 1)
+
+	before, _ := ParseDiff(sampleDiff)
+	after, _ := ParseDiff(shifted)
+	if before[0].Hunks[1].ID != after[0].Hunks[1].ID {
+		t.Fatalf("expected hunk ID to survive a line shift")
+	}
+}
+
+func TestPatchWithLineSelection(t *testing.T) {
+	files, err := ParseDiff(sampleDiff)
+	if err != nil {
+		t.Fatalf("ParseDiff returned error: %v", err)
+	}
+	file := files[0]
+
+	// Keep only "-two" and "+TWO" from the first hunk; skip the second hunk.
+	patch := string(file.Patch(Selection{file.Hunks[0].ID: {1: true, 2: true}}))
+	want := `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1,4 +1,4 @@ package main
+ one
+-two
++TWO
+ three
+ four
+`
+	if patch != want {
+		t.Fatalf("unexpected patch:\n%s\nwant:\n%s", patch, want)
+	}
+
+	if file.Patch(Selection{}) != nil {
+		t.Fatalf("expected nil patch for empty selection")
+	}
+}
+
+func TestApplyCachedStagesSelectedLines(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repoDir := t.TempDir()
+	runGit(t, repoDir, "init")
+	runGit(t, repoDir, "config", "user.email", "test@example.com")
+	runGit(t, repoDir, "config", "user.name", "Test User")
+
+	path := filepath.Join(repoDir, "list.txt")
+	original := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n"
+	if err := os.WriteFile(path, []byte(original), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	runGit(t, repoDir, "add", "list.txt")
+	runGit(t, repoDir, "commit", "-m", "feat: add list")
+
+	if err := os.WriteFile(path, []byte("A\nb\nc\nd\ne\nf\ng\nh\ni\nJ\nk\n"), 0o644); err != nil {
+		t.Fatalf("modify file: %v", err)
+	}
+
+	repo := NewRepo(repoDir, nil)
+	diff, err := repo.GetFileDiff("list.txt")
+	if err != nil {
+		t.Fatalf("GetFileDiff returned error: %v", err)
+	}
+	files, err := ParseDiff(string(diff))
+	if err != nil {
+		t.Fatalf("ParseDiff returned error: %v", err)
+	}
+	if len(files) != 1 || len(files[0].Hunks) != 2 {
+		t.Fatalf("expected two hunks, got %+v", files)
+	}
+
+	// Stage the first hunk whole and only the "+k" addition of the second.
+	second := files[0].Hunks[1]
+	lines := map[int]bool{}
+	for i, line := range second.Lines {
+		if line.Kind == '+' && line.Text == "k" {
+			lines[i] = true
+		}
+	}
+	selection := Selection{files[0].Hunks[0].ID: nil, second.ID: lines}
+	if err := repo.ApplyCached(files[0].Patch(selection)); err != nil {
+		t.Fatalf("ApplyCached returned error: %v", err)
+	}
+
+	staged := runGit(t, repoDir, "show", ":list.txt")
+	if want := "A\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\n"; staged != want {
+		t.Fatalf("unexpected index content:\n%q\nwant:\n%q", staged, want)
+	}
+}
+
+func TestApplyCachedPartialDeletionKeepsFile(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repoDir := t.TempDir()
+	runGit(t, repoDir, "init")
+	runGit(t, repoDir, "config", "user.email", "test@example.com")
+	runGit(t, repoDir, "config", "user.name", "Test User")
+
+	path := filepath.Join(repoDir, "gone.txt")
+	if err := os.WriteFile(path, []byte("keep\ndrop\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	runGit(t, repoDir, "add", "gone.txt")
+	runGit(t, repoDir, "commit", "-m", "feat: add file")
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("remove file: %v", err)
+	}
+
+	repo := NewRepo(repoDir, nil)
+	diff, err := repo.GetFileDiff("gone.txt")
+	if err != nil {
+		t.Fatalf("GetFileDiff returned error: %v", err)
+	}
+	files, err := ParseDiff(string(diff))
+	if err != nil {
+		t.Fatalf("ParseDiff returned error: %v", err)
+	}
+	if !files[0].IsDeletion() {
+		t.Fatalf("expected deletion, got %+v", files[0])
+	}
+
+	hunk := files[0].Hunks[0]
+	if err := repo.ApplyCached(files[0].Patch(Selection{hunk.ID: {1: true}})); err != nil {
+		t.Fatalf("ApplyCached returned error: %v", err)
+	}
+
+	if staged := runGit(t, repoDir, "show", ":gone.txt"); staged != "keep\n" {
+		t.Fatalf("expected file to stay in the index with one line removed, got %q", staged)
+	}
+}
@@ -0,0 +1,118 @@
+package git
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FileStat is one file's line counts, as reported by --numstat.
+type FileStat struct {
+	Path string
+	// OldPath is set for renames and copies.
+	OldPath string
+	Added   int
+	Deleted int
+	Binary  bool
+}
+
+// FileChange is one file of a diff, for browsing the diff file by file.
+type FileChange struct {
+	FileStat
+	// Diff is this file's part of the diff, starting at its "diff --git" line.
+	Diff string
+}
+
+// CommitNumstat returns the added and deleted line counts of each file the
+// commit changes, in the order git show lists them.
+func (r *Repo) CommitNumstat(hash string) ([]FileStat, error) {
+	out, err := r.run("show", "--format=", "--numstat", "-z", hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commit stats: %w", err)
+	}
+	return parseNumstat(string(out)), nil
+}
+
+// parseNumstat reads "--numstat -z" records: "added\tdeleted\tpath\0", or
+// "added\tdeleted\t\0old\0new\0" for a rename. Binary files count "-".
+func parseNumstat(out string) []FileStat {
+	fields := strings.Split(out, "\x00")
+	var stats []FileStat
+	for i := 0; i < len(fields); i++ {
+		parts := strings.SplitN(fields[i], "\t", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		stat := FileStat{Path: parts[2], Binary: parts[0] == "-"}
+		stat.Added, _ = strconv.Atoi(parts[0])
+		stat.Deleted, _ = strconv.Atoi(parts[1])
+		if stat.Path == "" && i+2 < len(fields) {
+			stat.OldPath, stat.Path = fields[i+1], fields[i+2]
+			i += 2
+		}
+		stats = append(stats, stat)
+	}
+	return stats
+}
+
+// SplitDiff splits a diff into its files. stats, from CommitNumstat, supply
+// the line counts when they line up with the diff; otherwise the counts are
+// taken from the diff's own hunks.
+func SplitDiff(diff string, stats []FileStat) []FileChange {
+	var chunks []string
+	start := -1
+	lines := strings.SplitAfter(diff, "\n")
+	offset := 0
+	for _, line := range lines {
+		if strings.HasPrefix(line, "diff --git ") {
+			if start >= 0 {
+				chunks = append(chunks, diff[start:offset])
+			}
+			start = offset
+		}
+		offset += len(line)
+	}
+	if start >= 0 {
+		chunks = append(chunks, diff[start:])
+	}
+
+	useStats := len(stats) == len(chunks)
+	changes := make([]FileChange, 0, len(chunks))
+	for i, chunk := range chunks {
+		change := FileChange{Diff: chunk}
+		if useStats {
+			change.FileStat = stats[i]
+		} else {
+			change.FileStat = statFromDiff(chunk)
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// statFromDiff counts the changed lines of a single file's diff.
+func statFromDiff(chunk string) FileStat {
+	files, err := ParseDiff(chunk)
+	if err != nil || len(files) == 0 {
+		header, _, _ := strings.Cut(chunk, "\n")
+		_, path := parseDiffGitPaths(header)
+		return FileStat{Path: path}
+	}
+
+	file := files[0]
+	stat := FileStat{Path: file.Path(), Binary: file.Binary}
+	if file.OldPath != "" && file.OldPath != "/dev/null" && file.OldPath != stat.Path {
+		stat.OldPath = file.OldPath
+	}
+	for _, hunk := range file.Hunks {
+		for _, line := range hunk.Lines {
+			switch line.Kind {
+			case '+':
+				stat.Added++
+			case '-':
+				stat.Deleted++
+			}
+		}
+	}
+	return stat
+}
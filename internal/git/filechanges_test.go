@@ -0,0 +1,110 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseNumstat(t *testing.T) {
+	stats := parseNumstat("-\t-\tlogo.png\x000\t0\t\x00old.go\x00new.go\x003\t1\tmain.go\x00")
+	want := []FileStat{
+		{Path: "logo.png", Binary: true},
+		{Path: "new.go", OldPath: "old.go"},
+		{Path: "main.go", Added: 3, Deleted: 1},
+	}
+	if len(stats) != len(want) {
+		t.Fatalf("parseNumstat() = %+v", stats)
+	}
+	for i := range want {
+		if stats[i] != want[i] {
+			t.Fatalf("stat %d = %+v, want %+v", i, stats[i], want[i])
+		}
+	}
+}
+
+func TestSplitDiffCountsWithoutStats(t *testing.T) {
+	diff := "diff --git a/a.go b/a.go\n" +
+		"--- a/a.go\n" +
+		"+++ b/a.go\n" +
+		"@@ -1,2 +1,2 @@\n" +
+		"-old\n" +
+		"+new\n" +
+		"+more\n" +
+		"diff --git a/b.go b/c.go\n" +
+		"similarity index 100%\n" +
+		"rename from b.go\n" +
+		"rename to c.go\n"
+
+	changes := SplitDiff(diff, nil)
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 files, got %+v", changes)
+	}
+	if changes[0].Path != "a.go" || changes[0].Added != 2 || changes[0].Deleted != 1 {
+		t.Fatalf("unexpected first file %+v", changes[0].FileStat)
+	}
+	if changes[1].Path != "c.go" || changes[1].OldPath != "b.go" {
+		t.Fatalf("unexpected rename %+v", changes[1].FileStat)
+	}
+	if changes[0].Diff+changes[1].Diff != diff {
+		t.Fatal("expected the file diffs to add up to the whole diff")
+	}
+	if len(SplitDiff("", nil)) != 0 {
+		t.Fatal("expected no files for an empty diff")
+	}
+}
+
+func TestCommitNumstatMatchesDiff(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repoDir := t.TempDir()
+	runGit(t, repoDir, "init", "-b", "main")
+	runGit(t, repoDir, "config", "user.email", "test@example.com")
+	runGit(t, repoDir, "config", "user.name", "Test User")
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repoDir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	write("a.txt", "one\ntwo\n")
+	write("b.txt", "keep\n")
+	runGit(t, repoDir, "add", ".")
+	runGit(t, repoDir, "commit", "-m", "chore: init")
+	write("a.txt", "one\nthree\nfour\n")
+	runGit(t, repoDir, "mv", "b.txt", "c.txt")
+	write("logo.png", "\x00\x01\x02")
+	runGit(t, repoDir, "add", ".")
+	runGit(t, repoDir, "commit", "-m", "feat: change")
+
+	repo := NewRepo(repoDir, ExecRunner{})
+	stats, err := repo.CommitNumstat("HEAD")
+	if err != nil {
+		t.Fatalf("CommitNumstat() error = %v", err)
+	}
+	_, diff, err := repo.GetCommitDetails("HEAD")
+	if err != nil {
+		t.Fatalf("GetCommitDetails() error = %v", err)
+	}
+
+	changes := SplitDiff(diff, stats)
+	if len(changes) != 3 {
+		t.Fatalf("expected 3 files, got %+v", changes)
+	}
+	byPath := map[string]FileStat{}
+	for _, change := range changes {
+		byPath[change.Path] = change.FileStat
+	}
+	if a := byPath["a.txt"]; a.Added != 2 || a.Deleted != 1 {
+		t.Fatalf("unexpected a.txt stat %+v", a)
+	}
+	if c := byPath["c.txt"]; c.OldPath != "b.txt" {
+		t.Fatalf("expected the rename from b.txt, got %+v", c)
+	}
+	if !byPath["logo.png"].Binary {
+		t.Fatalf("expected logo.png to be binary, got %+v", byPath["logo.png"])
+	}
+}
@@ -0,0 +1,350 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// unpushedDepth caps how many unpushed commits are considered as fixup targets.
+const unpushedDepth = 200
+
+// FixupHunk is a staged hunk, identified by its file and hunk ID.
+type FixupHunk struct {
+	Path   string
+	HunkID string
+}
+
+// FixupTarget is an unpushed commit that staged hunks most likely fix.
+type FixupTarget struct {
+	Commit CommitInfo
+	Hunks  []FixupHunk
+	// Lines counts the blamed lines that point at the commit.
+	Lines int
+}
+
+// Paths returns the files the target's hunks touch.
+func (t FixupTarget) Paths() []string {
+	var paths []string
+	for _, hunk := range t.Hunks {
+		if !slices.Contains(paths, hunk.Path) {
+			paths = append(paths, hunk.Path)
+		}
+	}
+	return paths
+}
+
+// FixupPlan maps the staged hunks onto the unpushed commits they change.
+type FixupPlan struct {
+	// Targets are ordered newest commit first.
+	Targets []FixupTarget
+	// Unmatched hunks only touch lines from pushed commits, or add new files.
+	Unmatched []FixupHunk
+	files     []FileDiff
+}
+
+// UnpushedCommits returns the commits on HEAD that are on no remote branch,
+// newest first.
+func (r *Repo) UnpushedCommits() ([]CommitInfo, error) {
+	if !r.CanAmend() {
+		return nil, nil
+	}
+	out, err := r.run("log", fmt.Sprintf("--max-count=%d", unpushedDepth), "--pretty=format:%H%x00%an%x00%ad%x00%s", "HEAD", "--not", "--remotes")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list unpushed commits: %w", err)
+	}
+	return parseCommitLog(out), nil
+}
+
+// PlanFixups blames the lines each staged hunk changes and assigns the hunk to
+// the unpushed commit that wrote most of them. Pure additions are blamed on
+// the lines around them.
+func (r *Repo) PlanFixups() (*FixupPlan, error) {
+	diff, err := r.GetStagedDiff()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read staged changes: %w", err)
+	}
+	files, err := ParseDiff(string(diff))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse staged changes: %w", err)
+	}
+	commits, err := r.UnpushedCommits()
+	if err != nil {
+		return nil, err
+	}
+
+	rank := map[string]int{}
+	for i, commit := range commits {
+		rank[commit.Hash] = i
+	}
+
+	plan := &FixupPlan{files: files}
+	targets := map[string]*FixupTarget{}
+	for _, file := range files {
+		var owners map[int]string
+		if file.OldPath != "/dev/null" && !file.Binary && len(commits) > 0 {
+			owners, err = r.blameLines(file.OldPath, fixupBlameLines(file))
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		for _, hunk := range file.Hunks {
+			ref := FixupHunk{Path: file.Path(), HunkID: hunk.ID}
+
+			counts := map[string]int{}
+			for _, line := range hunkBlameLines(hunk) {
+				if hash, ok := owners[line]; ok {
+					if _, unpushed := rank[hash]; unpushed {
+						counts[hash]++
+					}
+				}
+			}
+
+			best := ""
+			for hash, count := range counts {
+				// Ties go to the newest commit.
+				if best == "" || count > counts[best] || (count == counts[best] && rank[hash] < rank[best]) {
+					best = hash
+				}
+			}
+			if best == "" {
+				plan.Unmatched = append(plan.Unmatched, ref)
+				continue
+			}
+
+			target, ok := targets[best]
+			if !ok {
+				target = &FixupTarget{Commit: commits[rank[best]]}
+				targets[best] = target
+			}
+			target.Hunks = append(target.Hunks, ref)
+			target.Lines += counts[best]
+		}
+	}
+
+	for _, commit := range commits {
+		if target, ok := targets[commit.Hash]; ok {
+			plan.Targets = append(plan.Targets, *target)
+		}
+	}
+	return plan, nil
+}
+
+// fixupBlameLines returns the old line numbers to blame for every hunk of a file.
+func fixupBlameLines(file FileDiff) []int {
+	var lines []int
+	for _, hunk := range file.Hunks {
+		lines = append(lines, hunkBlameLines(hunk)...)
+	}
+	return lines
+}
+
+// hunkBlameLines returns the old line numbers a hunk removes, or for a pure
+// addition, the context lines directly before and after each added run.
+func hunkBlameLines(hunk Hunk) []int {
+	var removed, neighbours []int
+	oldLine := hunk.OldStart
+	prevContext := 0
+	for i, line := range hunk.Lines {
+		switch line.Kind {
+		case '-':
+			removed = append(removed, oldLine)
+			oldLine++
+		case ' ':
+			if i > 0 && hunk.Lines[i-1].Kind == '+' {
+				neighbours = append(neighbours, oldLine)
+			}
+			prevContext = oldLine
+			oldLine++
+		case '+':
+			if prevContext > 0 && (i == 0 || hunk.Lines[i-1].Kind == ' ') {
+				neighbours = append(neighbours, prevContext)
+			}
+		}
+	}
+	if len(removed) > 0 {
+		return removed
+	}
+	return neighbours
+}
+
+// blameLines maps each requested line of path at HEAD to the commit that last changed it.
+func (r *Repo) blameLines(path string, lines []int) (map[int]string, error) {
+	if len(lines) == 0 {
+		return nil, nil
+	}
+
+	lines = slices.Compact(slices.Sorted(slices.Values(lines)))
+	args := []string{"blame", "--porcelain"}
+	for _, line := range lines {
+		args = append(args, fmt.Sprintf("-L%d,%d", line, line))
+	}
+	args = append(args, "HEAD", "--", path)
+	out, err := r.run(args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to blame %s: %w", path, err)
+	}
+	return parseBlamePorcelain(out), nil
+}
+
+// parseBlamePorcelain reads "<hash> <orig-line> <final-line> [<count>]"
+// headers from git blame --porcelain output.
+func parseBlamePorcelain(out []byte) map[int]string {
+	owners := map[int]string{}
+	for _, line := range strings.Split(string(out), "\n") {
+		if strings.HasPrefix(line, "\t") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 3 || len(fields) > 4 || !isHash(fields[0]) {
+			continue
+		}
+		final, err := strconv.Atoi(fields[2])
+		if err != nil {
+			continue
+		}
+		owners[final] = fields[0]
+	}
+	return owners
+}
+
+func isHash(s string) bool {
+	if len(s) != 40 && len(s) != 64 {
+		return false
+	}
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return false
+		}
+	}
+	return true
+}
+
+// Patch builds a patch of the given staged hunks against HEAD.
+func (p *FixupPlan) Patch(hunks []FixupHunk) []byte {
+	var b bytes.Buffer
+	for _, file := range p.files {
+		selection := Selection{}
+		for _, hunk := range hunks {
+			if hunk.Path == file.Path() {
+				selection[hunk.HunkID] = nil
+			}
+		}
+		if len(selection) > 0 {
+			b.Write(file.Patch(selection))
+		}
+	}
+	return b.Bytes()
+}
+
+// Paths returns every path with staged changes in the plan, including rename sources.
+func (p *FixupPlan) Paths() []string {
+	var paths []string
+	for _, file := range p.files {
+		for _, path := range []string{file.OldPath, file.NewPath} {
+			if path != "" && path != "/dev/null" && !slices.Contains(paths, path) {
+				paths = append(paths, path)
+			}
+		}
+	}
+	return paths
+}
+
+// CommitFixups creates a "fixup!" commit for each target from its hunks. The
+// index is emptied first and each target's hunks are staged on their own;
+// hunks not committed, including those of targets left out, are staged again
+// afterwards, also when a commit fails. beforeCommit, if set, runs once a
+// target's hunks are staged, with the fixup's message, so pre-commit hooks
+// check what is about to be committed; an error stops before that commit.
+// afterCommit, if set, runs with the same message once each fixup is created.
+// It returns the number of fixup commits created.
+func (r *Repo) CommitFixups(plan *FixupPlan, targets []FixupTarget, opts CommitOptions, beforeCommit func(target FixupTarget, message string) error, afterCommit func(target FixupTarget, message string)) (int, error) {
+	var committed []FixupHunk
+	restore := func() error {
+		var remaining []FixupHunk
+		for _, file := range plan.files {
+			for _, hunk := range file.Hunks {
+				ref := FixupHunk{Path: file.Path(), HunkID: hunk.ID}
+				if !slices.Contains(committed, ref) {
+					remaining = append(remaining, ref)
+				}
+			}
+		}
+		return r.ApplyCached(plan.Patch(remaining))
+	}
+
+	if err := r.UnstageFiles(plan.Paths()); err != nil {
+		return 0, err
+	}
+
+	for i, target := range targets {
+		if err := r.ApplyCached(plan.Patch(target.Hunks)); err != nil {
+			_ = r.UnstageFiles(plan.Paths())
+			_ = restore()
+			return i, err
+		}
+
+		message := "fixup! " + target.Commit.Subject
+		if beforeCommit != nil {
+			if err := beforeCommit(target, message); err != nil {
+				_ = r.UnstageFiles(target.Paths())
+				_ = restore()
+				return i, err
+			}
+		}
+
+		args := append([]string{"commit", "--fixup=" + target.Commit.Hash}, opts.args()...)
+		if _, err := r.run(args...); err != nil {
+			_ = r.UnstageFiles(target.Paths())
+			_ = restore()
+			return i, fmt.Errorf("failed to create fixup for %s: %w", shortHash(target.Commit.Hash), err)
+		}
+		committed = append(committed, target.Hunks...)
+		if afterCommit != nil {
+			afterCommit(target, message)
+		}
+	}
+
+	if err := restore(); err != nil {
+		return len(targets), err
+	}
+	return len(targets), nil
+}
+
+// Autosquash folds fixup commits into their targets with a non-interactive
+// "git rebase -i --autosquash" starting below the oldest target. Uncommitted
+// changes are stashed for the rebase and restored afterwards, though git
+// only restores new files to the index.
+func (r *Repo) Autosquash(targets []FixupTarget) error {
+	if len(targets) == 0 {
+		return nil
+	}
+
+	// Targets are ordered newest first.
+	oldest := targets[len(targets)-1].Commit.Hash
+	args := []string{"rebase", "-i", "--autosquash", "--autostash"}
+	if _, err := r.run("rev-parse", "--verify", "--quiet", oldest+"^"); err != nil {
+		args = append(args, "--root")
+	} else {
+		args = append(args, oldest+"^")
+	}
+
+	if _, err := r.runWith(Command{
+		Args: args,
+		// Accept the generated todo list and messages without opening an editor.
+		Env: []string{"GIT_SEQUENCE_EDITOR=true", "GIT_EDITOR=true"},
+	}); err != nil {
+		return fmt.Errorf("failed to autosquash fixups: %w", err)
+	}
+	return nil
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 8 {
+		return hash[:8]
+	}
+	return hash
+}
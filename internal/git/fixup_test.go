@@ -0,0 +1,175 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPlanAndCommitFixups(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repoDir := t.TempDir()
+	runGit(t, repoDir, "init", "-b", "main")
+	runGit(t, repoDir, "config", "user.email", "test@example.com")
+	runGit(t, repoDir, "config", "user.name", "Test User")
+
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repoDir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	write("pushed.txt", "one\ntwo\n")
+	runGit(t, repoDir, "add", ".")
+	runGit(t, repoDir, "commit", "-m", "chore: pushed")
+	// Pretend the first commit is already on the remote.
+	runGit(t, repoDir, "update-ref", "refs/remotes/origin/main", "HEAD")
+
+	write("a.txt", "alpha\nbeta\ngamma\n")
+	runGit(t, repoDir, "add", ".")
+	runGit(t, repoDir, "commit", "-m", "feat: add a")
+	write("b.txt", "first\nsecnod\nthird\n")
+	runGit(t, repoDir, "add", ".")
+	runGit(t, repoDir, "commit", "-m", "feat: add b")
+	write("c.txt", "unrelated\n")
+	runGit(t, repoDir, "add", ".")
+	runGit(t, repoDir, "commit", "-m", "feat: add c")
+
+	write("a.txt", "alpha\nbeta\ngamma\ndelta\n")
+	write("b.txt", "first\nsecond\nthird\n")
+	write("pushed.txt", "one\n2\n")
+	write("new.txt", "new\n")
+	runGit(t, repoDir, "add", ".")
+
+	repo := NewRepo(repoDir, ExecRunner{})
+	plan, err := repo.PlanFixups()
+	if err != nil {
+		t.Fatalf("PlanFixups() error = %v", err)
+	}
+	if len(plan.Targets) != 2 {
+		t.Fatalf("expected two targets, got %+v", plan.Targets)
+	}
+	if plan.Targets[0].Commit.Subject != "feat: add b" || plan.Targets[1].Commit.Subject != "feat: add a" {
+		t.Fatalf("unexpected targets: %+v", plan.Targets)
+	}
+	if len(plan.Unmatched) != 2 {
+		t.Fatalf("expected the pushed file and the new file to be unmatched, got %+v", plan.Unmatched)
+	}
+
+	// Hooks run once per fixup: before it with only its hunks staged, and
+	// after it once it is HEAD.
+	var checked []string
+	beforeCommit := func(target FixupTarget, message string) error {
+		staged := strings.TrimSpace(runGit(t, repoDir, "diff", "--cached", "--name-only"))
+		checked = append(checked, message+": "+staged)
+		return nil
+	}
+	var created []string
+	afterCommit := func(target FixupTarget, message string) {
+		head := strings.TrimSpace(runGit(t, repoDir, "log", "-1", "--format=%s"))
+		created = append(created, message+": "+head)
+	}
+	count, err := repo.CommitFixups(plan, plan.Targets, CommitOptions{}, beforeCommit, afterCommit)
+	if err != nil || count != 2 {
+		t.Fatalf("CommitFixups() = %d, %v", count, err)
+	}
+	if got := strings.Join(checked, "; "); got != "fixup! feat: add b: b.txt; fixup! feat: add a: a.txt" {
+		t.Fatalf("unexpected pre-commit checks %q", got)
+	}
+	if got := strings.Join(created, "; "); got != "fixup! feat: add b: fixup! feat: add b; fixup! feat: add a: fixup! feat: add a" {
+		t.Fatalf("unexpected post-commit runs %q", got)
+	}
+	subjects := runGit(t, repoDir, "log", "-2", "--format=%s")
+	if subjects != "fixup! feat: add a\nfixup! feat: add b\n" {
+		t.Fatalf("unexpected fixup commits:\n%s", subjects)
+	}
+	if staged := runGit(t, repoDir, "diff", "--cached", "--name-only"); staged != "new.txt\npushed.txt\n" {
+		t.Fatalf("expected unmatched hunks to stay staged, got %q", staged)
+	}
+
+	if err := repo.Autosquash(plan.Targets); err != nil {
+		t.Fatalf("Autosquash() error = %v", err)
+	}
+	log := runGit(t, repoDir, "log", "--format=%s")
+	if log != "feat: add c\nfeat: add b\nfeat: add a\nchore: pushed\n" {
+		t.Fatalf("unexpected history after autosquash:\n%s", log)
+	}
+	if b := runGit(t, repoDir, "show", "HEAD~1:b.txt"); !strings.Contains(b, "second") {
+		t.Fatalf("expected the typo fix in feat: add b, got %q", b)
+	}
+	if status := runGit(t, repoDir, "status", "--porcelain"); !strings.Contains(status, "pushed.txt") || !strings.Contains(status, "new.txt") {
+		t.Fatalf("expected uncommitted changes to survive the rebase, got %q", status)
+	}
+}
+
+func TestHunkBlameLines(t *testing.T) {
+	files, err := ParseDiff(`diff --git a/f b/f
+--- a/f
++++ b/f
+@@ -3,3 +3,4 @@
+ c
+ d
++added
+ e
+@@ -10,2 +11,2 @@
+ j
+-k
++K
+`)
+	if err != nil {
+		t.Fatalf("ParseDiff() error = %v", err)
+	}
+
+	if got := hunkBlameLines(files[0].Hunks[0]); len(got) != 2 || got[0] != 4 || got[1] != 5 {
+		t.Fatalf("expected the lines around the addition, got %v", got)
+	}
+	if got := hunkBlameLines(files[0].Hunks[1]); len(got) != 1 || got[0] != 11 {
+		t.Fatalf("expected the removed line, got %v", got)
+	}
+}
+
+func TestCommitFixupsStopsWhenBeforeCommitFails(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repoDir := t.TempDir()
+	runGit(t, repoDir, "init", "-b", "main")
+	runGit(t, repoDir, "config", "user.email", "test@example.com")
+	runGit(t, repoDir, "config", "user.name", "Test User")
+	if err := os.WriteFile(filepath.Join(repoDir, "a.txt"), []byte("alpha\nbeta\n"), 0o644); err != nil {
+		t.Fatalf("write a.txt: %v", err)
+	}
+	runGit(t, repoDir, "add", ".")
+	runGit(t, repoDir, "commit", "-m", "feat: add a")
+	if err := os.WriteFile(filepath.Join(repoDir, "a.txt"), []byte("alpha\nbeta!\n"), 0o644); err != nil {
+		t.Fatalf("write a.txt: %v", err)
+	}
+	runGit(t, repoDir, "add", ".")
+
+	repo := NewRepo(repoDir, ExecRunner{})
+	plan, err := repo.PlanFixups()
+	if err != nil || len(plan.Targets) != 1 {
+		t.Fatalf("PlanFixups() = %+v, %v", plan, err)
+	}
+	created, err := repo.CommitFixups(plan, plan.Targets, CommitOptions{}, func(FixupTarget, string) error {
+		return errors.New("lint failed")
+	}, func(FixupTarget, string) {
+		t.Fatal("expected no post-commit run without a commit")
+	})
+	if err == nil || created != 0 {
+		t.Fatalf("expected the hook failure to stop the fixup, got %d, %v", created, err)
+	}
+	if subjects := runGit(t, repoDir, "log", "--format=%s"); subjects != "feat: add a\n" {
+		t.Fatalf("expected no fixup commit, got:\n%s", subjects)
+	}
+	if staged := runGit(t, repoDir, "diff", "--cached", "--name-only"); staged != "a.txt\n" {
+		t.Fatalf("expected the hunk to be staged again, got %q", staged)
+	}
+}
@@ -0,0 +1,219 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// OperationKind names a multi-step git operation that can be interrupted.
+type OperationKind string
+
+const (
+	OperationNone       OperationKind = ""
+	OperationMerge      OperationKind = "merge"
+	OperationRebase     OperationKind = "rebase"
+	OperationCherryPick OperationKind = "cherry-pick"
+	OperationRevert     OperationKind = "revert"
+	OperationAm         OperationKind = "am"
+)
+
+// Operation describes a merge, rebase, cherry-pick, revert or am in progress.
+type Operation struct {
+	Kind OperationKind
+	// Head is the commit being merged, picked or reverted, when git records one.
+	Head string
+	// Message is the message git prepared for the commit, without comment lines.
+	Message string
+	// Conflicts lists the paths git reported as conflicted in the prepared message.
+	Conflicts []string
+	gitDir    string
+}
+
+// InProgress reports whether an operation is under way.
+func (o Operation) InProgress() bool {
+	return o.Kind != OperationNone
+}
+
+// Title describes the operation for display, e.g. "Merge in progress".
+func (o Operation) Title() string {
+	if !o.InProgress() {
+		return ""
+	}
+	name := string(o.Kind)
+	return strings.ToUpper(name[:1]) + name[1:] + " in progress"
+}
+
+// GitDir returns the absolute path of the repository's git directory.
+func (r *Repo) GitDir() (string, error) {
+	out, err := r.run("rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return "", fmt.Errorf("failed to locate git directory: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// HooksDir returns the absolute path of the directory git runs hooks from,
+// which honours core.hooksPath.
+func (r *Repo) HooksDir() (string, error) {
+	out, err := r.run("rev-parse", "--path-format=absolute", "--git-path", "hooks")
+	if err != nil {
+		return "", fmt.Errorf("failed to locate hooks directory: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// InProgress detects an interrupted merge, rebase, cherry-pick, revert or am.
+func (r *Repo) InProgress() (Operation, error) {
+	gitDir, err := r.GitDir()
+	if err != nil {
+		return Operation{}, err
+	}
+
+	op := Operation{gitDir: gitDir}
+	switch {
+	case pathExists(gitDir, "rebase-merge"):
+		op.Kind = OperationRebase
+		op.Head = readGitFile(gitDir, "REBASE_HEAD")
+		op.Message = readMessageFile(gitDir, "rebase-merge", "message")
+	case pathExists(gitDir, "rebase-apply", "applying"):
+		op.Kind = OperationAm
+		op.Message = readMessageFile(gitDir, "rebase-apply", "final-commit")
+	case pathExists(gitDir, "rebase-apply"):
+		op.Kind = OperationRebase
+		op.Head = readGitFile(gitDir, "REBASE_HEAD")
+		op.Message = readMessageFile(gitDir, "rebase-apply", "final-commit")
+	case pathExists(gitDir, "CHERRY_PICK_HEAD"):
+		op.Kind = OperationCherryPick
+		op.Head = readGitFile(gitDir, "CHERRY_PICK_HEAD")
+	case pathExists(gitDir, "REVERT_HEAD"):
+		op.Kind = OperationRevert
+		op.Head = readGitFile(gitDir, "REVERT_HEAD")
+	case pathExists(gitDir, "MERGE_HEAD"):
+		op.Kind = OperationMerge
+		op.Head = readGitFile(gitDir, "MERGE_HEAD")
+	default:
+		return Operation{}, nil
+	}
+
+	if msg := readMessageFile(gitDir, "MERGE_MSG"); msg != "" {
+		op.Message = msg
+	}
+	op.Conflicts = conflictsFromMessage(readGitFile(gitDir, "MERGE_MSG"))
+	return op, nil
+}
+
+// ContinueOperation commits message for the interrupted operation and
+// resumes it. The step is concluded with a regular commit, so opts apply as
+// they do to any other commit; a rebase or am keeps the author of the commit
+// being replayed unless opts override it. Git then replays whatever is left.
+func (r *Repo) ContinueOperation(op Operation, message string, opts CommitOptions) error {
+	if !op.InProgress() {
+		return fmt.Errorf("no operation in progress")
+	}
+
+	if op.Kind == OperationRebase || op.Kind == OperationAm {
+		author, date := readAuthorScript(op.gitDir)
+		if opts.Author == "" {
+			opts.Author = author
+		}
+		if opts.Date == "" {
+			opts.Date = date
+		}
+	}
+	if err := r.Commit(message, opts); err != nil {
+		return err
+	}
+
+	// A cherry-pick or revert of a single commit is finished by the commit;
+	// one of several, a rebase or an am goes on to the next commit. The apply
+	// backend of rebase and am must skip the patch that is now committed.
+	var args []string
+	switch {
+	case pathExists(op.gitDir, "rebase-merge"):
+		args = []string{"rebase", "--continue"}
+	case pathExists(op.gitDir, "rebase-apply"):
+		args = []string{string(op.Kind), "--skip"}
+	case pathExists(op.gitDir, "sequencer"):
+		args = []string{string(op.Kind), "--continue"}
+	default:
+		return nil
+	}
+	if _, err := r.runWith(Command{
+		Args: args,
+		// Accept prepared messages instead of opening an editor.
+		Env: []string{"GIT_EDITOR=true"},
+	}); err != nil {
+		return fmt.Errorf("failed to continue %s: %w", op.Kind, err)
+	}
+	return nil
+}
+
+// readAuthorScript reads the author and date of the commit a rebase or am
+// is replaying, from the shell-quoted author-script git writes for it.
+func readAuthorScript(gitDir string) (author, date string) {
+	script := readGitFile(gitDir, "rebase-merge", "author-script")
+	if script == "" {
+		script = readGitFile(gitDir, "rebase-apply", "author-script")
+	}
+	values := map[string]string{}
+	for _, line := range strings.Split(script, "\n") {
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSuffix(strings.TrimPrefix(value, "'"), "'")
+		values[key] = strings.ReplaceAll(value, `'\''`, "'")
+	}
+	if name, email := values["GIT_AUTHOR_NAME"], values["GIT_AUTHOR_EMAIL"]; name != "" && email != "" {
+		author = name + " <" + email + ">"
+	}
+	return author, values["GIT_AUTHOR_DATE"]
+}
+
+func pathExists(gitDir string, parts ...string) bool {
+	_, err := os.Stat(filepath.Join(append([]string{gitDir}, parts...)...))
+	return err == nil
+}
+
+func readGitFile(gitDir string, parts ...string) string {
+	data, err := os.ReadFile(filepath.Join(append([]string{gitDir}, parts...)...))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// readMessageFile reads a prepared commit message, dropping '#' comment lines.
+func readMessageFile(gitDir string, parts ...string) string {
+	var lines []string
+	for _, line := range strings.Split(readGitFile(gitDir, parts...), "\n") {
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// conflictsFromMessage reads the "# Conflicts:" list git appends to MERGE_MSG.
+func conflictsFromMessage(message string) []string {
+	var conflicts []string
+	inList := false
+	for _, line := range strings.Split(message, "\n") {
+		if !strings.HasPrefix(line, "#") {
+			inList = false
+			continue
+		}
+		text := strings.TrimSpace(strings.TrimPrefix(line, "#"))
+		switch {
+		case text == "Conflicts:":
+			inList = true
+		case inList && text != "":
+			conflicts = append(conflicts, text)
+		case inList:
+			inList = false
+		}
+	}
+	return conflicts
+}
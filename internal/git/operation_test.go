@@ -0,0 +1,203 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// conflictRepo creates a repository where branch "side" and "main" both change f.txt.
+func conflictRepo(t *testing.T) string {
+	t.Helper()
+
+	repoDir := t.TempDir()
+	runGit(t, repoDir, "init", "-b", "main")
+	runGit(t, repoDir, "config", "user.email", "test@example.com")
+	runGit(t, repoDir, "config", "user.name", "Test User")
+
+	write := func(content string) {
+		if err := os.WriteFile(filepath.Join(repoDir, "f.txt"), []byte(content), 0o644); err != nil {
+			t.Fatalf("write file: %v", err)
+		}
+	}
+	write("base\n")
+	runGit(t, repoDir, "add", "f.txt")
+	runGit(t, repoDir, "commit", "-m", "chore: base")
+	runGit(t, repoDir, "checkout", "-b", "side")
+	write("side\n")
+	runGit(t, repoDir, "commit", "-am", "feat: side change")
+	runGit(t, repoDir, "checkout", "main")
+	write("main\n")
+	runGit(t, repoDir, "commit", "-am", "feat: main change")
+	return repoDir
+}
+
+func runGitAllowFailure(dir string, args ...string) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	_ = cmd.Run()
+}
+
+func TestInProgressDetectsMergeWithConflicts(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repoDir := conflictRepo(t)
+	repo := NewRepo(repoDir, nil)
+
+	op, err := repo.InProgress()
+	if err != nil || op.InProgress() {
+		t.Fatalf("expected no operation, got %+v (%v)", op, err)
+	}
+
+	runGitAllowFailure(repoDir, "merge", "side")
+
+	op, err = repo.InProgress()
+	if err != nil {
+		t.Fatalf("InProgress returned error: %v", err)
+	}
+	if op.Kind != OperationMerge || op.Head == "" {
+		t.Fatalf("expected merge in progress, got %+v", op)
+	}
+	if !strings.HasPrefix(op.Message, "Merge branch 'side'") {
+		t.Fatalf("expected prepared merge message, got %q", op.Message)
+	}
+	if len(op.Conflicts) != 1 || op.Conflicts[0] != "f.txt" {
+		t.Fatalf("expected f.txt conflict, got %v", op.Conflicts)
+	}
+
+	changes, err := repo.ListChanges()
+	if err != nil {
+		t.Fatalf("ListChanges returned error: %v", err)
+	}
+	if len(changes) != 1 || !changes[0].Conflict || changes[0].Status != "conflicted" {
+		t.Fatalf("expected conflicted f.txt, got %+v", changes)
+	}
+}
+
+func TestContinueOperationUsesMessage(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	tests := []struct {
+		name  string
+		start []string
+		kind  OperationKind
+	}{
+		{name: "cherry-pick", start: []string{"cherry-pick", "side"}, kind: OperationCherryPick},
+		{name: "rebase", start: []string{"rebase", "side"}, kind: OperationRebase},
+		{name: "merge", start: []string{"merge", "side"}, kind: OperationMerge},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repoDir := conflictRepo(t)
+			repo := NewRepo(repoDir, nil)
+			runGitAllowFailure(repoDir, tc.start...)
+
+			op, err := repo.InProgress()
+			if err != nil || op.Kind != tc.kind {
+				t.Fatalf("expected %s in progress, got %+v (%v)", tc.kind, op, err)
+			}
+
+			if err := os.WriteFile(filepath.Join(repoDir, "f.txt"), []byte("resolved\n"), 0o644); err != nil {
+				t.Fatalf("write file: %v", err)
+			}
+			runGit(t, repoDir, "add", "f.txt")
+
+			message := "fix: resolve f.txt conflict\n\nKeep both intents in a single line."
+			if err := repo.ContinueOperation(op, message, CommitOptions{}); err != nil {
+				t.Fatalf("ContinueOperation returned error: %v", err)
+			}
+
+			if subject := strings.TrimSpace(runGit(t, repoDir, "log", "-1", "--pretty=%s")); subject != "fix: resolve f.txt conflict" {
+				t.Fatalf("expected generated subject, got %q", subject)
+			}
+			if op, _ := repo.InProgress(); op.InProgress() {
+				t.Fatalf("expected operation to be finished, got %+v", op)
+			}
+		})
+	}
+}
+
+func TestContinueOperationAppliesCommitOptions(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repoDir := conflictRepo(t)
+	repo := NewRepo(repoDir, nil)
+	runGitAllowFailure(repoDir, "cherry-pick", "side")
+	op, err := repo.InProgress()
+	if err != nil || op.Kind != OperationCherryPick {
+		t.Fatalf("expected cherry-pick in progress, got %+v (%v)", op, err)
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, "f.txt"), []byte("resolved\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	runGit(t, repoDir, "add", "f.txt")
+
+	opts := CommitOptions{Signoff: true, Trailers: []string{"Refs: #42"}}
+	if err := repo.ContinueOperation(op, "fix: resolve f.txt conflict", opts); err != nil {
+		t.Fatalf("ContinueOperation returned error: %v", err)
+	}
+	body := runGit(t, repoDir, "log", "-1", "--pretty=%B")
+	for _, want := range []string{"Signed-off-by: Test User <test@example.com>", "Refs: #42"} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected %q in the message, got %q", want, body)
+		}
+	}
+	if op, _ := repo.InProgress(); op.InProgress() {
+		t.Fatalf("expected the cherry-pick to be finished, got %+v", op)
+	}
+}
+
+func TestContinueRebaseKeepsReplayedAuthor(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repoDir := conflictRepo(t)
+	repo := NewRepo(repoDir, nil)
+	runGit(t, repoDir, "checkout", "side")
+	runGit(t, repoDir, "commit", "--amend", "--no-edit", "--author", "Alice <alice@example.com>", "--date", "2020-01-02T03:04:05Z")
+	if err := os.WriteFile(filepath.Join(repoDir, "g.txt"), []byte("g\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	runGit(t, repoDir, "add", "g.txt")
+	runGit(t, repoDir, "commit", "-m", "feat: add g")
+	runGitAllowFailure(repoDir, "rebase", "main")
+
+	op, err := repo.InProgress()
+	if err != nil || op.Kind != OperationRebase {
+		t.Fatalf("expected rebase in progress, got %+v (%v)", op, err)
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, "f.txt"), []byte("resolved\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	runGit(t, repoDir, "add", "f.txt")
+
+	if err := repo.ContinueOperation(op, "feat: side change", CommitOptions{Signoff: true}); err != nil {
+		t.Fatalf("ContinueOperation returned error: %v", err)
+	}
+	if op, _ := repo.InProgress(); op.InProgress() {
+		t.Fatalf("expected the rebase to finish, got %+v", op)
+	}
+	log := runGit(t, repoDir, "log", "-3", "--pretty=%s|%an|%ad|%(trailers:key=Signed-off-by,valueonly)", "--date=iso-strict")
+	var lines []string
+	for _, line := range strings.Split(log, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "feat: add g|") {
+		t.Fatalf("expected the remaining commit replayed on top, got:\n%s", log)
+	}
+	if want := "feat: side change|Alice|2020-01-02T03:04:05+00:00|Test User <test@example.com>"; lines[1] != want {
+		t.Fatalf("resolved commit = %q, want %q", lines[1], want)
+	}
+}
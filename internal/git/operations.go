@@ -3,6 +3,7 @@ package git
 import (
 	"errors"
 	"fmt"
+	"os"
 	"os/exec"
 	"strings"
 )
@@ -11,15 +12,18 @@ const (
 	notInGitRepoMessage = "run this tool inside a git repository"
 )
 
-// EnsureGitRepository checks if the current directory is inside a git repository
-func EnsureGitRepository() error {
-	out, err := exec.Command("git", "rev-parse", "--is-inside-work-tree").CombinedOutput()
+// EnsureRepository checks if the repository directory is inside a git work tree
+func (r *Repo) EnsureRepository() error {
+	out, err := r.run("rev-parse", "--is-inside-work-tree")
 	if err != nil {
 		if errors.Is(err, exec.ErrNotFound) {
 			return fmt.Errorf("git is not installed or not in PATH")
 		}
 
-		output := strings.TrimSpace(string(out))
+		output := strings.TrimSpace(err.Error())
+		if cmdErr, ok := errors.AsType[*CommandError](err); ok {
+			output = strings.TrimSpace(cmdErr.Stderr + cmdErr.Stdout)
+		}
 		if strings.Contains(strings.ToLower(output), "not a git repository") {
 			return fmt.Errorf(notInGitRepoMessage)
 		}
@@ -37,126 +41,216 @@ func EnsureGitRepository() error {
 	return nil
 }
 
-// GetStagedDiff returns the diff of staged changes
-func GetStagedDiff() ([]byte, error) {
-	if err := EnsureGitRepository(); err != nil {
-		return nil, err
+// ConfigValue returns a git config value, or an empty string when it is unset.
+func (r *Repo) ConfigValue(key string) (string, error) {
+	out, err := r.run("config", "--get", key)
+	if err != nil {
+		if cmdErr, ok := errors.AsType[*CommandError](err); ok && cmdErr.ExitCode() == 1 {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read git config %s: %w", key, err)
 	}
+	return strings.TrimSpace(string(out)), nil
+}
 
-	diff, err := exec.Command("git", "diff", "--cached").CombinedOutput()
+// GetStagedDiff returns the diff of staged changes
+func (r *Repo) GetStagedDiff() ([]byte, error) {
+	diff, err := r.run("diff", "--cached")
 	if err != nil {
-		output := strings.TrimSpace(string(diff))
-		if output == "" {
-			return nil, fmt.Errorf("failed to read staged changes: %w", err)
+		return nil, fmt.Errorf("failed to read staged changes: %w", err)
+	}
+
+	return diff, nil
+}
+
+// GetAmendDiff returns the diff the amended HEAD commit would contain: the
+// index against HEAD's parent, or against the empty tree for a root commit.
+func (r *Repo) GetAmendDiff() ([]byte, error) {
+	base := "HEAD~1"
+	if _, err := r.run("rev-parse", "--verify", "--quiet", "HEAD~1"); err != nil {
+		tree, err := r.EmptyTree()
+		if err != nil {
+			return nil, err
 		}
-		return nil, fmt.Errorf("failed to read staged changes: %s", output)
+		base = tree
+	}
+
+	diff, err := r.run("diff", "--cached", "--no-color", "--no-ext-diff", base)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the amended commit's changes: %w", err)
+	}
+
+	return diff, nil
+}
+
+// EmptyTree returns the ID of the empty tree in the repository's hash format.
+func (r *Repo) EmptyTree() (string, error) {
+	out, err := r.runWith(Command{
+		Args:  []string{"hash-object", "-t", "tree", "--stdin"},
+		Stdin: strings.NewReader(""),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to compute the empty tree: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// GetWorkingDiff returns the diff of all tracked changes, staged or not, against HEAD
+func (r *Repo) GetWorkingDiff() ([]byte, error) {
+	diff, err := r.run("diff", "HEAD", "--no-color", "--no-ext-diff")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read working tree changes: %w", err)
 	}
 
 	return diff, nil
 }
 
 // GetFileDiff returns the diff for a specific file
-func GetFileDiff(path string) ([]byte, error) {
-	if err := EnsureGitRepository(); err != nil {
-		return nil, err
+func (r *Repo) GetFileDiff(path string) ([]byte, error) {
+	diff, err := r.run("diff", "--no-color", "--no-ext-diff", "--", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file diff: %w", err)
 	}
 
-	diff, err := exec.Command("git", "diff", "--", path).CombinedOutput()
+	return diff, nil
+}
+
+// GetStagedFileDiff returns the staged diff for a specific file
+func (r *Repo) GetStagedFileDiff(path string) ([]byte, error) {
+	diff, err := r.run("diff", "--cached", "--no-color", "--no-ext-diff", "--", path)
 	if err != nil {
-		output := strings.TrimSpace(string(diff))
-		if output == "" {
-			return nil, fmt.Errorf("failed to read file diff: %w", err)
-		}
-		return nil, fmt.Errorf("failed to read file diff: %s", output)
+		return nil, fmt.Errorf("failed to read staged file diff: %w", err)
 	}
 
 	return diff, nil
 }
 
-// ListUnstagedChanges returns a list of files with unstaged changes
-func ListUnstagedChanges() ([]ChangedFile, error) {
-	if err := EnsureGitRepository(); err != nil {
-		return nil, err
+// ListChanges returns every changed, conflicted or untracked file with
+// separate index and work tree states.
+func (r *Repo) ListChanges() ([]ChangedFile, error) {
+	out, err := r.run("status", "--porcelain=v2", "-z", "--untracked-files=all")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read changes: %w", err)
 	}
+	return parseStatusV2(out)
+}
 
-	out, err := exec.Command("git", "status", "--porcelain").CombinedOutput()
+// ListUnstagedChanges returns a list of files with unstaged changes
+func (r *Repo) ListUnstagedChanges() ([]ChangedFile, error) {
+	changes, err := r.ListChanges()
 	if err != nil {
-		output := strings.TrimSpace(string(out))
-		if output == "" {
-			return nil, fmt.Errorf("failed to read unstaged changes: %w", err)
+		return nil, fmt.Errorf("failed to read unstaged changes: %w", err)
+	}
+
+	unstaged := make([]ChangedFile, 0, len(changes))
+	for _, change := range changes {
+		if change.HasUnstagedChanges() {
+			unstaged = append(unstaged, change)
 		}
-		return nil, fmt.Errorf("failed to read unstaged changes: %s", output)
 	}
+	return unstaged, nil
+}
 
-	lines := strings.Split(strings.TrimRight(string(out), "\r\n"), "\n")
-	changes := make([]ChangedFile, 0, len(lines))
-	seen := make(map[string]struct{}, len(lines))
+// parseStatusV2 parses `git status --porcelain=v2 -z` output.
+func parseStatusV2(out []byte) ([]ChangedFile, error) {
+	records := strings.Split(string(out), "\x00")
+	changes := make([]ChangedFile, 0, len(records))
 
-	for _, line := range lines {
-		if strings.TrimSpace(line) == "" || len(line) < 3 {
+	for i := 0; i < len(records); i++ {
+		record := records[i]
+		if record == "" {
 			continue
 		}
 
-		statusCode := line[:2]
-		rawPath := strings.TrimSpace(line[3:])
-		if rawPath == "" {
-			continue
-		}
+		switch record[0] {
+		case '?':
+			changes = append(changes, ChangedFile{
+				Path:      strings.TrimPrefix(record, "? "),
+				Status:    "untracked",
+				Untracked: true,
+			})
+		case '1', '2', 'u':
+			fieldCount := map[byte]int{'1': 9, '2': 10, 'u': 11}[record[0]]
+			fields := strings.SplitN(record, " ", fieldCount)
+			if len(fields) != fieldCount || len(fields[1]) != 2 {
+				return nil, fmt.Errorf("unexpected status entry %q", record)
+			}
 
-		switch {
-		case statusCode == "??":
-			if _, exists := seen[rawPath]; exists {
-				continue
+			change := ChangedFile{
+				Path:           fields[fieldCount-1],
+				IndexStatus:    fields[1][0],
+				WorktreeStatus: fields[1][1],
+				Conflict:       record[0] == 'u',
 			}
-			seen[rawPath] = struct{}{}
-			changes = append(changes, ChangedFile{Path: rawPath, Status: "untracked"})
-		case statusCode[1] != ' ':
-			path := rawPath
-			if strings.Contains(rawPath, " -> ") {
-				parts := strings.SplitN(rawPath, " -> ", 2)
-				path = strings.TrimSpace(parts[1])
+			if fields[2] != "N..." {
+				change.Submodule = fields[2]
 			}
-			if _, exists := seen[path]; exists {
-				continue
+			if record[0] == '2' {
+				// Renames and copies carry the original path as the next record.
+				if i+1 >= len(records) {
+					return nil, fmt.Errorf("missing original path for %q", change.Path)
+				}
+				i++
+				change.OrigPath = records[i]
 			}
-			seen[path] = struct{}{}
-			changes = append(changes, ChangedFile{Path: path, Status: statusLabel(statusCode[1])})
+			change.Status = changeLabel(change)
+			changes = append(changes, change)
 		}
 	}
 
 	return changes, nil
 }
 
+// changeLabel describes the most relevant state of a change for display.
+func changeLabel(change ChangedFile) string {
+	switch {
+	case change.Conflict:
+		return statusLabel('U')
+	case change.WorktreeStatus != '.':
+		return statusLabel(change.WorktreeStatus)
+	default:
+		return statusLabel(change.IndexStatus)
+	}
+}
+
 // StageFiles stages the specified files
-func StageFiles(paths []string) error {
+func (r *Repo) StageFiles(paths []string) error {
 	if len(paths) == 0 {
 		return nil
 	}
 
 	args := append([]string{"add", "--"}, paths...)
-	out, err := exec.Command("git", args...).CombinedOutput()
-	if err != nil {
-		output := strings.TrimSpace(string(out))
-		if output == "" {
-			return fmt.Errorf("failed to stage selected files: %w", err)
-		}
-		return fmt.Errorf("failed to stage selected files: %s", output)
+	if _, err := r.run(args...); err != nil {
+		return fmt.Errorf("failed to stage selected files: %w", err)
 	}
 	return nil
 }
 
-// Commit creates a new commit with the given message
-func Commit(message string) error {
-	out, err := exec.Command("git", "commit", "-m", message).CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("%s", string(out))
+// UnstageFiles removes the specified files from the index, keeping work tree changes
+func (r *Repo) UnstageFiles(paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+
+	// Before the first commit there is no HEAD to restore from.
+	args := append([]string{"restore", "--staged", "--"}, paths...)
+	if !r.CanAmend() {
+		args = append([]string{"rm", "--cached", "-r", "--quiet", "--"}, paths...)
+	}
+	if _, err := r.run(args...); err != nil {
+		return fmt.Errorf("failed to unstage selected files: %w", err)
 	}
 	return nil
 }
 
+// Commit creates a new commit with the given message
+func (r *Repo) Commit(message string, opts CommitOptions) error {
+	return r.commit(message, nil, opts)
+}
+
 // CanAmend checks if there is a commit to amend
-func CanAmend() bool {
-	out, err := exec.Command("git", "rev-parse", "HEAD").CombinedOutput()
+func (r *Repo) CanAmend() bool {
+	out, err := r.run("rev-parse", "--verify", "--quiet", "HEAD")
 	if err != nil {
 		return false
 	}
@@ -164,16 +258,16 @@ func CanAmend() bool {
 }
 
 // GetLastCommit returns information about the last commit
-func GetLastCommit() (*CommitInfo, error) {
+func (r *Repo) GetLastCommit() (*CommitInfo, error) {
 	// Get commit hash
-	hashOut, err := exec.Command("git", "rev-parse", "HEAD").CombinedOutput()
+	hashOut, err := r.run("rev-parse", "HEAD")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get commit hash: %w", err)
 	}
 	hash := strings.TrimSpace(string(hashOut))
 
 	// Get commit message and details
-	logOut, err := exec.Command("git", "log", "-1", "--pretty=format:%an%n%ad%n%s%n%b", "HEAD").CombinedOutput()
+	logOut, err := r.run("log", "-1", "--pretty=format:%an%n%ad%n%s%n%b", "HEAD")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get commit details: %w", err)
 	}
@@ -197,26 +291,56 @@ func GetLastCommit() (*CommitInfo, error) {
 }
 
 // AmendCommit amends the last commit with a new message
-func AmendCommit(message string) error {
-	out, err := exec.Command("git", "commit", "--amend", "-m", message).CombinedOutput()
+func (r *Repo) AmendCommit(message string, opts CommitOptions) error {
+	return r.commit(message, []string{"--amend"}, opts)
+}
+
+// commit runs git commit with the message read from a temporary file, so long
+// bodies survive and lines starting with '#' are not stripped as comments.
+func (r *Repo) commit(message string, extra []string, opts CommitOptions) error {
+	file, err := os.CreateTemp("", "commiter-msg-*.txt")
 	if err != nil {
-		return fmt.Errorf("%s", string(out))
+		return fmt.Errorf("failed to create commit message file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.WriteString(message); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write commit message file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write commit message file: %w", err)
+	}
+
+	args := append([]string{"commit", "--cleanup=whitespace", "-F", file.Name()}, extra...)
+	args = append(args, opts.args()...)
+	if _, err := r.run(args...); err != nil {
+		return err
 	}
 	return nil
 }
 
 // GetCommitHistory returns the last n commits
-func GetCommitHistory(limit int) ([]CommitInfo, error) {
-	if err := EnsureGitRepository(); err != nil {
-		return nil, err
-	}
+func (r *Repo) GetCommitHistory(limit int) ([]CommitInfo, error) {
+	return r.CommitHistory(HistoryFilter{}, 0, limit)
+}
 
-	args := []string{"log", fmt.Sprintf("--max-count=%d", limit), "--pretty=format:%H%x00%an%x00%ad%x00%s"}
-	out, err := exec.Command("git", args...).CombinedOutput()
+// CommitHistory returns up to limit commits matching filter, after skipping
+// the first skip of them, so the history can be read a page at a time.
+func (r *Repo) CommitHistory(filter HistoryFilter, skip, limit int) ([]CommitInfo, error) {
+	args := []string{"log", fmt.Sprintf("--max-count=%d", limit), fmt.Sprintf("--skip=%d", skip), "--pretty=format:%H%x00%an%x00%ad%x00%s%x00%P%x00%D"}
+	args = append(args, filter.Args()...)
+	out, err := r.run(args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get commit history: %w", err)
 	}
 
+	return parseCommitLog(out), nil
+}
+
+// parseCommitLog reads log output in the "%H%x00%an%x00%ad%x00%s" format,
+// optionally followed by "%x00%P%x00%D".
+func parseCommitLog(out []byte) []CommitInfo {
 	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
 	commits := make([]CommitInfo, 0, len(lines))
 
@@ -230,25 +354,28 @@ func GetCommitHistory(limit int) ([]CommitInfo, error) {
 			continue
 		}
 
-		commits = append(commits, CommitInfo{
+		commit := CommitInfo{
 			Hash:    parts[0],
 			Author:  parts[1],
 			Date:    parts[2],
 			Subject: parts[3],
-		})
+		}
+		if len(parts) >= 6 {
+			commit.Parents = strings.Fields(parts[4])
+			if parts[5] != "" {
+				commit.Refs = strings.Split(parts[5], ", ")
+			}
+		}
+		commits = append(commits, commit)
 	}
 
-	return commits, nil
+	return commits
 }
 
 // GetCommitDetails returns detailed information and diff for a commit
-func GetCommitDetails(hash string) (*CommitInfo, string, error) {
-	if err := EnsureGitRepository(); err != nil {
-		return nil, "", err
-	}
-
+func (r *Repo) GetCommitDetails(hash string) (*CommitInfo, string, error) {
 	// Get commit details
-	logOut, err := exec.Command("git", "log", "-1", "--pretty=format:%H%x00%an%x00%ad%x00%s%x00%b", hash).CombinedOutput()
+	logOut, err := r.run("log", "-1", "--pretty=format:%H%x00%an%x00%ad%x00%s%x00%b", hash)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to get commit details: %w", err)
 	}
@@ -272,7 +399,7 @@ func GetCommitDetails(hash string) (*CommitInfo, string, error) {
 	}
 
 	// Get commit diff
-	diffOut, err := exec.Command("git", "show", "--pretty=format:", hash).CombinedOutput()
+	diffOut, err := r.run("show", "--pretty=format:", hash)
 	if err != nil {
 		return info, "", fmt.Errorf("failed to get commit diff: %w", err)
 	}
@@ -293,8 +420,42 @@ func statusLabel(code byte) string {
 	case 'C':
 		return "copied"
 	case 'U':
-		return "unmerged"
+		return "conflicted"
 	default:
 		return "changed"
 	}
 }
+
+// CommitMessage is the full message of a commit.
+type CommitMessage struct {
+	Hash    string
+	Message string
+}
+
+// CommitMessages returns the messages of the commits in revRange, oldest
+// first, leaving out merge commits. An empty range means the commits on HEAD
+// that are on no remote branch.
+func (r *Repo) CommitMessages(revRange string) ([]CommitMessage, error) {
+	args := []string{"log", "--reverse", "--no-merges", "--format=%H%x00%B%x1e"}
+	if revRange == "" {
+		args = append(args, "HEAD", "--not", "--remotes")
+	} else {
+		args = append(args, revRange)
+	}
+	args = append(args, "--")
+
+	out, err := r.run(args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commit messages: %w", err)
+	}
+
+	var messages []CommitMessage
+	for _, record := range strings.Split(string(out), "\x1e") {
+		hash, message, ok := strings.Cut(strings.TrimLeft(record, "\n"), "\x00")
+		if !ok {
+			continue
+		}
+		messages = append(messages, CommitMessage{Hash: hash, Message: strings.TrimSpace(message)})
+	}
+	return messages, nil
+}
@@ -13,16 +13,13 @@ func TestEnsureGitRepositoryOutsideRepo(t *testing.T) {
 		t.Skip("git is not installed")
 	}
 
-	tempDir := t.TempDir()
-	withWorkingDir(t, tempDir, func() {
-		err := EnsureGitRepository()
-		if err == nil {
-			t.Fatal("expected error outside git repository")
-		}
-		if !strings.Contains(err.Error(), notInGitRepoMessage) {
-			t.Fatalf("expected %q in error, got %q", notInGitRepoMessage, err.Error())
-		}
-	})
+	_, err := Open(t.TempDir(), nil)
+	if err == nil {
+		t.Fatal("expected error outside git repository")
+	}
+	if !strings.Contains(err.Error(), notInGitRepoMessage) {
+		t.Fatalf("expected %q in error, got %q", notInGitRepoMessage, err.Error())
+	}
 }
 
 func TestGetStagedDiff(t *testing.T) {
@@ -39,15 +36,13 @@ func TestGetStagedDiff(t *testing.T) {
 	}
 	runGit(t, repoDir, "add", "hello.txt")
 
-	withWorkingDir(t, repoDir, func() {
-		diff, err := GetStagedDiff()
-		if err != nil {
-			t.Fatalf("GetStagedDiff returned error: %v", err)
-		}
-		if !strings.Contains(string(diff), "hello.txt") {
-			t.Fatalf("expected staged diff to contain file name, got:\n%s", string(diff))
-		}
-	})
+	diff, err := NewRepo(repoDir, nil).GetStagedDiff()
+	if err != nil {
+		t.Fatalf("GetStagedDiff returned error: %v", err)
+	}
+	if !strings.Contains(string(diff), "hello.txt") {
+		t.Fatalf("expected staged diff to contain file name, got:\n%s", string(diff))
+	}
 }
 
 func TestListUnstagedChanges(t *testing.T) {
@@ -76,58 +71,446 @@ func TestListUnstagedChanges(t *testing.T) {
 		t.Fatalf("write untracked file: %v", err)
 	}
 
-	withWorkingDir(t, repoDir, func() {
-		status := runGit(t, repoDir, "status", "--porcelain")
-		t.Logf("Git status output:\n%s", status)
+	status := runGit(t, repoDir, "status", "--porcelain")
+	t.Logf("Git status output:\n%s", status)
 
-		changes, err := ListUnstagedChanges()
-		if err != nil {
-			t.Fatalf("ListUnstagedChanges returned error: %v", err)
-		}
+	changes, err := NewRepo(repoDir, nil).ListUnstagedChanges()
+	if err != nil {
+		t.Fatalf("ListUnstagedChanges returned error: %v", err)
+	}
 
-		statusByPath := map[string]string{}
-		for _, c := range changes {
-			statusByPath[c.Path] = c.Status
-		}
+	statusByPath := map[string]string{}
+	for _, c := range changes {
+		statusByPath[c.Path] = c.Status
+	}
 
-		if got := statusByPath["tracked.txt"]; got != "modified" {
-			t.Fatalf("expected tracked.txt status to be modified, got %q (full map: %#v)", got, statusByPath)
-		}
-		if got := statusByPath["new.txt"]; got != "untracked" {
-			t.Fatalf("expected new.txt status to be untracked, got %q (full map: %#v)", got, statusByPath)
+	if got := statusByPath["tracked.txt"]; got != "modified" {
+		t.Fatalf("expected tracked.txt status to be modified, got %q (full map: %#v)", got, statusByPath)
+	}
+	if got := statusByPath["new.txt"]; got != "untracked" {
+		t.Fatalf("expected new.txt status to be untracked, got %q (full map: %#v)", got, statusByPath)
+	}
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s failed: %v\n%s", strings.Join(args, " "), err, string(out))
+	}
+
+	return string(out)
+}
+
+func TestConfigValue(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repoDir := t.TempDir()
+	runGit(t, repoDir, "init")
+	runGit(t, repoDir, "config", "commiter.language", "de")
+
+	repo := NewRepo(repoDir, nil)
+	value, err := repo.ConfigValue("commiter.language")
+	if err != nil {
+		t.Fatalf("ConfigValue returned error: %v", err)
+	}
+	if value != "de" {
+		t.Fatalf("expected %q, got %q", "de", value)
+	}
+
+	missing, err := repo.ConfigValue("commiter.missing")
+	if err != nil || missing != "" {
+		t.Fatalf("expected empty value for missing key, got %q (%v)", missing, err)
+	}
+}
+
+func TestListChangesHandlesAwkwardPaths(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repoDir := t.TempDir()
+	runGit(t, repoDir, "init")
+	runGit(t, repoDir, "config", "user.email", "test@example.com")
+	runGit(t, repoDir, "config", "user.name", "Test User")
+
+	names := []string{"with space.txt", `quote"d.txt`, "ümlaut.txt", "old name.txt"}
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(repoDir, name), []byte(name+"\n"), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
 		}
+	}
+	runGit(t, repoDir, "add", ".")
+	runGit(t, repoDir, "commit", "-m", "feat: add files")
+
+	// Partially staged: one change in the index, another in the work tree.
+	partial := filepath.Join(repoDir, "with space.txt")
+	if err := os.WriteFile(partial, []byte("staged\n"), 0o644); err != nil {
+		t.Fatalf("write partial: %v", err)
+	}
+	runGit(t, repoDir, "add", "with space.txt")
+	if err := os.WriteFile(partial, []byte("staged\nunstaged\n"), 0o644); err != nil {
+		t.Fatalf("write partial: %v", err)
+	}
+
+	// Staged only.
+	if err := os.WriteFile(filepath.Join(repoDir, "ümlaut.txt"), []byte("changed\n"), 0o644); err != nil {
+		t.Fatalf("write staged: %v", err)
+	}
+	runGit(t, repoDir, "add", "ümlaut.txt")
+
+	runGit(t, repoDir, "mv", "old name.txt", "new name.txt")
+
+	if err := os.WriteFile(filepath.Join(repoDir, `new "quoted" file.txt`), []byte("new\n"), 0o644); err != nil {
+		t.Fatalf("write untracked: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, `quote"d.txt`), []byte("edited\n"), 0o644); err != nil {
+		t.Fatalf("write modified: %v", err)
+	}
+
+	repo := NewRepo(repoDir, nil)
+	changes, err := repo.ListChanges()
+	if err != nil {
+		t.Fatalf("ListChanges returned error: %v", err)
+	}
+
+	byPath := map[string]ChangedFile{}
+	for _, c := range changes {
+		byPath[c.Path] = c
+	}
+
+	if c := byPath["with space.txt"]; !c.HasStagedChanges() || !c.HasUnstagedChanges() || c.Status != "modified" {
+		t.Fatalf("expected partially staged file, got %+v", c)
+	}
+	if c := byPath["ümlaut.txt"]; !c.HasStagedChanges() || c.HasUnstagedChanges() {
+		t.Fatalf("expected staged-only file, got %+v", c)
+	}
+	if c := byPath["new name.txt"]; c.IndexStatus != 'R' || c.OrigPath != "old name.txt" || c.Status != "renamed" {
+		t.Fatalf("expected rename from old name.txt, got %+v", c)
+	}
+	if c := byPath[`new "quoted" file.txt`]; !c.Untracked {
+		t.Fatalf("expected untracked quoted file, got %+v (all: %+v)", c, changes)
+	}
+
+	unstaged, err := repo.ListUnstagedChanges()
+	if err != nil {
+		t.Fatalf("ListUnstagedChanges returned error: %v", err)
+	}
+	paths := make([]string, 0, len(unstaged))
+	for _, c := range unstaged {
+		paths = append(paths, c.Path)
+	}
+	if strings.Contains(strings.Join(paths, "|"), "ümlaut.txt") {
+		t.Fatalf("expected staged-only file to be excluded from unstaged list, got %v", paths)
+	}
+
+	if err := repo.StageFiles(paths); err != nil {
+		t.Fatalf("StageFiles returned error for %v: %v", paths, err)
+	}
+	remaining, err := repo.ListUnstagedChanges()
+	if err != nil {
+		t.Fatalf("ListUnstagedChanges returned error: %v", err)
+	}
+	if len(remaining) != 0 {
+		t.Fatalf("expected everything staged, got %+v", remaining)
+	}
+}
+
+func TestParseStatusV2(t *testing.T) {
+	out := strings.Join([]string{
+		"1 .M N... 100644 100644 100644 abc abc a b.txt",
+		"2 R. N... 100644 100644 100644 abc abc R100 new.txt",
+		"old.txt",
+		"u UU N... 100644 100644 100644 100644 a b c both.txt",
+		"1 .M SC.. 160000 160000 160000 abc abc vendor/lib",
+		"? untracked file",
+		"",
+	}, "\x00")
+
+	changes, err := parseStatusV2([]byte(out))
+	if err != nil {
+		t.Fatalf("parseStatusV2 returned error: %v", err)
+	}
+	if len(changes) != 5 {
+		t.Fatalf("expected 5 entries, got %+v", changes)
+	}
+	if changes[0].Path != "a b.txt" || changes[0].IndexStatus != '.' || changes[0].WorktreeStatus != 'M' {
+		t.Fatalf("unexpected ordinary entry: %+v", changes[0])
+	}
+	if changes[1].Path != "new.txt" || changes[1].OrigPath != "old.txt" {
+		t.Fatalf("unexpected rename entry: %+v", changes[1])
+	}
+	if !changes[2].Conflict || changes[2].Path != "both.txt" {
+		t.Fatalf("unexpected conflict entry: %+v", changes[2])
+	}
+	if !changes[3].IsSubmodule() || changes[3].Submodule != "SC.." {
+		t.Fatalf("unexpected submodule entry: %+v", changes[3])
+	}
+	if !changes[4].Untracked || changes[4].Path != "untracked file" {
+		t.Fatalf("unexpected untracked entry: %+v", changes[4])
+	}
+}
+
+func TestUnstageFilesBeforeFirstCommit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repoDir := t.TempDir()
+	runGit(t, repoDir, "init")
+	if err := os.WriteFile(filepath.Join(repoDir, "first.txt"), []byte("first\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	runGit(t, repoDir, "add", "first.txt")
+
+	repo := NewRepo(repoDir, nil)
+	if err := repo.UnstageFiles([]string{"first.txt"}); err != nil {
+		t.Fatalf("UnstageFiles returned error: %v", err)
+	}
+
+	changes, err := repo.ListChanges()
+	if err != nil {
+		t.Fatalf("ListChanges returned error: %v", err)
+	}
+	if len(changes) != 1 || !changes[0].Untracked {
+		t.Fatalf("expected first.txt to be untracked again, got %+v", changes)
+	}
+}
+
+func TestCommitWithOptions(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repoDir := t.TempDir()
+	runGit(t, repoDir, "init")
+	runGit(t, repoDir, "config", "user.email", "test@example.com")
+	runGit(t, repoDir, "config", "user.name", "Test User")
+	if err := os.WriteFile(filepath.Join(repoDir, "a.txt"), []byte("a\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	runGit(t, repoDir, "add", "a.txt")
+
+	message := "fix: keep markdown headings\n\n# Heading kept in the body\n" + strings.Repeat("long body line\n", 200)
+	err := NewRepo(repoDir, nil).Commit(message, CommitOptions{
+		Signoff:  true,
+		Author:   "Jane Doe <jane@example.com>",
+		Date:     "2024-01-02T03:04:05Z",
+		Trailers: []string{"Reviewed-by: Sam <sam@example.com>"},
 	})
+	if err != nil {
+		t.Fatalf("Commit returned error: %v", err)
+	}
+
+	body := runGit(t, repoDir, "log", "-1", "--pretty=%B")
+	if !strings.Contains(body, "# Heading kept in the body") {
+		t.Fatalf("expected '#' line to survive, got:\n%s", body)
+	}
+	if !strings.Contains(body, "Signed-off-by: Test User <test@example.com>") {
+		t.Fatalf("expected signoff trailer, got:\n%s", body)
+	}
+	if !strings.Contains(body, "Reviewed-by: Sam <sam@example.com>") {
+		t.Fatalf("expected custom trailer, got:\n%s", body)
+	}
+
+	author := strings.TrimSpace(runGit(t, repoDir, "log", "-1", "--pretty=%an <%ae>|%aI"))
+	if author != "Jane Doe <jane@example.com>|2024-01-02T03:04:05+00:00" {
+		t.Fatalf("unexpected author/date %q", author)
+	}
 }
 
-func withWorkingDir(t *testing.T, dir string, fn func()) {
-	t.Helper()
+func TestCommitOptionsArgs(t *testing.T) {
+	opts := CommitOptions{Sign: true, SigningKey: "ABC123", NoVerify: true}
+	got := strings.Join(opts.args(), " ")
+	if got != "--gpg-sign=ABC123 --no-verify" {
+		t.Fatalf("unexpected args %q", got)
+	}
+
+	opts.Sign = false
+	if got := strings.Join(opts.args(), " "); got != "--no-verify" {
+		t.Fatalf("expected signing key to be ignored when signing is off, got %q", got)
+	}
+}
 
-	originalDir, err := os.Getwd()
+func TestCommitMessages(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repoDir := t.TempDir()
+	runGit(t, repoDir, "init", "-b", "main")
+	runGit(t, repoDir, "config", "user.email", "test@example.com")
+	runGit(t, repoDir, "config", "user.name", "Test User")
+	runGit(t, repoDir, "commit", "--allow-empty", "-m", "chore: init")
+	runGit(t, repoDir, "update-ref", "refs/remotes/origin/main", "HEAD")
+	runGit(t, repoDir, "commit", "--allow-empty", "-m", "feat: first", "-m", "Body with\n\nparagraphs.")
+	runGit(t, repoDir, "commit", "--allow-empty", "-m", "fix: second")
+
+	repo := NewRepo(repoDir, ExecRunner{})
+	messages, err := repo.CommitMessages("")
 	if err != nil {
-		t.Fatalf("get working directory: %v", err)
+		t.Fatalf("CommitMessages() error = %v", err)
+	}
+	if len(messages) != 2 {
+		t.Fatalf("expected the 2 unpushed commits, got %+v", messages)
+	}
+	if messages[0].Message != "feat: first\n\nBody with\n\nparagraphs." || messages[1].Message != "fix: second" {
+		t.Fatalf("unexpected messages: %+v", messages)
+	}
+	if head := strings.TrimSpace(runGit(t, repoDir, "rev-parse", "HEAD")); messages[1].Hash != head {
+		t.Fatalf("expected the newest commit last, got %s", messages[1].Hash)
 	}
 
-	if err := os.Chdir(dir); err != nil {
-		t.Fatalf("change to %s: %v", dir, err)
+	messages, err = repo.CommitMessages("HEAD~1..HEAD")
+	if err != nil || len(messages) != 1 || messages[0].Message != "fix: second" {
+		t.Fatalf("CommitMessages(range) = %+v, %v", messages, err)
+	}
+}
+
+func TestCommitHistoryFiltersAndPages(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repoDir := t.TempDir()
+	runGit(t, repoDir, "init", "-b", "main")
+	runGit(t, repoDir, "config", "user.email", "test@example.com")
+	runGit(t, repoDir, "config", "user.name", "Test User")
+	runGit(t, repoDir, "commit", "--allow-empty", "-m", "chore: init")
+	if err := os.WriteFile(filepath.Join(repoDir, "parse.go"), []byte("func parseLog() {}\n"), 0o644); err != nil {
+		t.Fatalf("write parse.go: %v", err)
 	}
-	defer func() {
-		if err := os.Chdir(originalDir); err != nil {
-			t.Fatalf("restore working directory: %v", err)
+	runGit(t, repoDir, "add", "parse.go")
+	runGit(t, repoDir, "commit", "-m", "feat: add log parser")
+	runGit(t, repoDir, "checkout", "-b", "feature")
+	runGit(t, repoDir, "commit", "--allow-empty", "--author", "Alice <alice@example.com>", "-m", "fix: first fix")
+	runGit(t, repoDir, "commit", "--allow-empty", "-m", "fix: second fix")
+
+	repo := NewRepo(repoDir, ExecRunner{})
+	subjects := func(filter HistoryFilter, skip, limit int) string {
+		t.Helper()
+		commits, err := repo.CommitHistory(filter, skip, limit)
+		if err != nil {
+			t.Fatalf("CommitHistory(%+v) error = %v", filter, err)
+		}
+		var out []string
+		for _, commit := range commits {
+			out = append(out, commit.Subject)
 		}
-	}()
+		return strings.Join(out, ", ")
+	}
 
-	fn()
+	if got := subjects(HistoryFilter{}, 1, 2); got != "fix: first fix, feat: add log parser" {
+		t.Fatalf("unexpected second page %q", got)
+	}
+	if got := subjects(HistoryFilter{Revs: []string{"main..feature"}}, 0, 10); got != "fix: second fix, fix: first fix" {
+		t.Fatalf("unexpected range %q", got)
+	}
+	if got := subjects(HistoryFilter{Author: "alice"}, 0, 10); got != "fix: first fix" {
+		t.Fatalf("unexpected author filter %q", got)
+	}
+	if got := subjects(HistoryFilter{Grep: "^feat"}, 0, 10); got != "feat: add log parser" {
+		t.Fatalf("unexpected grep filter %q", got)
+	}
+	if got := subjects(HistoryFilter{Pickaxe: "parseLog"}, 0, 10); got != "feat: add log parser" {
+		t.Fatalf("unexpected pickaxe filter %q", got)
+	}
+	if got := subjects(HistoryFilter{Paths: []string{"parse.go"}}, 0, 10); got != "feat: add log parser" {
+		t.Fatalf("unexpected path filter %q", got)
+	}
+	if got := subjects(HistoryFilter{Since: "2000-01-01", Until: "2000-12-31"}, 0, 10); got != "" {
+		t.Fatalf("expected no commits in 2000, got %q", got)
+	}
+	if _, err := repo.CommitHistory(HistoryFilter{Revs: []string{"missing..HEAD"}}, 0, 10); err == nil {
+		t.Fatal("expected an unknown revision to fail")
+	}
 }
 
-func runGit(t *testing.T, dir string, args ...string) string {
-	t.Helper()
+func TestCommitHistoryReadsParentsAndRefs(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
 
-	cmd := exec.Command("git", args...)
-	cmd.Dir = dir
-	out, err := cmd.CombinedOutput()
+	repoDir := t.TempDir()
+	runGit(t, repoDir, "init", "-b", "main")
+	runGit(t, repoDir, "config", "user.email", "test@example.com")
+	runGit(t, repoDir, "config", "user.name", "Test User")
+	runGit(t, repoDir, "commit", "--allow-empty", "-m", "chore: init")
+	runGit(t, repoDir, "tag", "v1")
+	runGit(t, repoDir, "checkout", "-b", "feature")
+	runGit(t, repoDir, "commit", "--allow-empty", "-m", "feat: on feature")
+	runGit(t, repoDir, "checkout", "main")
+
+	repo := NewRepo(repoDir, ExecRunner{})
+	commits, err := repo.CommitHistory(HistoryFilter{}, 0, 10)
 	if err != nil {
-		t.Fatalf("git %s failed: %v\n%s", strings.Join(args, " "), err, string(out))
+		t.Fatalf("CommitHistory() error = %v", err)
+	}
+	if len(commits) != 1 || len(commits[0].Parents) != 0 || strings.Join(commits[0].Refs, ", ") != "HEAD -> main, tag: v1" {
+		t.Fatalf("expected only main's root commit with its refs, got %+v", commits)
 	}
 
-	return string(out)
+	commits, err = repo.CommitHistory(HistoryFilter{All: true, Graph: true}, 0, 10)
+	if err != nil {
+		t.Fatalf("CommitHistory(all) error = %v", err)
+	}
+	if len(commits) != 2 || commits[0].Subject != "feat: on feature" {
+		t.Fatalf("expected the feature commit first with --all, got %+v", commits)
+	}
+	if len(commits[0].Parents) != 1 || commits[0].Parents[0] != commits[1].Hash || strings.Join(commits[0].Refs, ", ") != "feature" {
+		t.Fatalf("unexpected parents or refs %+v", commits[0])
+	}
+}
+
+func TestHistoryFilterString(t *testing.T) {
+	filter := HistoryFilter{Author: "alice", Grep: "^fix", Revs: []string{"main..feature"}, Paths: []string{"internal/git"}}
+	if got := filter.String(); got != "main..feature • author: alice • message: ^fix • path: internal/git" {
+		t.Fatalf("unexpected description %q", got)
+	}
+	if got := (HistoryFilter{}).String(); got != "" {
+		t.Fatalf("expected no description without filters, got %q", got)
+	}
+}
+
+func TestLatestTag(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repoDir := t.TempDir()
+	runGit(t, repoDir, "init", "-b", "main")
+	runGit(t, repoDir, "config", "user.email", "test@example.com")
+	runGit(t, repoDir, "config", "user.name", "Test User")
+	runGit(t, repoDir, "commit", "--allow-empty", "-m", "chore: init")
+
+	repo := NewRepo(repoDir, ExecRunner{})
+	if tag, err := repo.LatestTag("HEAD"); err != nil || tag != "" {
+		t.Fatalf("LatestTag() without tags = %q, %v", tag, err)
+	}
+
+	runGit(t, repoDir, "tag", "v1.0.0")
+	runGit(t, repoDir, "commit", "--allow-empty", "-m", "feat: more")
+	if tag, err := repo.LatestTag("HEAD"); err != nil || tag != "v1.0.0" {
+		t.Fatalf("LatestTag() = %q, %v; want v1.0.0", tag, err)
+	}
+	if _, err := repo.LatestTag("missing"); err == nil {
+		t.Fatal("expected an error for an unknown revision")
+	}
+
+	// A tag on the revision itself is skipped by PreviousTag.
+	runGit(t, repoDir, "tag", "v1.1.0")
+	if tag, err := repo.LatestTag("HEAD"); err != nil || tag != "v1.1.0" {
+		t.Fatalf("LatestTag() = %q, %v; want v1.1.0", tag, err)
+	}
+	if tag, err := repo.PreviousTag("HEAD"); err != nil || tag != "v1.0.0" {
+		t.Fatalf("PreviousTag() = %q, %v; want v1.0.0", tag, err)
+	}
+	if tag, err := repo.PreviousTag("v1.0.0"); err != nil || tag != "" {
+		t.Fatalf("PreviousTag() of the first tag = %q, %v", tag, err)
+	}
 }
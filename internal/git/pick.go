@@ -0,0 +1,87 @@
+package git
+
+import (
+	"fmt"
+)
+
+// Pick is a revert or cherry-pick applied to the index and working tree but
+// not yet committed, so its message can be written before the commit is made.
+type Pick struct {
+	// Kind is OperationRevert or OperationCherryPick.
+	Kind OperationKind
+	// Hash is the commit being reverted or picked.
+	Hash string
+	// Message is the message git prepared, without comment lines.
+	Message string
+	// Conflicts lists the paths left unmerged; it is empty when the changes
+	// applied cleanly.
+	Conflicts []string
+}
+
+// StartPick reverts or cherry-picks hash with --no-commit. Conflicts are not
+// an error: they are returned in the Pick to be resolved before committing,
+// or undone with AbortPick. The working tree must have no tracked changes, so
+// that aborting cannot lose any work.
+func (r *Repo) StartPick(kind OperationKind, hash string) (Pick, error) {
+	if kind != OperationRevert && kind != OperationCherryPick {
+		return Pick{}, fmt.Errorf("unsupported operation %q", kind)
+	}
+	changes, err := r.ListChanges()
+	if err != nil {
+		return Pick{}, err
+	}
+	for _, change := range changes {
+		if !change.Untracked {
+			return Pick{}, fmt.Errorf("cannot %s with uncommitted changes; commit or stash them first", kind)
+		}
+	}
+	operation, err := r.InProgress()
+	if err != nil {
+		return Pick{}, err
+	}
+	if operation.InProgress() {
+		return Pick{}, fmt.Errorf("%s: finish it first", operation.Title())
+	}
+
+	pick := Pick{Kind: kind, Hash: hash}
+	_, runErr := r.run(string(kind), "--no-commit", hash)
+	if pick.Conflicts, err = r.PickConflicts(); err != nil {
+		return Pick{}, err
+	}
+	if runErr != nil && len(pick.Conflicts) == 0 {
+		// Nothing to resolve, so undo whatever was half applied.
+		_ = r.AbortPick()
+		return Pick{}, fmt.Errorf("failed to %s %s: %w", kind, shortHash(hash), runErr)
+	}
+
+	gitDir, err := r.GitDir()
+	if err != nil {
+		return Pick{}, err
+	}
+	pick.Message = readMessageFile(gitDir, "MERGE_MSG")
+	return pick, nil
+}
+
+// PickConflicts returns the paths that are still unmerged.
+func (r *Repo) PickConflicts() ([]string, error) {
+	changes, err := r.ListChanges()
+	if err != nil {
+		return nil, err
+	}
+	var conflicts []string
+	for _, change := range changes {
+		if change.Conflict {
+			conflicts = append(conflicts, change.Path)
+		}
+	}
+	return conflicts, nil
+}
+
+// AbortPick undoes a pick that has not been committed, restoring the index
+// and working tree to HEAD and clearing git's revert or cherry-pick state.
+func (r *Repo) AbortPick() error {
+	if _, err := r.run("reset", "--merge"); err != nil {
+		return fmt.Errorf("failed to abort: %w", err)
+	}
+	return nil
+}
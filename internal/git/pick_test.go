@@ -0,0 +1,76 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStartPickRevertAndCommit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repoDir := conflictRepo(t)
+	repo := NewRepo(repoDir, ExecRunner{})
+	head := strings.TrimSpace(runGit(t, repoDir, "rev-parse", "HEAD"))
+
+	pick, err := repo.StartPick(OperationRevert, head)
+	if err != nil {
+		t.Fatalf("StartPick() error = %v", err)
+	}
+	if len(pick.Conflicts) != 0 || !strings.HasPrefix(pick.Message, `Revert "feat: main change"`) {
+		t.Fatalf("unexpected pick %+v", pick)
+	}
+	if staged := runGit(t, repoDir, "diff", "--cached"); !strings.Contains(staged, "-main") || !strings.Contains(staged, "+base") {
+		t.Fatalf("expected the revert to be staged, got:\n%s", staged)
+	}
+
+	if err := repo.Commit("revert: undo main change", CommitOptions{}); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	if operation, err := repo.InProgress(); err != nil || operation.InProgress() {
+		t.Fatalf("expected the commit to conclude the revert, got %+v, %v", operation, err)
+	}
+}
+
+func TestStartPickConflictAndAbort(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repoDir := conflictRepo(t)
+	repo := NewRepo(repoDir, ExecRunner{})
+	head := strings.TrimSpace(runGit(t, repoDir, "rev-parse", "HEAD"))
+
+	if err := os.WriteFile(filepath.Join(repoDir, "f.txt"), []byte("dirty\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if _, err := repo.StartPick(OperationCherryPick, "side"); err == nil || !strings.Contains(err.Error(), "uncommitted changes") {
+		t.Fatalf("expected uncommitted changes to be refused, got %v", err)
+	}
+	runGit(t, repoDir, "checkout", "--", "f.txt")
+
+	pick, err := repo.StartPick(OperationCherryPick, "side")
+	if err != nil {
+		t.Fatalf("StartPick() error = %v", err)
+	}
+	if len(pick.Conflicts) != 1 || pick.Conflicts[0] != "f.txt" || pick.Message != "feat: side change" {
+		t.Fatalf("expected a conflict in f.txt, got %+v", pick)
+	}
+
+	if err := repo.AbortPick(); err != nil {
+		t.Fatalf("AbortPick() error = %v", err)
+	}
+	if status := runGit(t, repoDir, "status", "--porcelain"); status != "" {
+		t.Fatalf("expected a clean tree after abort, got %q", status)
+	}
+	if now := strings.TrimSpace(runGit(t, repoDir, "rev-parse", "HEAD")); now != head {
+		t.Fatalf("expected HEAD to stay at %s, got %s", head, now)
+	}
+	if operation, err := repo.InProgress(); err != nil || operation.InProgress() {
+		t.Fatalf("expected no operation after abort, got %+v, %v", operation, err)
+	}
+}
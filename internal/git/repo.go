@@ -0,0 +1,158 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Command describes a single git invocation.
+type Command struct {
+	Dir   string
+	Args  []string
+	Stdin io.Reader
+	// Env entries are appended to the current process environment.
+	Env []string
+}
+
+// Runner executes git commands and returns their standard output.
+type Runner interface {
+	Run(ctx context.Context, cmd Command) ([]byte, error)
+}
+
+// CommandError describes a git command that exited unsuccessfully.
+type CommandError struct {
+	Args   []string
+	Stdout string
+	Stderr string
+	Err    error
+}
+
+func (e *CommandError) Error() string {
+	if output := strings.TrimSpace(e.Stderr); output != "" {
+		return output
+	}
+	if output := strings.TrimSpace(e.Stdout); output != "" {
+		return output
+	}
+	return fmt.Sprintf("git %s: %v", strings.Join(e.Args, " "), e.Err)
+}
+
+// Unwrap returns the underlying execution error.
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the git exit code, or -1 if git did not run.
+func (e *CommandError) ExitCode() int {
+	if exitErr, ok := errors.AsType[*exec.ExitError](e.Err); ok {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+// ExecRunner runs the git binary from PATH.
+type ExecRunner struct{}
+
+// Run implements Runner.
+func (ExecRunner) Run(ctx context.Context, cmd Command) ([]byte, error) {
+	c := exec.CommandContext(ctx, "git", cmd.Args...)
+	c.Dir = cmd.Dir
+	c.Stdin = cmd.Stdin
+	if len(cmd.Env) > 0 {
+		c.Env = append(os.Environ(), cmd.Env...)
+	}
+
+	var stdout, stderr bytes.Buffer
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+
+	if err := c.Run(); err != nil {
+		return stdout.Bytes(), &CommandError{
+			Args:   cmd.Args,
+			Stdout: stdout.String(),
+			Stderr: stderr.String(),
+			Err:    err,
+		}
+	}
+	return stdout.Bytes(), nil
+}
+
+// TimingRunner wraps a Runner and reports how long every git call took.
+type TimingRunner struct {
+	Runner  Runner
+	Observe func(cmd Command, elapsed time.Duration, err error)
+}
+
+// Run implements Runner.
+func (t TimingRunner) Run(ctx context.Context, cmd Command) ([]byte, error) {
+	start := time.Now()
+	out, err := t.Runner.Run(ctx, cmd)
+	if t.Observe != nil {
+		t.Observe(cmd, time.Since(start), err)
+	}
+	return out, err
+}
+
+// Repo is a git repository bound to a working directory.
+type Repo struct {
+	dir    string
+	runner Runner
+
+	rootOnce sync.Once
+	root     string
+	rootErr  error
+}
+
+// NewRepo creates a Repo for dir (empty means the process working directory).
+// A nil runner uses ExecRunner.
+func NewRepo(dir string, runner Runner) *Repo {
+	if runner == nil {
+		runner = ExecRunner{}
+	}
+	return &Repo{dir: dir, runner: runner}
+}
+
+// Open creates a Repo and verifies that dir is inside a git work tree.
+func Open(dir string, runner Runner) (*Repo, error) {
+	repo := NewRepo(dir, runner)
+	if err := repo.EnsureRepository(); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// Dir returns the directory git commands run in.
+func (r *Repo) Dir() string {
+	return r.dir
+}
+
+// Root returns the absolute path of the working tree's top-level directory,
+// where the project's commiter files live. It is looked up once and cached.
+func (r *Repo) Root() (string, error) {
+	r.rootOnce.Do(func() {
+		out, err := r.run("rev-parse", "--show-toplevel")
+		if err != nil {
+			r.rootErr = fmt.Errorf("failed to locate repository root: %w", err)
+			return
+		}
+		r.root = strings.TrimSpace(string(out))
+	})
+	return r.root, r.rootErr
+}
+
+func (r *Repo) run(args ...string) ([]byte, error) {
+	return r.runner.Run(context.Background(), Command{Dir: r.dir, Args: args})
+}
+
+func (r *Repo) runWith(cmd Command) ([]byte, error) {
+	cmd.Dir = r.dir
+	return r.runner.Run(context.Background(), cmd)
+}
@@ -0,0 +1,94 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeRunner struct {
+	calls   []Command
+	outputs map[string]string
+	errs    map[string]error
+}
+
+func (f *fakeRunner) Run(_ context.Context, cmd Command) ([]byte, error) {
+	f.calls = append(f.calls, cmd)
+	key := strings.Join(cmd.Args, " ")
+	return []byte(f.outputs[key]), f.errs[key]
+}
+
+func TestRepoRunsCommandsInBoundDirectory(t *testing.T) {
+	runner := &fakeRunner{outputs: map[string]string{
+		"diff --cached": "diff --git a/x b/x\n",
+	}}
+	repo := NewRepo("/work/project", runner)
+
+	diff, err := repo.GetStagedDiff()
+	if err != nil {
+		t.Fatalf("GetStagedDiff returned error: %v", err)
+	}
+	if string(diff) != "diff --git a/x b/x\n" {
+		t.Fatalf("unexpected diff %q", diff)
+	}
+	if len(runner.calls) != 1 || runner.calls[0].Dir != "/work/project" {
+		t.Fatalf("expected one call in bound directory, got %+v", runner.calls)
+	}
+}
+
+func TestRepoWrapsCommandErrors(t *testing.T) {
+	runner := &fakeRunner{errs: map[string]error{
+		"add -- missing.txt": &CommandError{Stderr: "fatal: pathspec 'missing.txt' did not match any files\n", Err: errors.New("exit status 128")},
+	}}
+
+	err := NewRepo("", runner).StageFiles([]string{"missing.txt"})
+	if err == nil {
+		t.Fatal("expected staging error")
+	}
+	if err.Error() != "failed to stage selected files: fatal: pathspec 'missing.txt' did not match any files" {
+		t.Fatalf("unexpected error message %q", err)
+	}
+}
+
+func TestTimingRunnerObservesEveryCall(t *testing.T) {
+	var observed []string
+	runner := TimingRunner{
+		Runner: &fakeRunner{},
+		Observe: func(cmd Command, elapsed time.Duration, err error) {
+			if elapsed < 0 {
+				t.Errorf("negative elapsed time %s", elapsed)
+			}
+			observed = append(observed, strings.Join(cmd.Args, " "))
+		},
+	}
+
+	repo := NewRepo("", runner)
+	repo.CanAmend()
+	repo.GetStagedDiff()
+
+	if strings.Join(observed, "|") != "rev-parse --verify --quiet HEAD|diff --cached" {
+		t.Fatalf("unexpected observed calls %v", observed)
+	}
+}
+
+func TestRootIsLookedUpOnce(t *testing.T) {
+	runner := &fakeRunner{outputs: map[string]string{
+		"rev-parse --show-toplevel": "/work/project\n",
+	}}
+	repo := NewRepo("/work/project/sub", runner)
+
+	for range 3 {
+		root, err := repo.Root()
+		if err != nil {
+			t.Fatalf("Root returned error: %v", err)
+		}
+		if root != "/work/project" {
+			t.Fatalf("unexpected root %q", root)
+		}
+	}
+	if len(runner.calls) != 1 {
+		t.Fatalf("expected one git call, got %+v", runner.calls)
+	}
+}
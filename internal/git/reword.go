@@ -0,0 +1,115 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IsPushed reports whether the commit is reachable from any remote-tracking
+// branch, in which case rewriting it would rewrite published history.
+func (r *Repo) IsPushed(hash string) (bool, error) {
+	out, err := r.run("for-each-ref", "--contains", hash, "--format=%(refname)", "refs/remotes")
+	if err != nil {
+		return false, fmt.Errorf("failed to check whether %s is pushed: %w", shortHash(hash), err)
+	}
+	return strings.TrimSpace(string(out)) != "", nil
+}
+
+// Reword replaces the message of a commit on the current branch. The commit
+// is recreated with the same tree, parents, author and date, and the commits
+// after it are replayed on top with a non-interactive
+// "git rebase --rebase-merges --onto". Signing, signoff and trailers from opts
+// apply; the author and date overrides do not, since a reword keeps both.
+func (r *Repo) Reword(hash, message string, opts CommitOptions) error {
+	head, err := r.run("rev-parse", "HEAD")
+	if err != nil {
+		return fmt.Errorf("failed to resolve HEAD: %w", err)
+	}
+	if _, err := r.run("merge-base", "--is-ancestor", hash, "HEAD"); err != nil {
+		return fmt.Errorf("%s is not on the current branch", shortHash(hash))
+	}
+
+	message, err = r.withTrailers(message, opts)
+	if err != nil {
+		return err
+	}
+
+	meta, err := r.run("log", "-1", "--format=%an%x00%ae%x00%aD%x00%P", hash)
+	if err != nil {
+		return fmt.Errorf("failed to read commit %s: %w", shortHash(hash), err)
+	}
+	fields := strings.Split(strings.TrimRight(string(meta), "\n"), "\x00")
+	if len(fields) != 4 {
+		return fmt.Errorf("failed to read commit %s", shortHash(hash))
+	}
+
+	args := []string{"commit-tree", hash + "^{tree}"}
+	for _, parent := range strings.Fields(fields[3]) {
+		args = append(args, "-p", parent)
+	}
+	if opts.Sign {
+		if opts.SigningKey != "" {
+			args = append(args, "-S"+opts.SigningKey)
+		} else {
+			args = append(args, "-S")
+		}
+	}
+	out, err := r.runWith(Command{
+		Args:  args,
+		Stdin: strings.NewReader(message),
+		Env: []string{
+			"GIT_AUTHOR_NAME=" + fields[0],
+			"GIT_AUTHOR_EMAIL=" + fields[1],
+			"GIT_AUTHOR_DATE=" + fields[2],
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to rewrite commit %s: %w", shortHash(hash), err)
+	}
+	rewritten := strings.TrimSpace(string(out))
+
+	if strings.TrimSpace(string(head)) == hash {
+		if _, err := r.run("update-ref", "-m", "commiter: reword", "HEAD", rewritten, hash); err != nil {
+			return fmt.Errorf("failed to update HEAD: %w", err)
+		}
+		return nil
+	}
+
+	if _, err := r.run("rebase", "--rebase-merges", "--autostash", "--onto", rewritten, hash); err != nil {
+		return fmt.Errorf("failed to replay the commits after %s: %w", shortHash(hash), err)
+	}
+	return nil
+}
+
+// withTrailers appends the signoff and extra trailers from opts to message.
+func (r *Repo) withTrailers(message string, opts CommitOptions) (string, error) {
+	var args []string
+	if opts.Signoff {
+		ident, err := r.run("var", "GIT_COMMITTER_IDENT")
+		if err != nil {
+			return "", fmt.Errorf("failed to read committer identity: %w", err)
+		}
+		// The identity ends with a timestamp and time zone.
+		name, _, _ := strings.Cut(strings.TrimSpace(string(ident)), "> ")
+		args = append(args, "--trailer=Signed-off-by: "+name+">")
+	}
+	for _, trailer := range opts.Trailers {
+		if trailer = strings.TrimSpace(trailer); trailer != "" {
+			args = append(args, "--trailer="+trailer)
+		}
+	}
+
+	message = strings.TrimSpace(message) + "\n"
+	if len(args) == 0 {
+		return message, nil
+	}
+
+	out, err := r.runWith(Command{
+		Args:  append([]string{"interpret-trailers"}, args...),
+		Stdin: strings.NewReader(message),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to add trailers: %w", err)
+	}
+	return string(out), nil
+}
@@ -0,0 +1,73 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRewordRewritesOlderCommit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repoDir := t.TempDir()
+	runGit(t, repoDir, "init", "-b", "main")
+	runGit(t, repoDir, "config", "user.email", "test@example.com")
+	runGit(t, repoDir, "config", "user.name", "Test User")
+
+	for i, name := range []string{"a.txt", "b.txt", "c.txt"} {
+		if err := os.WriteFile(filepath.Join(repoDir, name), []byte(name+"\n"), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		runGit(t, repoDir, "add", name)
+		runGit(t, repoDir, "commit", "-m", "commit "+string(rune('1'+i)), "--date=2020-01-02T03:04:05Z")
+	}
+	runGit(t, repoDir, "update-ref", "refs/remotes/origin/main", "HEAD~2")
+
+	repo := NewRepo(repoDir, ExecRunner{})
+	root := strings.TrimSpace(runGit(t, repoDir, "rev-parse", "HEAD~2"))
+	if pushed, err := repo.IsPushed(root); err != nil || !pushed {
+		t.Fatalf("IsPushed(root) = %v, %v; want true", pushed, err)
+	}
+
+	target := strings.TrimSpace(runGit(t, repoDir, "rev-parse", "HEAD~1"))
+	if pushed, err := repo.IsPushed(target); err != nil || pushed {
+		t.Fatalf("IsPushed(target) = %v, %v; want false", pushed, err)
+	}
+
+	// Uncommitted changes survive the rewrite.
+	if err := os.WriteFile(filepath.Join(repoDir, "c.txt"), []byte("dirty\n"), 0o644); err != nil {
+		t.Fatalf("write c.txt: %v", err)
+	}
+
+	if err := repo.Reword(target, "feat: add b\n\nWith a body.", CommitOptions{Signoff: true}); err != nil {
+		t.Fatalf("Reword() error = %v", err)
+	}
+
+	log := runGit(t, repoDir, "log", "--format=%s")
+	if log != "commit 3\nfeat: add b\ncommit 1\n" {
+		t.Fatalf("unexpected history:\n%s", log)
+	}
+	body := runGit(t, repoDir, "log", "-1", "--format=%B", "HEAD~1")
+	if !strings.Contains(body, "With a body.") || !strings.Contains(body, "Signed-off-by: Test User <test@example.com>") {
+		t.Fatalf("unexpected message:\n%s", body)
+	}
+	if date := runGit(t, repoDir, "log", "-1", "--format=%aI", "HEAD~1"); !strings.HasPrefix(date, "2020-01-02T03:04:05") {
+		t.Fatalf("expected the author date to be kept, got %s", date)
+	}
+	if status := runGit(t, repoDir, "status", "--porcelain"); !strings.Contains(status, "c.txt") {
+		t.Fatalf("expected the uncommitted change to survive, got %q", status)
+	}
+
+	// Rewording HEAD moves the branch without a rebase.
+	head := strings.TrimSpace(runGit(t, repoDir, "rev-parse", "HEAD"))
+	if err := repo.Reword(head, "feat: add c", CommitOptions{}); err != nil {
+		t.Fatalf("Reword(HEAD) error = %v", err)
+	}
+	if subject := strings.TrimSpace(runGit(t, repoDir, "log", "-1", "--format=%s")); subject != "feat: add c" {
+		t.Fatalf("unexpected HEAD subject %q", subject)
+	}
+}
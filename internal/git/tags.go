@@ -0,0 +1,80 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LatestTag returns the most recent tag reachable from rev, or "" when there
+// is none.
+func (r *Repo) LatestTag(rev string) (string, error) {
+	if _, err := r.run("rev-parse", "--verify", "--quiet", rev+"^{commit}"); err != nil {
+		return "", fmt.Errorf("unknown revision %q", rev)
+	}
+
+	out, err := r.run("describe", "--tags", "--abbrev=0", rev)
+	if err != nil {
+		// describe fails when no tag is reachable.
+		return "", nil
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// PreviousTag returns the most recent tag reachable from rev other than the
+// tags on rev itself, or "" when there is none. It is where the release
+// tagged at rev starts.
+func (r *Repo) PreviousTag(rev string) (string, error) {
+	if _, err := r.run("rev-parse", "--verify", "--quiet", rev+"^{commit}"); err != nil {
+		return "", fmt.Errorf("unknown revision %q", rev)
+	}
+	out, err := r.run("tag", "--points-at", rev)
+	if err != nil {
+		return "", fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	args := []string{"describe", "--tags", "--abbrev=0"}
+	for _, tag := range strings.Fields(string(out)) {
+		args = append(args, "--exclude="+tag)
+	}
+	out, err = r.run(append(args, rev)...)
+	if err != nil {
+		// describe fails when no other tag is reachable.
+		return "", nil
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// Tags returns the names of the tags reachable from rev.
+func (r *Repo) Tags(rev string) ([]string, error) {
+	out, err := r.run("tag", "--list", "--merged", rev)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	return strings.Fields(string(out)), nil
+}
+
+// TagOptions controls how an annotated tag is created.
+type TagOptions struct {
+	Sign       bool
+	SigningKey string
+}
+
+// CreateTag creates an annotated tag on HEAD. The message is kept as written,
+// so markdown headings are not mistaken for comments.
+func (r *Repo) CreateTag(name, message string, opts TagOptions) error {
+	args := []string{"tag", "--cleanup=whitespace", "-F", "-"}
+	switch {
+	case opts.Sign && opts.SigningKey != "":
+		args = append(args, "-u", opts.SigningKey)
+	case opts.Sign:
+		args = append(args, "-s")
+	default:
+		args = append(args, "-a")
+	}
+	args = append(args, name)
+
+	if _, err := r.runWith(Command{Args: args, Stdin: strings.NewReader(strings.TrimSpace(message) + "\n")}); err != nil {
+		return fmt.Errorf("failed to create tag %s: %w", name, err)
+	}
+	return nil
+}
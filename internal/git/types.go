@@ -1,21 +1,114 @@
 package git
 
+import "strings"
+
 // ChangedFile represents a file with changes in the git repository
 type ChangedFile struct {
-	Path     string
-	Status   string
-	Selected bool
+	Path   string
+	Status string
+	// IndexStatus and WorktreeStatus are the porcelain XY codes ('.' means unchanged).
+	IndexStatus    byte
+	WorktreeStatus byte
+	// OrigPath is the source path of a rename or copy.
+	OrigPath string
+	// Submodule holds the porcelain v2 submodule state (e.g. "SC.."), empty for regular files.
+	Submodule string
+	Untracked bool
+	Conflict  bool
+	Selected  bool
+}
+
+// HasStagedChanges reports whether the index differs from HEAD for this file.
+func (f ChangedFile) HasStagedChanges() bool {
+	return !f.Untracked && !f.Conflict && f.IndexStatus != 0 && f.IndexStatus != '.'
+}
+
+// HasUnstagedChanges reports whether the work tree differs from the index.
+func (f ChangedFile) HasUnstagedChanges() bool {
+	return f.Untracked || f.Conflict || (f.WorktreeStatus != 0 && f.WorktreeStatus != '.')
+}
+
+// IndexLabel describes the staged change, e.g. "modified".
+func (f ChangedFile) IndexLabel() string {
+	return statusLabel(f.IndexStatus)
+}
+
+// WorktreeLabel describes the unstaged change, e.g. "modified" or "untracked".
+func (f ChangedFile) WorktreeLabel() string {
+	switch {
+	case f.Untracked:
+		return "untracked"
+	case f.Conflict:
+		return statusLabel('U')
+	default:
+		return statusLabel(f.WorktreeStatus)
+	}
+}
+
+// IsSubmodule reports whether the entry is a submodule.
+func (f ChangedFile) IsSubmodule() bool {
+	return strings.HasPrefix(f.Submodule, "S")
 }
 
 // Title implements list.Item interface
 func (f ChangedFile) Title() string { return f.Path }
 
 // Description implements list.Item interface
-func (f ChangedFile) Description() string { return f.Status }
+func (f ChangedFile) Description() string {
+	if f.OrigPath != "" {
+		return f.Status + " from " + f.OrigPath
+	}
+	return f.Status
+}
 
 // FilterValue implements list.Item interface
 func (f ChangedFile) FilterValue() string { return f.Path }
 
+// CommitOptions controls how a commit is created.
+type CommitOptions struct {
+	// Sign signs the commit with the configured GPG or SSH key; SigningKey selects a specific key.
+	Sign       bool
+	SigningKey string
+	Signoff    bool
+	// Author overrides the author, e.g. "Jane Doe <jane@example.com>".
+	Author string
+	// Date overrides the author date in any format git accepts.
+	Date string
+	// Trailers are appended as "Key: value" lines, e.g. "Reviewed-by: Jane Doe <jane@example.com>".
+	Trailers []string
+	// NoVerify skips the repository's pre-commit and commit-msg hooks.
+	NoVerify bool
+}
+
+func (o CommitOptions) args() []string {
+	var args []string
+	if o.Sign {
+		if o.SigningKey != "" {
+			args = append(args, "--gpg-sign="+o.SigningKey)
+		} else {
+			args = append(args, "--gpg-sign")
+		}
+	}
+	if o.Signoff {
+		args = append(args, "--signoff")
+	}
+	if o.Author != "" {
+		args = append(args, "--author="+o.Author)
+	}
+	if o.Date != "" {
+		args = append(args, "--date="+o.Date)
+	}
+	for _, trailer := range o.Trailers {
+		if trailer = strings.TrimSpace(trailer); trailer != "" {
+			args = append(args, "--trailer="+trailer)
+		}
+	}
+	if o.NoVerify {
+		args = append(args, "--no-verify")
+	}
+	return args
+}
+
 // CommitInfo represents information about a git commit
 type CommitInfo struct {
 	Hash    string
@@ -23,4 +116,86 @@ type CommitInfo struct {
 	Author  string
 	Subject string
 	Body    string
+	// Parents and Refs are only read by CommitHistory. Refs are the branch
+	// and tag names pointing at the commit, e.g. "HEAD -> main", "tag: v1.0".
+	Parents []string
+	Refs    []string
+}
+
+// HistoryFilter narrows the commits listed by CommitHistory. Every field maps
+// to a git log option, so filtering happens in git rather than on a loaded
+// page.
+type HistoryFilter struct {
+	Author  string   // --author regexp
+	Since   string   // --since date, e.g. "2 weeks ago"
+	Until   string   // --until date
+	Grep    string   // --grep regexp matched against the message
+	Pickaxe string   // -S string whose number of occurrences changed
+	Revs    []string // revisions or ranges, e.g. "main..feature"; HEAD when empty
+	Paths   []string // pathspecs after "--"
+	All     bool     // --all: every ref, not only HEAD or Revs
+	// Graph lists children before their parents and keeps branches together
+	// (--topo-order), and rewrites parents to the nearest listed commit when
+	// paths are given (--parents), as drawing a graph needs.
+	Graph bool
+}
+
+// Args returns the git log arguments for the filter.
+func (f HistoryFilter) Args() []string {
+	var args []string
+	if f.Author != "" {
+		args = append(args, "--author="+f.Author)
+	}
+	if f.Since != "" {
+		args = append(args, "--since="+f.Since)
+	}
+	if f.Until != "" {
+		args = append(args, "--until="+f.Until)
+	}
+	if f.Grep != "" {
+		args = append(args, "--grep="+f.Grep)
+	}
+	if f.Pickaxe != "" {
+		args = append(args, "-S"+f.Pickaxe)
+	}
+	if f.Graph {
+		args = append(args, "--topo-order", "--parents")
+	}
+	if f.All {
+		args = append(args, "--all")
+	}
+	args = append(args, "--end-of-options")
+	args = append(args, f.Revs...)
+	args = append(args, "--")
+	return append(args, f.Paths...)
+}
+
+// String describes the active filters, or returns "" when there are none.
+func (f HistoryFilter) String() string {
+	var parts []string
+	if f.All {
+		parts = append(parts, "all refs")
+	}
+	if len(f.Revs) > 0 {
+		parts = append(parts, strings.Join(f.Revs, " "))
+	}
+	if f.Author != "" {
+		parts = append(parts, "author: "+f.Author)
+	}
+	if f.Since != "" {
+		parts = append(parts, "since: "+f.Since)
+	}
+	if f.Until != "" {
+		parts = append(parts, "until: "+f.Until)
+	}
+	if f.Grep != "" {
+		parts = append(parts, "message: "+f.Grep)
+	}
+	if f.Pickaxe != "" {
+		parts = append(parts, "-S "+f.Pickaxe)
+	}
+	if len(f.Paths) > 0 {
+		parts = append(parts, "path: "+strings.Join(f.Paths, ", "))
+	}
+	return strings.Join(parts, " • ")
 }
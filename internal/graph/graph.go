@@ -0,0 +1,171 @@
+// Package graph lays out commits in lanes and draws the lines between them
+// with box-drawing characters, for showing history as a graph.
+package graph
+
+import "strings"
+
+// Row is one commit's part of the graph: Node is the line holding the
+// commit's node, and Edge the line below it leading on to the next commit.
+type Row struct {
+	Node string
+	Edge string
+}
+
+// Builder lays out commits one at a time, newest first, in the order git log
+// --topo-order lists them. Each lane holds the hash of the commit its line
+// leads to, so the graph can be extended as more history is loaded.
+type Builder struct {
+	lanes []string
+}
+
+// Add places the commit in the graph and returns its row. The commit takes
+// the lane leading to it, or a free lane when it is a branch tip. Its first
+// parent continues that lane, joining another lane that already leads to the
+// parent, in which case the leftmost of the two carries on; further parents
+// join their lane or start a new one.
+func (b *Builder) Add(hash string, parents []string) Row {
+	col := b.lane(hash)
+	if col < 0 {
+		col = b.free(0)
+	}
+	before := append([]string(nil), b.lanes...)
+
+	node := newCells(len(b.lanes))
+	for i, lane := range b.lanes {
+		if lane != "" {
+			node.lane(i).up, node.lane(i).down = true, true
+		}
+	}
+	*node.lane(col) = cell{node: true}
+	b.lanes[col] = ""
+
+	var targets []int
+	for i, parent := range parents {
+		if k := b.lane(parent); k >= 0 {
+			if i == 0 && k > col {
+				// Keep the line to the left so the graph stays narrow.
+				b.lanes[col], b.lanes[k] = parent, ""
+			}
+			targets = append(targets, k)
+			continue
+		}
+		if i == 0 {
+			b.lanes[col] = parent
+			continue
+		}
+		k := b.free(col + 1)
+		b.lanes[k] = parent
+		targets = append(targets, k)
+	}
+
+	edge := newCells(len(b.lanes))
+	for i, lane := range b.lanes {
+		c := edge.lane(i)
+		c.up = i < len(before) && before[i] != "" && i != col
+		c.down = lane != ""
+	}
+	edge.lane(col).up = len(parents) > 0
+	for _, k := range targets {
+		edge.connect(col, k)
+	}
+
+	b.trim()
+	return Row{Node: node.String(), Edge: edge.String()}
+}
+
+// lane returns the lane leading to hash, or -1.
+func (b *Builder) lane(hash string) int {
+	for i, lane := range b.lanes {
+		if lane == hash {
+			return i
+		}
+	}
+	return -1
+}
+
+// free returns the first unused lane at or after from, adding one if needed.
+func (b *Builder) free(from int) int {
+	for i := from; i < len(b.lanes); i++ {
+		if b.lanes[i] == "" {
+			return i
+		}
+	}
+	for len(b.lanes) < from {
+		b.lanes = append(b.lanes, "")
+	}
+	b.lanes = append(b.lanes, "")
+	return len(b.lanes) - 1
+}
+
+// trim drops unused lanes from the right so the graph narrows again.
+func (b *Builder) trim() {
+	for len(b.lanes) > 0 && b.lanes[len(b.lanes)-1] == "" {
+		b.lanes = b.lanes[:len(b.lanes)-1]
+	}
+}
+
+// cell is one character of a line, described by the directions its line
+// runs in. Lanes sit at even cells with a gap cell between each pair.
+type cell struct {
+	up, down, left, right bool
+	node                  bool
+}
+
+type cells []cell
+
+func newCells(lanes int) cells {
+	return make(cells, max(2*lanes-1, 0))
+}
+
+func (cs cells) lane(i int) *cell {
+	return &cs[2*i]
+}
+
+// connect draws a horizontal line between two lanes.
+func (cs cells) connect(from, to int) {
+	lo, hi := 2*min(from, to), 2*max(from, to)
+	cs[lo].right = true
+	cs[hi].left = true
+	for i := lo + 1; i < hi; i++ {
+		cs[i].left, cs[i].right = true, true
+	}
+}
+
+func (cs cells) String() string {
+	var b strings.Builder
+	for _, c := range cs {
+		b.WriteString(c.String())
+	}
+	return strings.TrimRight(b.String(), " ")
+}
+
+func (c cell) String() string {
+	if c.node {
+		return "●"
+	}
+	switch {
+	case c.up && c.down && c.left && c.right:
+		return "┼"
+	case c.up && c.down && c.right:
+		return "├"
+	case c.up && c.down && c.left:
+		return "┤"
+	case c.up && c.left && c.right:
+		return "┴"
+	case c.down && c.left && c.right:
+		return "┬"
+	case c.up && c.right:
+		return "╰"
+	case c.up && c.left:
+		return "╯"
+	case c.down && c.right:
+		return "╭"
+	case c.down && c.left:
+		return "╮"
+	case c.up || c.down:
+		return "│"
+	case c.left || c.right:
+		return "─"
+	}
+	return " "
+}
@@ -0,0 +1,101 @@
+package graph
+
+import (
+	"strings"
+	"testing"
+)
+
+type commit struct {
+	hash    string
+	parents []string
+}
+
+// draw lays out the commits and returns the graph, one line per row line.
+func draw(commits []commit) string {
+	var b Builder
+	var lines []string
+	for _, c := range commits {
+		row := b.Add(c.hash, c.parents)
+		lines = append(lines, row.Node+" "+c.hash)
+		if row.Edge != "" {
+			lines = append(lines, row.Edge)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestLinearHistory(t *testing.T) {
+	got := draw([]commit{
+		{"c", []string{"b"}},
+		{"b", []string{"a"}},
+		{"a", nil},
+	})
+	want := "● c\n│\n● b\n│\n● a"
+	if got != want {
+		t.Fatalf("graph =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestBranchAndMerge(t *testing.T) {
+	got := draw([]commit{
+		{"m", []string{"c", "b"}},
+		{"c", []string{"a"}},
+		{"b", []string{"a"}},
+		{"a", nil},
+	})
+	want := strings.Join([]string{
+		"● m",
+		"├─╮",
+		"● │ c",
+		"│ │",
+		"│ ● b",
+		"├─╯",
+		"● a",
+	}, "\n")
+	if got != want {
+		t.Fatalf("graph =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestSeparateTipsJoinTheirForkPoint(t *testing.T) {
+	// As git log --all lists a branch that forks from main.
+	got := draw([]commit{
+		{"feature", []string{"f1"}},
+		{"main", []string{"base"}},
+		{"f1", []string{"base"}},
+		{"base", nil},
+	})
+	want := strings.Join([]string{
+		"● feature",
+		"│",
+		"│ ● main",
+		"│ │",
+		"● │ f1",
+		"├─╯",
+		"● base",
+	}, "\n")
+	if got != want {
+		t.Fatalf("graph =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestLineCrossesLaneToReachItsParent(t *testing.T) {
+	got := draw([]commit{
+		{"m", []string{"x", "b"}},
+		{"t", []string{"x"}},
+		{"b", []string{"x"}},
+		{"x", nil},
+	})
+	want := strings.Join([]string{
+		"● m",
+		"├─╮",
+		"│ │ ● t",
+		"├─┼─╯",
+		"│ ● b",
+		"├─╯",
+		"● x",
+	}, "\n")
+	if got != want {
+		t.Fatalf("graph =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,106 @@
+package hooks
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PrepareCommitMsg is the git hook commiter installs.
+const PrepareCommitMsg = "prepare-commit-msg"
+
+// installedMarker identifies a hook script written by Install.
+const installedMarker = "# Installed by commiter."
+
+// previousSuffix is appended to the name of a hook that was already in place,
+// which the installed hook runs after its own work.
+const previousSuffix = ".commiter-previous"
+
+// Installed reports whether the hooks directory holds commiter's
+// prepare-commit-msg hook.
+func Installed(hooksDir string) (bool, error) {
+	data, err := os.ReadFile(filepath.Join(hooksDir, PrepareCommitMsg))
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return strings.Contains(string(data), installedMarker), nil
+}
+
+// Install writes the prepare-commit-msg hook that runs exe. A hook that is
+// already there is kept under a new name and chained, so it still runs and
+// sees the generated message. Installing again only refreshes the script.
+func Install(hooksDir, exe string) error {
+	if err := os.MkdirAll(hooksDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create hooks directory: %w", err)
+	}
+
+	path := filepath.Join(hooksDir, PrepareCommitMsg)
+	installed, err := Installed(hooksDir)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if !installed {
+		if _, err := os.Stat(path + previousSuffix); err == nil {
+			return fmt.Errorf("%s already exists; move it away before installing", path+previousSuffix)
+		}
+		if err := os.Rename(path, path+previousSuffix); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to keep the existing hook: %w", err)
+		}
+	}
+
+	if err := os.WriteFile(path, []byte(hookScript(exe)), 0o755); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// Uninstall removes commiter's hook and puts back the hook it replaced.
+func Uninstall(hooksDir string) error {
+	path := filepath.Join(hooksDir, PrepareCommitMsg)
+	installed, err := Installed(hooksDir)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if !installed {
+		return fmt.Errorf("no commiter hook installed in %s", hooksDir)
+	}
+
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", path, err)
+	}
+	if err := os.Rename(path+previousSuffix, path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to restore the previous hook: %w", err)
+	}
+	return nil
+}
+
+// hookScript runs commiter first, falling back to the one on PATH if exe has
+// moved, then hands over to the hook it replaced. A missing commiter never
+// blocks a commit.
+func hookScript(exe string) string {
+	return `#!/bin/sh
+` + installedMarker + ` Remove with: commiter hook uninstall
+commiter=` + shellQuote(exe) + `
+if [ ! -x "$commiter" ]; then
+	commiter=$(command -v commiter)
+fi
+if [ -n "$commiter" ]; then
+	"$commiter" hook ` + PrepareCommitMsg + ` "$@" || exit $?
+fi
+
+previous="$(dirname "$0")/` + PrepareCommitMsg + previousSuffix + `"
+if [ -x "$previous" ]; then
+	exec "$previous" "$@"
+fi
+`
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
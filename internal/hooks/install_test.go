@@ -0,0 +1,81 @@
+package hooks
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestInstallChainsExistingHookAndUninstallRestoresIt(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook scripts need a POSIX shell")
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repoDir := t.TempDir()
+	gitCmd := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoDir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s failed: %v\n%s", strings.Join(args, " "), err, out)
+		}
+		return string(out)
+	}
+	gitCmd("init")
+	gitCmd("config", "user.email", "test@example.com")
+	gitCmd("config", "user.name", "Test User")
+	gitCmd("config", "core.hooksPath", ".githooks")
+
+	hooksDir := filepath.Join(repoDir, ".githooks")
+	if err := os.MkdirAll(hooksDir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	previous := "#!/bin/sh\necho 'Chained: yes' >> \"$1\"\n"
+	if err := os.WriteFile(filepath.Join(hooksDir, PrepareCommitMsg), []byte(previous), 0o755); err != nil {
+		t.Fatalf("write previous hook: %v", err)
+	}
+
+	// A stand-in for the commiter binary: "hook prepare-commit-msg <file> ..."
+	exe := filepath.Join(t.TempDir(), "fake commiter")
+	script := "#!/bin/sh\n[ -z \"$4\" ] && printf 'feat: generated\\n\\n' > \"$3\"\nexit 0\n"
+	if err := os.WriteFile(exe, []byte(script), 0o755); err != nil {
+		t.Fatalf("write fake commiter: %v", err)
+	}
+
+	if err := Install(hooksDir, exe); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if err := Install(hooksDir, exe); err != nil {
+		t.Fatalf("second Install() error = %v", err)
+	}
+	if installed, err := Installed(hooksDir); err != nil || !installed {
+		t.Fatalf("Installed() = %v, %v", installed, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(repoDir, "a.txt"), []byte("a\n"), 0o644); err != nil {
+		t.Fatalf("write a.txt: %v", err)
+	}
+	gitCmd("add", "a.txt")
+	gitCmd("-c", "core.editor=true", "commit")
+	if message := gitCmd("log", "-1", "--format=%B"); message != "feat: generated\n\nChained: yes\n\n" {
+		t.Fatalf("unexpected message: %q", message)
+	}
+
+	if err := Uninstall(hooksDir); err != nil {
+		t.Fatalf("Uninstall() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(hooksDir, PrepareCommitMsg))
+	if err != nil || string(data) != previous {
+		t.Fatalf("expected the previous hook back, got %q, %v", data, err)
+	}
+	if err := Uninstall(hooksDir); err == nil {
+		t.Fatal("expected an error when commiter's hook is not installed")
+	}
+}
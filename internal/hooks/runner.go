@@ -27,6 +27,7 @@ const (
 // RunOptions configures hook execution.
 type RunOptions struct {
 	Phase         Phase
+	Dir           string
 	Commands      []string
 	Timeout       time.Duration
 	CommitMessage string
@@ -92,6 +93,7 @@ func runCommand(ctx context.Context, opts RunOptions, index int, command string,
 
 	name, args := shellCommand(command)
 	cmd := exec.CommandContext(runCtx, name, args...)
+	cmd.Dir = opts.Dir
 	cmd.Env = append(os.Environ(),
 		"COMMITER_HOOK_PHASE="+string(opts.Phase),
 		"COMMITER_COMMIT_MESSAGE="+opts.CommitMessage,
@@ -0,0 +1,286 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/samcharles93/commiter/internal/config"
+)
+
+// Severity describes how serious a rule violation is.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Rule identifiers reported on issues.
+const (
+	RuleType          = "type"
+	RuleFormat        = "format"
+	RuleSubjectLength = "subject-length"
+	RuleSubjectMood   = "subject-mood"
+	RuleBlankLine     = "blank-line"
+	RuleBodyWrap      = "body-wrap"
+	RuleForbiddenWord = "forbidden-word"
+	RuleEmpty         = "empty"
+)
+
+// Issue is a single rule violation found in a commit message.
+type Issue struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Line     int      `json:"line"`
+	Message  string   `json:"message"`
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("line %d: %s (%s)", i.Line, i.Message, i.Rule)
+}
+
+// Rules configures message validation.
+type Rules struct {
+	Types            []string
+	SubjectMaxLength int
+	BodyWrapWidth    int
+	ForbiddenWords   []string
+	Language         string
+}
+
+// RulesFor builds validation rules from a template and the user's memory notes.
+// The template's language decides which English-only checks apply.
+func RulesFor(tmpl *config.CommitTemplate, memory string) Rules {
+	rules := Rules{
+		SubjectMaxLength: config.DefaultSubjectMaxLength,
+		BodyWrapWidth:    config.DefaultBodyWrapWidth,
+		ForbiddenWords:   ForbiddenWords(memory),
+	}
+	if tmpl != nil {
+		rules.Types = append([]string(nil), tmpl.Types...)
+		rules.SubjectMaxLength = tmpl.GetSubjectMaxLength()
+		rules.BodyWrapWidth = tmpl.GetBodyWrapWidth()
+		rules.Language = tmpl.Language
+	}
+	return rules
+}
+
+// Header is the parsed form of a Conventional Commits subject line.
+type Header struct {
+	Type        string
+	Scope       string
+	Breaking    bool
+	Description string
+}
+
+var headerPattern = regexp.MustCompile(`^([A-Za-z]+)(?:\(([^()]*)\))?(!)?: (.*)$`)
+
+// ParseHeader parses a "type(scope)!: description" subject line.
+func ParseHeader(subject string) (Header, bool) {
+	match := headerPattern.FindStringSubmatch(strings.TrimSpace(subject))
+	if match == nil {
+		return Header{}, false
+	}
+	return Header{
+		Type:        strings.ToLower(match[1]),
+		Scope:       match[2],
+		Breaking:    match[3] == "!",
+		Description: strings.TrimSpace(match[4]),
+	}, true
+}
+
+// HasErrors reports whether any issue has error severity.
+func HasErrors(issues []Issue) bool {
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks a commit message against the rules.
+func Validate(message string, rules Rules) []Issue {
+	message = strings.TrimSpace(message)
+	if message == "" {
+		return []Issue{{Rule: RuleEmpty, Severity: SeverityError, Line: 1, Message: "commit message is empty"}}
+	}
+
+	lines := strings.Split(message, "\n")
+	subject := strings.TrimRight(lines[0], " \t\r")
+	if gitGenerated(subject) {
+		return nil
+	}
+
+	var issues []Issue
+	description := subject
+	if len(rules.Types) > 0 {
+		header, ok := ParseHeader(subject)
+		switch {
+		case !ok:
+			issues = append(issues, Issue{
+				Rule:     RuleFormat,
+				Severity: SeverityError,
+				Line:     1,
+				Message:  fmt.Sprintf("subject must start with a type prefix such as %q", rules.Types[0]+": "),
+			})
+		case !slices.Contains(rules.Types, header.Type):
+			issues = append(issues, Issue{
+				Rule:     RuleType,
+				Severity: SeverityError,
+				Line:     1,
+				Message:  fmt.Sprintf("type %q is not allowed (use one of: %s)", header.Type, strings.Join(rules.Types, ", ")),
+			})
+		default:
+			description = header.Description
+		}
+	}
+
+	if limit := rules.SubjectMaxLength; limit > 0 {
+		if n := utf8.RuneCountInString(subject); n > limit {
+			issues = append(issues, Issue{
+				Rule:     RuleSubjectLength,
+				Severity: SeverityError,
+				Line:     1,
+				Message:  fmt.Sprintf("subject is %d characters, limit is %d", n, limit),
+			})
+		}
+	}
+
+	// Mood and word rules are written for English messages only.
+	english := config.IsEnglish(rules.Language)
+
+	if word, ok := nonImperative(description); english && ok {
+		issues = append(issues, Issue{
+			Rule:     RuleSubjectMood,
+			Severity: SeverityWarning,
+			Line:     1,
+			Message:  fmt.Sprintf("subject should use the imperative mood (%q)", word),
+		})
+	}
+
+	if len(lines) > 1 && strings.TrimSpace(lines[1]) != "" {
+		issues = append(issues, Issue{
+			Rule:     RuleBlankLine,
+			Severity: SeverityError,
+			Line:     2,
+			Message:  "separate the subject from the body with a blank line",
+		})
+	}
+
+	if width := rules.BodyWrapWidth; width > 0 {
+		for i := 1; i < len(lines); i++ {
+			line := strings.TrimRight(lines[i], " \t\r")
+			if utf8.RuneCountInString(line) <= width || unbreakable(line) {
+				continue
+			}
+			issues = append(issues, Issue{
+				Rule:     RuleBodyWrap,
+				Severity: SeverityWarning,
+				Line:     i + 1,
+				Message:  fmt.Sprintf("body line exceeds %d characters", width),
+			})
+		}
+	}
+
+	for _, word := range rules.ForbiddenWords {
+		if !english {
+			break
+		}
+		for i, line := range lines {
+			if containsWord(line, word) {
+				issues = append(issues, Issue{
+					Rule:     RuleForbiddenWord,
+					Severity: SeverityError,
+					Line:     i + 1,
+					Message:  fmt.Sprintf("avoid the word %q", word),
+				})
+				break
+			}
+		}
+	}
+
+	return issues
+}
+
+// generatedPrefixes start the subjects git writes itself for fixups,
+// squashes, merges and reverts.
+var generatedPrefixes = []string{"fixup! ", "squash! ", "amend! ", "Merge ", `Revert "`}
+
+// gitGenerated reports whether git wrote the subject, so the template's rules
+// do not apply to it.
+func gitGenerated(subject string) bool {
+	for _, prefix := range generatedPrefixes {
+		if strings.HasPrefix(subject, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// imperativeExceptions are common words whose suffix looks non-imperative.
+var imperativeExceptions = map[string]struct{}{
+	"bring": {}, "string": {}, "ring": {}, "sing": {}, "swing": {},
+	"embed": {}, "feed": {}, "need": {}, "seed": {}, "shed": {}, "speed": {}, "bleed": {},
+	"breed": {}, "proceed": {}, "succeed": {}, "exceed": {},
+	"address": {}, "access": {}, "bypass": {}, "pass": {}, "process": {}, "focus": {},
+	"bias": {}, "alias": {}, "canvas": {},
+}
+
+// nonImperative applies a cheap suffix heuristic to the first word of a description.
+func nonImperative(description string) (string, bool) {
+	fields := strings.Fields(description)
+	if len(fields) == 0 {
+		return "", false
+	}
+	word := strings.ToLower(strings.Trim(fields[0], ".,:;!?\"'`"))
+	if len(word) < 4 {
+		return "", false
+	}
+	if _, ok := imperativeExceptions[word]; ok {
+		return "", false
+	}
+	switch {
+	case strings.HasSuffix(word, "ed"), strings.HasSuffix(word, "ing"):
+		return word, true
+	case strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") &&
+		!strings.HasSuffix(word, "us") && !strings.HasSuffix(word, "is"):
+		return word, true
+	}
+	return "", false
+}
+
+// unbreakable reports lines that cannot be wrapped, such as long URLs.
+func unbreakable(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	trimmed = strings.TrimLeft(trimmed, "-*> ")
+	return !strings.ContainsAny(trimmed, " \t")
+}
+
+func containsWord(line, word string) bool {
+	line = strings.ToLower(line)
+	word = strings.ToLower(word)
+	for idx := 0; ; {
+		pos := strings.Index(line[idx:], word)
+		if pos < 0 {
+			return false
+		}
+		start := idx + pos
+		end := start + len(word)
+		if isBoundary(line, start-1) && isBoundary(line, end) {
+			return true
+		}
+		idx = start + 1
+	}
+}
+
+func isBoundary(s string, i int) bool {
+	if i < 0 || i >= len(s) {
+		return true
+	}
+	c := s[i]
+	return !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '_' || c >= 0x80)
+}
@@ -0,0 +1,162 @@
+package lint
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/samcharles93/commiter/internal/config"
+	"github.com/samcharles93/commiter/internal/llm"
+)
+
+func conventionalRules() Rules {
+	templates := config.GetDefaultTemplates()
+	return RulesFor(&templates[0], "")
+}
+
+func TestValidateAcceptsWellFormedMessage(t *testing.T) {
+	msg := "feat(ui): add lint warnings to review\n\n- show remaining issues below the message"
+	if issues := Validate(msg, conventionalRules()); len(issues) != 0 {
+		t.Fatalf("expected no issues, got %v", issues)
+	}
+}
+
+func TestValidateReportsRuleViolations(t *testing.T) {
+	rules := conventionalRules()
+	rules.ForbiddenWords = []string{"updated"}
+
+	tests := []struct {
+		name    string
+		message string
+		rule    string
+	}{
+		{name: "missing prefix", message: "add lint warnings", rule: RuleFormat},
+		{name: "unknown type", message: "feature: add lint warnings", rule: RuleType},
+		{name: "long subject", message: "feat: " + strings.Repeat("x", 80), rule: RuleSubjectLength},
+		{name: "past tense", message: "feat: added lint warnings", rule: RuleSubjectMood},
+		{name: "third person", message: "fix: adds missing check", rule: RuleSubjectMood},
+		{name: "no blank line", message: "feat: add lint\n- body", rule: RuleBlankLine},
+		{name: "wide body", message: "feat: add lint\n\n" + strings.Repeat("word ", 20), rule: RuleBodyWrap},
+		{name: "forbidden word", message: "docs: refresh readme\n\n- Updated install steps", rule: RuleForbiddenWord},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			issues := Validate(tc.message, rules)
+			for _, issue := range issues {
+				if issue.Rule == tc.rule {
+					return
+				}
+			}
+			t.Fatalf("expected %q issue, got %v", tc.rule, issues)
+		})
+	}
+}
+
+func TestValidateSkipsGitGeneratedSubjects(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+	}{
+		{name: "fixup", message: "fixup! feat: add lint warnings"},
+		{name: "squash", message: "squash! feat: add lint warnings\n\n- fold in the review notes"},
+		{name: "amend", message: "amend! feat: add lint warnings\n\nfeat: add lint warnings to review"},
+		{name: "merge branch", message: "Merge branch 'feature/lint' into main"},
+		{name: "merge pull request", message: "Merge pull request #42 from user/feature-lint"},
+		{name: "revert", message: "Revert \"feat: add lint warnings\"\n\nThis reverts commit 1234567."},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if issues := Validate(tc.message, conventionalRules()); len(issues) != 0 {
+				t.Fatalf("expected git's subject to be skipped, got %v", issues)
+			}
+		})
+	}
+}
+
+func TestValidateIgnoresUnbreakableBodyLines(t *testing.T) {
+	msg := "fix: correct link\n\nhttps://example.com/" + strings.Repeat("a", 100)
+	for _, issue := range Validate(msg, conventionalRules()) {
+		if issue.Rule == RuleBodyWrap {
+			t.Fatalf("expected long URL to be allowed, got %v", issue)
+		}
+	}
+}
+
+func TestForbiddenWordsFromMemory(t *testing.T) {
+	memory := `- never use the word 'updated' if you can use a more specific verb like 'refactored'.
+- Avoid the words "misc" and "stuff".
+- user likes lowercase subjects.`
+
+	got := ForbiddenWords(memory)
+	want := []string{"updated", "misc", "stuff"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected forbidden words %v, got %v", want, got)
+	}
+}
+
+func TestGenerateWithRepairSendsCorrectionUntilValid(t *testing.T) {
+	responses := []string{"Added lint", "feat: add lint"}
+	var calls [][]llm.Message
+
+	generate := func(_ context.Context, history []llm.Message) (string, error) {
+		calls = append(calls, history)
+		return responses[len(calls)-1], nil
+	}
+
+	msg, issues, err := GenerateWithRepair(context.Background(), generate, nil, conventionalRules(), 2)
+	if err != nil {
+		t.Fatalf("GenerateWithRepair returned error: %v", err)
+	}
+	if msg != "feat: add lint" {
+		t.Fatalf("expected repaired message, got %q", msg)
+	}
+	if len(issues) != 0 {
+		t.Fatalf("expected no remaining issues, got %v", issues)
+	}
+	if len(calls) != 2 {
+		t.Fatalf("expected one corrective follow-up, got %d calls", len(calls))
+	}
+	followUp := calls[1]
+	if len(followUp) != 2 || followUp[0].Content != "Added lint" || !strings.Contains(followUp[1].Content, RuleFormat) {
+		t.Fatalf("expected follow-up with previous answer and rule list, got %+v", followUp)
+	}
+}
+
+func TestGenerateWithRepairStopsAfterMaxAttempts(t *testing.T) {
+	calls := 0
+	generate := func(context.Context, []llm.Message) (string, error) {
+		calls++
+		return "still wrong", nil
+	}
+
+	_, issues, err := GenerateWithRepair(context.Background(), generate, nil, conventionalRules(), 2)
+	if err != nil {
+		t.Fatalf("GenerateWithRepair returned error: %v", err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected initial call plus two repairs, got %d", calls)
+	}
+	if !HasErrors(issues) {
+		t.Fatalf("expected remaining issues to be returned, got %v", issues)
+	}
+}
+
+func TestValidateSkipsEnglishRulesForOtherLanguages(t *testing.T) {
+	rules := conventionalRules()
+	rules.Language = "de"
+	rules.ForbiddenWords = []string{"updated"}
+
+	msg := "fix: behebt Absturz beim Speichern\n\n- updated Fehlerbehandlung"
+	for _, issue := range Validate(msg, rules) {
+		if issue.Rule == RuleSubjectMood || issue.Rule == RuleForbiddenWord {
+			t.Fatalf("expected English-only rules to be skipped, got %v", issue)
+		}
+	}
+
+	issues := Validate("fehler: behebt Absturz", rules)
+	if len(issues) == 0 || issues[0].Rule != RuleType {
+		t.Fatalf("expected type rule to still apply, got %v", issues)
+	}
+}
@@ -0,0 +1,59 @@
+package lint
+
+import (
+	"regexp"
+	"strings"
+)
+
+// forbiddenPatterns match preference notes such as
+// "never use the word 'updated'" or "avoid the words "misc" and "stuff"".
+var forbiddenPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\b(?:never|don't|do not)\s+use\s+the\s+words?\s+(.+)`),
+	regexp.MustCompile(`(?i)\bavoid\s+the\s+words?\s+(.+)`),
+}
+
+var quotedWord = regexp.MustCompile(`['"‘’“”` + "`" + `]([^'"‘’“”` + "`" + `]+)['"‘’“”` + "`" + `]`)
+
+// ForbiddenWords extracts quoted words the user asked never to use from MEMORY.md content.
+func ForbiddenWords(memory string) []string {
+	var words []string
+	seen := map[string]struct{}{}
+
+	for _, line := range strings.Split(memory, "\n") {
+		for _, pattern := range forbiddenPatterns {
+			match := pattern.FindStringSubmatch(line)
+			if match == nil {
+				continue
+			}
+			// Only the quoted words before any "if"/"unless" clause are forbidden.
+			tail := match[1]
+			if cut := clauseBoundary(tail); cut >= 0 {
+				tail = tail[:cut]
+			}
+			for _, quoted := range quotedWord.FindAllStringSubmatch(tail, -1) {
+				word := strings.ToLower(strings.TrimSpace(quoted[1]))
+				if word == "" {
+					continue
+				}
+				if _, ok := seen[word]; ok {
+					continue
+				}
+				seen[word] = struct{}{}
+				words = append(words, word)
+			}
+		}
+	}
+
+	return words
+}
+
+func clauseBoundary(s string) int {
+	lower := strings.ToLower(s)
+	cut := -1
+	for _, marker := range []string{" if ", " unless ", " when ", " instead"} {
+		if idx := strings.Index(lower, marker); idx >= 0 && (cut < 0 || idx < cut) {
+			cut = idx
+		}
+	}
+	return cut
+}
@@ -0,0 +1,52 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/samcharles93/commiter/internal/llm"
+)
+
+// Generator produces a commit message for the given follow-up conversation.
+type Generator func(ctx context.Context, history []llm.Message) (string, error)
+
+// GenerateWithRepair generates a message and, while it still violates error-level
+// rules, asks the model to correct it up to maxRepairs times. It returns the last
+// message together with any issues that remain.
+func GenerateWithRepair(ctx context.Context, generate Generator, history []llm.Message, rules Rules, maxRepairs int) (string, []Issue, error) {
+	message, err := generate(ctx, history)
+	if err != nil {
+		return "", nil, err
+	}
+
+	issues := Validate(message, rules)
+	for attempt := 0; attempt < maxRepairs && HasErrors(issues); attempt++ {
+		followUp := append([]llm.Message(nil), history...)
+		followUp = append(followUp,
+			llm.Message{Role: "assistant", Content: message},
+			llm.Message{Role: "user", Content: CorrectionPrompt(issues)},
+		)
+
+		repaired, err := generate(ctx, followUp)
+		if err != nil {
+			// Keep the best message so far; the remaining issues surface as warnings.
+			break
+		}
+		message = repaired
+		issues = Validate(message, rules)
+	}
+
+	return message, issues, nil
+}
+
+// CorrectionPrompt builds the follow-up instruction listing rule violations.
+func CorrectionPrompt(issues []Issue) string {
+	var b strings.Builder
+	b.WriteString("The commit message breaks these rules:\n")
+	for _, issue := range issues {
+		fmt.Fprintf(&b, "- %s\n", issue)
+	}
+	b.WriteString("\nRewrite the commit message so it follows every rule. Output ONLY the corrected commit message.")
+	return b.String()
+}
@@ -0,0 +1,143 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Report output formats.
+const (
+	FormatText   = "text"
+	FormatJSON   = "json"
+	FormatGitHub = "github"
+)
+
+// Formats lists the supported report formats.
+var Formats = []string{FormatText, FormatJSON, FormatGitHub}
+
+// Result holds the issues found in one commit message. Commit is empty for a
+// message that has not been committed yet, such as in a commit-msg hook.
+type Result struct {
+	Commit  string  `json:"commit,omitempty"`
+	Subject string  `json:"subject"`
+	Issues  []Issue `json:"issues"`
+}
+
+// Check validates one message and returns its result.
+func Check(commit, message string, rules Rules) Result {
+	subject, _, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	issues := Validate(message, rules)
+	if issues == nil {
+		issues = []Issue{}
+	}
+	return Result{Commit: commit, Subject: subject, Issues: issues}
+}
+
+// CleanMessage strips what git removes from an edited message before
+// committing: comment lines and everything below the scissors line.
+func CleanMessage(message string) string {
+	var lines []string
+	for _, line := range strings.Split(message, "\n") {
+		if strings.HasPrefix(line, "# ") && strings.Contains(line, ">8") {
+			break
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// Failed reports whether the results should fail the run: any error, or any
+// issue at all when strict.
+func Failed(results []Result, strict bool) bool {
+	for _, result := range results {
+		if HasErrors(result.Issues) || (strict && len(result.Issues) > 0) {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteReport writes the results in the given format.
+func WriteReport(w io.Writer, format string, results []Result) error {
+	switch format {
+	case FormatText, "":
+		return writeText(w, results)
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	case FormatGitHub:
+		return writeGitHub(w, results)
+	default:
+		return fmt.Errorf("unknown format %q (use one of: %s)", format, strings.Join(Formats, ", "))
+	}
+}
+
+func writeText(w io.Writer, results []Result) error {
+	var b strings.Builder
+	errors, warnings := 0, 0
+	for _, result := range results {
+		if len(result.Issues) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "%s %s\n", resultName(result), result.Subject)
+		for _, issue := range result.Issues {
+			fmt.Fprintf(&b, "  %-7s %s\n", issue.Severity, issue)
+			if issue.Severity == SeverityError {
+				errors++
+			} else {
+				warnings++
+			}
+		}
+	}
+
+	noun := "messages"
+	if len(results) == 1 {
+		noun = "message"
+	}
+	fmt.Fprintf(&b, "%d %s checked: %d errors, %d warnings\n", len(results), noun, errors, warnings)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeGitHub emits GitHub Actions workflow commands, which show up as
+// annotations on the run.
+func writeGitHub(w io.Writer, results []Result) error {
+	var b strings.Builder
+	for _, result := range results {
+		for _, issue := range result.Issues {
+			command := "error"
+			if issue.Severity == SeverityWarning {
+				command = "warning"
+			}
+			title := "commit message: " + issue.Rule
+			message := fmt.Sprintf("%s %q line %d: %s", resultName(result), result.Subject, issue.Line, issue.Message)
+			fmt.Fprintf(&b, "::%s title=%s::%s\n", command, escapeProperty(title), escapeData(message))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func resultName(result Result) string {
+	if result.Commit == "" {
+		return "message"
+	}
+	if len(result.Commit) > 8 {
+		return result.Commit[:8]
+	}
+	return result.Commit
+}
+
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
@@ -0,0 +1,96 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func sampleResults() []Result {
+	rules := conventionalRules()
+	return []Result{
+		Check("0123456789abcdef", "feat: add lint command", rules),
+		Check("fedcba9876543210", "added lint, command", rules),
+	}
+}
+
+func TestWriteReportText(t *testing.T) {
+	var out bytes.Buffer
+	if err := WriteReport(&out, FormatText, sampleResults()); err != nil {
+		t.Fatalf("WriteReport() error = %v", err)
+	}
+
+	report := out.String()
+	if strings.Contains(report, "01234567") {
+		t.Fatalf("expected clean commits to be left out:\n%s", report)
+	}
+	if !strings.Contains(report, "fedcba98 added lint, command\n  error   line 1:") {
+		t.Fatalf("expected the failing commit and its issues:\n%s", report)
+	}
+	if !strings.HasSuffix(report, "2 messages checked: 1 errors, 1 warnings\n") {
+		t.Fatalf("unexpected summary:\n%s", report)
+	}
+}
+
+func TestWriteReportJSON(t *testing.T) {
+	var out bytes.Buffer
+	if err := WriteReport(&out, FormatJSON, sampleResults()); err != nil {
+		t.Fatalf("WriteReport() error = %v", err)
+	}
+
+	var decoded []Result
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	if len(decoded) != 2 || len(decoded[0].Issues) != 0 || decoded[1].Issues[0].Rule != RuleFormat {
+		t.Fatalf("unexpected results: %+v", decoded)
+	}
+	if !strings.Contains(out.String(), `"issues": []`) {
+		t.Fatalf("expected clean commits to report an empty issue list:\n%s", out.String())
+	}
+}
+
+func TestWriteReportGitHubEscapesValues(t *testing.T) {
+	results := []Result{{
+		Subject: "100% done",
+		Issues:  []Issue{{Rule: RuleBodyWrap, Severity: SeverityWarning, Line: 3, Message: "line one\nline two"}},
+	}}
+
+	var out bytes.Buffer
+	if err := WriteReport(&out, FormatGitHub, results); err != nil {
+		t.Fatalf("WriteReport() error = %v", err)
+	}
+
+	want := "::warning title=commit message%3A body-wrap::message \"100%25 done\" line 3: line one%0Aline two\n"
+	if out.String() != want {
+		t.Fatalf("WriteReport() = %q, want %q", out.String(), want)
+	}
+}
+
+func TestWriteReportRejectsUnknownFormat(t *testing.T) {
+	if err := WriteReport(&bytes.Buffer{}, "xml", nil); err == nil {
+		t.Fatal("expected an error for an unknown format")
+	}
+}
+
+func TestCleanMessageDropsCommentsAndScissors(t *testing.T) {
+	message := "feat: add lint\n# Please enter the commit message\n\nBody line.\n" +
+		"# ------------------------ >8 ------------------------\ndiff --git a/x b/x\n"
+	if got := CleanMessage(message); got != "feat: add lint\n\nBody line." {
+		t.Fatalf("CleanMessage() = %q", got)
+	}
+}
+
+func TestFailed(t *testing.T) {
+	warning := []Result{{Issues: []Issue{{Severity: SeverityWarning}}}}
+	if Failed(warning, false) {
+		t.Fatal("warnings alone should not fail without strict")
+	}
+	if !Failed(warning, true) {
+		t.Fatal("warnings should fail with strict")
+	}
+	if !Failed(sampleResults(), false) {
+		t.Fatal("errors should fail")
+	}
+}
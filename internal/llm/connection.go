@@ -0,0 +1,76 @@
+package llm
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/samcharles93/commiter/internal/config"
+)
+
+const (
+	DefaultDeepSeekAPIURL = "https://api.deepseek.com/v1/chat/completions"
+	DefaultOpenAIAPIURL   = "https://api.openai.com/v1/chat/completions"
+)
+
+// Connection holds the resolved settings needed to talk to a provider.
+type Connection struct {
+	Provider string
+	APIKey   string
+	Model    string
+	BaseURL  string
+}
+
+// ResolveConnection merges provider defaults, environment keys, config values
+// and command line overrides. Overrides are ignored when empty.
+func ResolveConnection(cfg *config.Config, providerOverride, modelOverride string) (Connection, error) {
+	if cfg == nil {
+		cfg = &config.Config{}
+	}
+
+	// Determine provider
+	providerName := "deepseek"
+	if cfg.Provider != "" {
+		providerName = cfg.Provider
+	}
+	if providerOverride != "" {
+		providerName = providerOverride
+	}
+
+	conn := Connection{Provider: providerName}
+	switch strings.ToLower(providerName) {
+	case "openai":
+		conn.APIKey = os.Getenv("OPENAI_API_KEY")
+		conn.Model = "gpt-4o"
+		conn.BaseURL = DefaultOpenAIAPIURL
+	case "deepseek":
+		conn.APIKey = os.Getenv("DEEPSEEK_API_KEY")
+		conn.Model = "deepseek-chat"
+		conn.BaseURL = DefaultDeepSeekAPIURL
+	default:
+		return Connection{}, fmt.Errorf("unknown provider %q", providerName)
+	}
+
+	// Override with config values
+	if cfg.APIKey != "" {
+		conn.APIKey = cfg.APIKey
+	}
+	if cfg.Model != "" {
+		conn.Model = cfg.Model
+	}
+	if cfg.BaseURL != "" {
+		conn.BaseURL = cfg.BaseURL
+	}
+
+	// Override with flags
+	if modelOverride != "" {
+		conn.Model = modelOverride
+	}
+
+	return conn, nil
+}
+
+// NewProvider creates a GenericProvider for the connection.
+func (c Connection) NewProvider() *GenericProvider {
+	return NewGenericProvider(c.APIKey, c.Model, c.BaseURL)
+}
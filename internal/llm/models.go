@@ -0,0 +1,157 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	modelCacheFileName = "models.json"
+	modelCacheTTL      = 24 * time.Hour
+)
+
+// userCacheDir is swapped in tests.
+var userCacheDir = os.UserCacheDir
+
+type modelsResponse struct {
+	// OpenAI-compatible APIs
+	Data []struct {
+		ID string `json:"id"`
+	} `json:"data"`
+	// Ollama's /api/tags
+	Models []struct {
+		Name string `json:"name"`
+	} `json:"models"`
+}
+
+type modelCacheEntry struct {
+	FetchedAt time.Time `json:"fetched_at"`
+	Models    []string  `json:"models"`
+}
+
+// ModelsURL derives the model listing endpoint from a chat completions URL.
+func ModelsURL(baseURL string) string {
+	u := strings.TrimRight(baseURL, "/")
+	if trimmed, ok := strings.CutSuffix(u, "/chat/completions"); ok {
+		return trimmed + "/models"
+	}
+	return u + "/models"
+}
+
+// ListModels fetches the model IDs offered by the provider.
+func (s *GenericProvider) ListModels(ctx context.Context) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ModelsURL(s.baseURL), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.apiKey)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		if urlErr, ok := errors.AsType[*url.Error](err); ok && urlErr.Timeout() {
+			return nil, fmt.Errorf("request to LLM timed out: %w", err)
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	var payload modelsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("failed to decode model list: %w", err)
+	}
+
+	models := make([]string, 0, len(payload.Data)+len(payload.Models))
+	for _, m := range payload.Data {
+		if m.ID != "" {
+			models = append(models, m.ID)
+		}
+	}
+	for _, m := range payload.Models {
+		if m.Name != "" {
+			models = append(models, m.Name)
+		}
+	}
+	sort.Strings(models)
+	return models, nil
+}
+
+// CachedModels returns the provider's model list, served from a disk cache
+// for up to a day unless refresh is set.
+func (s *GenericProvider) CachedModels(ctx context.Context, refresh bool) ([]string, error) {
+	key := ModelsURL(s.baseURL)
+	cache := loadModelCache()
+	if entry, ok := cache[key]; ok && !refresh && time.Since(entry.FetchedAt) < modelCacheTTL {
+		return entry.Models, nil
+	}
+
+	models, err := s.ListModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cache[key] = modelCacheEntry{FetchedAt: time.Now(), Models: models}
+	// A failed cache write only costs a refetch next time.
+	_ = saveModelCache(cache)
+	return models, nil
+}
+
+// Ping sends a tiny prompt and reports the round-trip latency.
+func (s *GenericProvider) Ping(ctx context.Context) (time.Duration, error) {
+	start := time.Now()
+	_, err := s.Complete(ctx, []Message{{Role: "user", Content: "Reply with OK."}})
+	return time.Since(start), err
+}
+
+func modelCachePath() (string, error) {
+	dir, err := userCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "commiter", modelCacheFileName), nil
+}
+
+func loadModelCache() map[string]modelCacheEntry {
+	cache := map[string]modelCacheEntry{}
+	path, err := modelCachePath()
+	if err != nil {
+		return cache
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cache
+	}
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return map[string]modelCacheEntry{}
+	}
+	return cache
+}
+
+func saveModelCache(cache map[string]modelCacheEntry) error {
+	path, err := modelCachePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
@@ -0,0 +1,98 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/samcharles93/commiter/internal/config"
+)
+
+func TestModelsURL(t *testing.T) {
+	tests := map[string]string{
+		"https://api.openai.com/v1/chat/completions": "https://api.openai.com/v1/models",
+		"http://localhost:11434/v1/":                 "http://localhost:11434/v1/models",
+	}
+	for input, want := range tests {
+		if got := ModelsURL(input); got != want {
+			t.Fatalf("ModelsURL(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestCachedModelsFetchesOnceAndSorts(t *testing.T) {
+	cacheDir := t.TempDir()
+	prev := userCacheDir
+	userCacheDir = func() (string, error) { return cacheDir, nil }
+	t.Cleanup(func() { userCacheDir = prev })
+
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/v1/models" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		w.Write([]byte(`{"data":[{"id":"deepseek-reasoner"},{"id":"deepseek-chat"}]}`))
+	}))
+	defer ts.Close()
+
+	provider := NewGenericProvider("key", "deepseek-chat", ts.URL+"/v1/chat/completions")
+	for range 2 {
+		models, err := provider.CachedModels(context.Background(), false)
+		if err != nil {
+			t.Fatalf("CachedModels returned error: %v", err)
+		}
+		if len(models) != 2 || models[0] != "deepseek-chat" {
+			t.Fatalf("expected sorted model list, got %v", models)
+		}
+	}
+	if requests != 1 {
+		t.Fatalf("expected second call to hit the cache, got %d requests", requests)
+	}
+
+	if _, err := provider.CachedModels(context.Background(), true); err != nil {
+		t.Fatalf("refresh returned error: %v", err)
+	}
+	if requests != 2 {
+		t.Fatalf("expected refresh to bypass the cache, got %d requests", requests)
+	}
+}
+
+func TestPingReportsAuthError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"invalid key"}`, http.StatusUnauthorized)
+	}))
+	defer ts.Close()
+
+	_, err := NewGenericProvider("bad", "m", ts.URL).Ping(context.Background())
+	apiErr, ok := errors.AsType[*APIError](err)
+	if !ok || !apiErr.IsAuthError() {
+		t.Fatalf("expected auth APIError, got %v", err)
+	}
+}
+
+func TestResolveConnectionPrecedence(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "env-key")
+
+	conn, err := ResolveConnection(&config.Config{Provider: "openai", Model: "gpt-4.1"}, "", "")
+	if err != nil {
+		t.Fatalf("ResolveConnection returned error: %v", err)
+	}
+	if conn.APIKey != "env-key" || conn.Model != "gpt-4.1" || conn.BaseURL != DefaultOpenAIAPIURL {
+		t.Fatalf("unexpected connection %+v", conn)
+	}
+
+	conn, err = ResolveConnection(&config.Config{Provider: "openai"}, "deepseek", "deepseek-reasoner")
+	if err != nil {
+		t.Fatalf("ResolveConnection returned error: %v", err)
+	}
+	if conn.Provider != "deepseek" || conn.Model != "deepseek-reasoner" {
+		t.Fatalf("expected flag overrides to win, got %+v", conn)
+	}
+
+	if _, err := ResolveConnection(&config.Config{Provider: "nope"}, "", ""); err == nil {
+		t.Fatal("expected unknown provider error")
+	}
+}
@@ -9,7 +9,6 @@ import (
 	"io"
 	"net/http"
 	"net/url"
-	"os"
 	"strings"
 	"time"
 
@@ -26,12 +25,35 @@ type Provider interface {
 	SummarizeChanges(ctx context.Context, diff string) (string, error)
 }
 
+// Completer sends free-form chat conversations, for features that need more
+// than a commit message or a summary.
+type Completer interface {
+	Complete(ctx context.Context, messages []Message) (string, error)
+}
+
+// APIError is returned when the provider answers with a non-200 status.
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API request failed with status %d: %s", e.StatusCode, e.Body)
+}
+
+// IsAuthError reports whether the provider rejected the credentials.
+func (e *APIError) IsAuthError() bool {
+	return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+}
+
 // GenericProvider implements Provider for generic LLM APIs
 type GenericProvider struct {
 	apiKey  string
 	model   string
 	baseURL string
 	client  *http.Client
+	// dir is where SYSTEM.md and MEMORY.md are read from.
+	dir string
 }
 
 // NewGenericProvider creates a new GenericProvider
@@ -44,7 +66,15 @@ func NewGenericProvider(apiKey, model, baseURL string) *GenericProvider {
 	}
 }
 
-func (s *GenericProvider) complete(ctx context.Context, messages []Message) (string, error) {
+// WithDir makes the provider read SYSTEM.md and MEMORY.md from dir, the
+// repository root, rather than the working directory.
+func (s *GenericProvider) WithDir(dir string) *GenericProvider {
+	s.dir = dir
+	return s
+}
+
+// Complete sends a chat conversation to the model and returns its reply.
+func (s *GenericProvider) Complete(ctx context.Context, messages []Message) (string, error) {
 	reqBody := ChatRequest{
 		Model:    s.model,
 		Messages: messages,
@@ -74,7 +104,7 @@ func (s *GenericProvider) complete(ctx context.Context, messages []Message) (str
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return "", &APIError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	var chatResp ChatResponse
@@ -91,36 +121,48 @@ func (s *GenericProvider) complete(ctx context.Context, messages []Message) (str
 
 // GenerateMessage generates a commit message from a diff
 func (s *GenericProvider) GenerateMessage(ctx context.Context, diff string, history []Message, template *config.CommitTemplate) (string, error) {
-	systemPrompt, _ := os.ReadFile("SYSTEM.md")
-	memoryPrompt, _ := os.ReadFile("MEMORY.md")
+	memoryPrompt := config.ReadMemory(s.dir)
 
-	fullSystemPrompt := string(systemPrompt)
+	fullSystemPrompt := config.ReadSystemPrompt(s.dir)
 	if len(memoryPrompt) > 0 {
-		fullSystemPrompt += "\n\nUser Preferences:\n" + string(memoryPrompt)
+		fullSystemPrompt += "\n\nUser Preferences:\n" + memoryPrompt
 	}
 
-	// Add template instructions if provided
-	if template != nil && template.Prompt != "" {
-		fullSystemPrompt += "\n\nCommit Message Template Instructions:\n" + template.Prompt
-		if template.Format != "" {
-			fullSystemPrompt += "\n\nUse this format: " + template.Format
-		}
-	}
+	fullSystemPrompt += TemplateInstructions(template)
 
+	// The diff prompt always leads the conversation so follow-ups (regenerate,
+	// refine, lint repairs) keep the change context.
 	messages := []Message{
 		{Role: "system", Content: fullSystemPrompt},
+		{Role: "user", Content: buildCommitPrompt(diff)},
 	}
+	messages = append(messages, history...)
 
-	if len(history) == 0 {
-		messages = append(messages, Message{
-			Role:    "user",
-			Content: buildCommitPrompt(diff),
-		})
-	} else {
-		messages = append(messages, history...)
+	return s.Complete(ctx, messages)
+}
+
+// TemplateInstructions returns the system prompt additions for a commit
+// template: its prompt, format and language.
+func TemplateInstructions(template *config.CommitTemplate) string {
+	var instructions string
+	if template != nil && template.Prompt != "" {
+		instructions += "\n\nCommit Message Template Instructions:\n" + template.Prompt
+		if template.Format != "" {
+			instructions += "\n\nUse this format: " + template.Format
+		}
+	}
+	if template != nil && !config.IsEnglish(template.Language) {
+		instructions += "\n\n" + languageInstruction(template)
 	}
+	return instructions
+}
 
-	return s.complete(ctx, messages)
+func languageInstruction(template *config.CommitTemplate) string {
+	instruction := fmt.Sprintf("Language: write the commit message in %s.", config.LanguageName(template.Language))
+	if len(template.Types) > 0 {
+		instruction += " Keep the type prefixes (" + strings.Join(template.Types, ", ") + ") in English exactly as listed."
+	}
+	return instruction
 }
 
 func buildCommitPrompt(diff string) string {
@@ -160,7 +202,7 @@ func (s *GenericProvider) SummarizeChanges(ctx context.Context, diff string) (st
 		},
 	}
 
-	return s.complete(ctx, messages)
+	return s.Complete(ctx, messages)
 }
 
 func truncateDiffForSummary(diff string) string {
@@ -7,6 +7,8 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+
+	"github.com/samcharles93/commiter/internal/config"
 )
 
 func TestGenerateMessage(t *testing.T) {
@@ -120,3 +122,58 @@ index 111..222 100644
 		t.Fatalf("expected single-file prompt without multi-file guidance, got: %q", prompt)
 	}
 }
+
+func TestGenerateMessageKeepsDiffWithFollowUpHistory(t *testing.T) {
+	var req ChatRequest
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		json.NewEncoder(w).Encode(ChatResponse{
+			Choices: []struct {
+				Message Message `json:"message"`
+			}{{Message: Message{Role: "assistant", Content: "feat: other option"}}},
+		})
+	}))
+	defer ts.Close()
+
+	provider := NewGenericProvider("test-key", "test-model", ts.URL)
+	history := []Message{
+		{Role: "assistant", Content: "feat: first option"},
+		{Role: "user", Content: "Give me a different option."},
+	}
+	if _, err := provider.GenerateMessage(context.Background(), "unique diff body", history, nil); err != nil {
+		t.Fatalf("GenerateMessage returned error: %v", err)
+	}
+
+	if len(req.Messages) != 4 {
+		t.Fatalf("expected system, diff prompt and two follow-ups, got %d messages", len(req.Messages))
+	}
+	if !strings.Contains(req.Messages[1].Content, "unique diff body") {
+		t.Fatalf("expected diff prompt to lead the conversation, got %q", req.Messages[1].Content)
+	}
+}
+
+func TestGenerateMessageAddsLanguageInstruction(t *testing.T) {
+	var req ChatRequest
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&req)
+		json.NewEncoder(w).Encode(ChatResponse{
+			Choices: []struct {
+				Message Message `json:"message"`
+			}{{Message: Message{Role: "assistant", Content: "feat: füge Test hinzu"}}},
+		})
+	}))
+	defer ts.Close()
+
+	tmpl := &config.CommitTemplate{Prompt: "prompt", Types: []string{"feat", "fix"}, Language: "de"}
+	provider := NewGenericProvider("test-key", "test-model", ts.URL)
+	if _, err := provider.GenerateMessage(context.Background(), "diff", nil, tmpl); err != nil {
+		t.Fatalf("GenerateMessage returned error: %v", err)
+	}
+
+	system := req.Messages[0].Content
+	if !strings.Contains(system, "in German") || !strings.Contains(system, "feat, fix") {
+		t.Fatalf("expected German language instruction in system prompt, got %q", system)
+	}
+}
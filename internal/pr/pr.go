@@ -0,0 +1,167 @@
+// Package pr writes pull request titles and descriptions from a branch's
+// commits and diff.
+package pr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/samcharles93/commiter/internal/config"
+	"github.com/samcharles93/commiter/internal/git"
+	"github.com/samcharles93/commiter/internal/llm"
+)
+
+// maxDiffChars caps how much of the branch diff is sent to the model; the
+// commit messages carry the rest.
+const maxDiffChars = 60000
+
+// templatePaths are the places GitHub looks for a pull request template.
+var templatePaths = []string{
+	".github/pull_request_template.md",
+	".github/PULL_REQUEST_TEMPLATE.md",
+	"pull_request_template.md",
+	"PULL_REQUEST_TEMPLATE.md",
+	"docs/pull_request_template.md",
+	"docs/PULL_REQUEST_TEMPLATE.md",
+}
+
+const systemPrompt = `You write pull request descriptions for code review.
+Reply with the title on the first line, a blank line, then the description in
+markdown. The title is a short summary of the whole branch, without a trailing
+period. The description explains what changed and why, calls out anything
+reviewers should look at closely, and mentions how it was tested when the
+commits say so. Do not invent issue numbers, links or test results.`
+
+// Branch holds what the pull request describes.
+type Branch struct {
+	Name      string
+	Base      string
+	MergeBase string
+	// Commits are the branch's commits since the merge base, oldest first.
+	Commits []git.CommitMessage
+	Diff    string
+}
+
+// Description is a generated pull request title and body.
+type Description struct {
+	Title string
+	Body  string
+}
+
+// String renders the description with the title on the first line.
+func (d Description) String() string {
+	return d.Title + "\n\n" + d.Body + "\n"
+}
+
+// Gather collects the commits and diff of HEAD since it left base.
+func Gather(repo *git.Repo, base string) (*Branch, error) {
+	name, err := repo.CurrentBranch()
+	if err != nil {
+		return nil, err
+	}
+	mergeBase, err := repo.MergeBase(base, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	commits, err := repo.CommitMessages(mergeBase + "..HEAD")
+	if err != nil {
+		return nil, err
+	}
+	diff, err := repo.GetRangeDiff(mergeBase, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	return &Branch{Name: name, Base: base, MergeBase: mergeBase, Commits: commits, Diff: string(diff)}, nil
+}
+
+// FindTemplate returns the repository's pull request template, or "" when
+// there is none.
+func FindTemplate(dir string) (string, error) {
+	for _, path := range templatePaths {
+		data, err := os.ReadFile(filepath.Join(dir, path))
+		if err == nil {
+			return string(data), nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("failed to read %s: %w", path, err)
+		}
+	}
+	return "", nil
+}
+
+// Generate asks the model for the pull request title and description. When
+// template is set, the description follows its sections. memory is the
+// project's MEMORY.md, if any.
+func Generate(ctx context.Context, completer llm.Completer, branch *Branch, template, language, memory string) (Description, error) {
+	if len(branch.Commits) == 0 {
+		return Description{}, fmt.Errorf("no commits since %s", branch.Base)
+	}
+
+	system := systemPrompt
+	if memory != "" {
+		system += "\n\nUser Preferences:\n" + memory
+	}
+	if strings.TrimSpace(template) != "" {
+		system += "\n\nThe repository has a pull request template. Fill in its sections, keep its " +
+			"headings and checklists, and drop HTML comments:\n\n" + template
+	}
+	if language != "" && !config.IsEnglish(language) {
+		system += fmt.Sprintf("\n\nWrite the title and description in %s.", config.LanguageName(language))
+	}
+
+	reply, err := completer.Complete(ctx, []llm.Message{
+		{Role: "system", Content: system},
+		{Role: "user", Content: buildPrompt(branch)},
+	})
+	if err != nil {
+		return Description{}, fmt.Errorf("failed to generate pull request description: %w", err)
+	}
+	return parseDescription(reply)
+}
+
+func buildPrompt(branch *Branch) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Write a pull request for merging %s into %s.\n\n", branchName(branch), branch.Base)
+	fmt.Fprintf(&b, "Commits (%d):\n", len(branch.Commits))
+	for _, commit := range branch.Commits {
+		fmt.Fprintf(&b, "\n%s\n", commit.Message)
+	}
+
+	diff := branch.Diff
+	if len(diff) > maxDiffChars {
+		diff = diff[:maxDiffChars] + "\n...[diff truncated]"
+	}
+	fmt.Fprintf(&b, "\nDiff:\n%s", diff)
+	return b.String()
+}
+
+func branchName(branch *Branch) string {
+	if branch.Name == "" {
+		return "HEAD"
+	}
+	return branch.Name
+}
+
+// parseDescription splits the reply into title and body, tolerating a code
+// fence around it and a markdown heading on the title.
+func parseDescription(reply string) (Description, error) {
+	reply = strings.TrimSpace(reply)
+	if strings.HasPrefix(reply, "```") {
+		_, rest, _ := strings.Cut(reply, "\n")
+		reply = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(rest), "```"))
+	}
+
+	title, body, _ := strings.Cut(reply, "\n")
+	title = strings.TrimSpace(strings.TrimLeft(title, "# "))
+	title = strings.TrimPrefix(title, "Title:")
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return Description{}, fmt.Errorf("model returned an empty pull request title")
+	}
+	return Description{Title: title, Body: strings.TrimSpace(body)}, nil
+}
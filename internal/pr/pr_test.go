@@ -0,0 +1,99 @@
+package pr
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/samcharles93/commiter/internal/git"
+	"github.com/samcharles93/commiter/internal/llm"
+)
+
+type stubCompleter struct {
+	reply    string
+	messages []llm.Message
+}
+
+func (s *stubCompleter) Complete(_ context.Context, messages []llm.Message) (string, error) {
+	s.messages = messages
+	return s.reply, nil
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s failed: %v\n%s", strings.Join(args, " "), err, string(out))
+	}
+	return string(out)
+}
+
+func TestGatherAndGenerate(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repoDir := t.TempDir()
+	runGit(t, repoDir, "init", "-b", "main")
+	runGit(t, repoDir, "config", "user.email", "test@example.com")
+	runGit(t, repoDir, "config", "user.name", "Test User")
+	runGit(t, repoDir, "commit", "--allow-empty", "-m", "chore: init")
+	runGit(t, repoDir, "checkout", "-b", "feature/export")
+	if err := os.WriteFile(filepath.Join(repoDir, "export.go"), []byte("package export\n"), 0o644); err != nil {
+		t.Fatalf("write export.go: %v", err)
+	}
+	runGit(t, repoDir, "add", "export.go")
+	runGit(t, repoDir, "commit", "-m", "feat: add export package")
+	runGit(t, repoDir, "checkout", "main")
+	runGit(t, repoDir, "commit", "--allow-empty", "-m", "fix: unrelated main change")
+	runGit(t, repoDir, "checkout", "feature/export")
+
+	repo := git.NewRepo(repoDir, git.ExecRunner{})
+	branch, err := Gather(repo, "main")
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	if branch.Name != "feature/export" || len(branch.Commits) != 1 || !strings.Contains(branch.Diff, "+package export") {
+		t.Fatalf("unexpected branch: %+v", branch)
+	}
+
+	if err := os.MkdirAll(filepath.Join(repoDir, ".github"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, ".github", "pull_request_template.md"), []byte("## Summary\n\n## Testing\n"), 0o644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	template, err := FindTemplate(repoDir)
+	if err != nil || template != "## Summary\n\n## Testing\n" {
+		t.Fatalf("FindTemplate() = %q, %v", template, err)
+	}
+
+	completer := &stubCompleter{reply: "```markdown\n# Add export package\n\n## Summary\nAdds the export package.\n```"}
+	description, err := Generate(context.Background(), completer, branch, template, "", "")
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if description.Title != "Add export package" || description.Body != "## Summary\nAdds the export package." {
+		t.Fatalf("unexpected description: %+v", description)
+	}
+	if !strings.Contains(completer.messages[0].Content, "## Testing") {
+		t.Fatal("expected the pull request template in the system prompt")
+	}
+	prompt := completer.messages[1].Content
+	if !strings.Contains(prompt, "merging feature/export into main") || !strings.Contains(prompt, "feat: add export package") ||
+		strings.Contains(prompt, "unrelated main change") {
+		t.Fatalf("unexpected prompt:\n%s", prompt)
+	}
+}
+
+func TestFindTemplateWithoutTemplate(t *testing.T) {
+	if template, err := FindTemplate(t.TempDir()); err != nil || template != "" {
+		t.Fatalf("FindTemplate() = %q, %v", template, err)
+	}
+}
@@ -0,0 +1,54 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/samcharles93/commiter/internal/config"
+	"github.com/samcharles93/commiter/internal/llm"
+)
+
+const notesSystemPrompt = `You write the message of an annotated git tag for a software release.
+The first line is a short title for the release, without the version number.
+After a blank line, summarise what changed for users: breaking changes first,
+then new features, then fixes, as short bullet points starting with "- ".
+Leave out internal chores unless they matter to users.
+Reply with the tag message only, as plain text.`
+
+// GenerateNotes asks the model for the tag message of the planned release.
+// memory is the project's MEMORY.md, if any.
+func GenerateNotes(ctx context.Context, completer llm.Completer, plan *Plan, language, memory string) (string, error) {
+	system := notesSystemPrompt
+	if memory != "" {
+		system += "\n\nUser Preferences:\n" + memory
+	}
+	if language != "" && !config.IsEnglish(language) {
+		system += fmt.Sprintf("\n\nWrite the message in %s.", config.LanguageName(language))
+	}
+
+	var prompt strings.Builder
+	fmt.Fprintf(&prompt, "Release %s", plan.Tag())
+	if plan.Previous != "" {
+		fmt.Fprintf(&prompt, " (previous release %s)", plan.Previous)
+	}
+	fmt.Fprintf(&prompt, ", a %s version bump.\n\nChanges:\n%s", plan.Bump, plan.Notes())
+
+	reply, err := completer.Complete(ctx, []llm.Message{
+		{Role: "system", Content: system},
+		{Role: "user", Content: prompt.String()},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to generate release notes: %w", err)
+	}
+
+	notes := strings.TrimSpace(reply)
+	notes = strings.TrimPrefix(notes, "```text")
+	notes = strings.TrimPrefix(notes, "```")
+	notes = strings.TrimSuffix(notes, "```")
+	notes = strings.TrimSpace(notes)
+	if notes == "" {
+		return "", fmt.Errorf("model returned empty release notes")
+	}
+	return notes, nil
+}
@@ -0,0 +1,107 @@
+package release
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/samcharles93/commiter/internal/changelog"
+	"github.com/samcharles93/commiter/internal/git"
+)
+
+// Plan describes the next release.
+type Plan struct {
+	// Previous is the latest release tag, or "" when there is none.
+	Previous string
+	// Latest is the newest semver tag, which may be a pre-release.
+	Latest string
+	Next   Version
+	Bump   Bump
+	// Commits are the commits since Previous, oldest first.
+	Commits []git.CommitMessage
+	Entries []changelog.Entry
+}
+
+// Tag returns the name of the tag to create.
+func (p *Plan) Tag() string {
+	return p.Next.String()
+}
+
+// Notes renders the release's changes as changelog markdown.
+func (p *Plan) Notes() string {
+	return changelog.Build(p.Tag(), "", p.Entries, true).Markdown()
+}
+
+// BumpFor returns the increment the entries call for: major for breaking
+// changes, minor for features and patch for fixes and performance work.
+// When types is not empty, entries of other types are ignored, as they do
+// not follow the template.
+func BumpFor(entries []changelog.Entry, types []string) Bump {
+	bump := BumpNone
+	for _, entry := range entries {
+		if entry.Type == "" || (len(types) > 0 && !slices.Contains(types, entry.Type)) {
+			continue
+		}
+		switch {
+		case entry.Breaking != "":
+			return BumpMajor
+		case entry.Type == "feat":
+			bump = max(bump, BumpMinor)
+		case entry.Type == "fix" || entry.Type == "perf":
+			bump = max(bump, BumpPatch)
+		}
+	}
+	return bump
+}
+
+// PlanRelease finds the latest semver tags reachable from HEAD and computes
+// the next version from the commits since the latest release. pre is an
+// optional pre-release identifier such as "rc"; types are the template's
+// commit types.
+func PlanRelease(repo *git.Repo, types []string, pre string) (*Plan, error) {
+	tags, err := repo.Tags("HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	stable := Version{Prefix: "v"}
+	latest := stable
+	plan := &Plan{}
+	for _, tag := range tags {
+		version, ok := ParseVersion(tag)
+		if !ok {
+			continue
+		}
+		if plan.Latest == "" || Compare(version, latest) > 0 {
+			latest, plan.Latest = version, tag
+		}
+		if version.Pre == "" && (plan.Previous == "" || Compare(version, stable) > 0) {
+			stable, plan.Previous = version, tag
+		}
+	}
+	if plan.Previous == "" {
+		// Without a release yet, count from 0.0.0 in the style of any
+		// pre-release tag.
+		stable.Prefix = latest.Prefix
+	}
+
+	revRange := "HEAD"
+	if plan.Previous != "" {
+		revRange = plan.Previous + "..HEAD"
+	}
+	plan.Commits, err = repo.CommitMessages(revRange)
+	if err != nil {
+		return nil, err
+	}
+	plan.Entries = changelog.Parse(plan.Commits)
+	plan.Bump = BumpFor(plan.Entries, types)
+
+	plan.Next = Next(stable, latest, plan.Bump, pre)
+	if plan.Next == stable || (plan.Latest != "" && Compare(plan.Next, latest) <= 0) {
+		since := "the start of the history"
+		if plan.Previous != "" {
+			since = plan.Previous
+		}
+		return nil, fmt.Errorf("no features, fixes or breaking changes since %s; nothing to release", since)
+	}
+	return plan, nil
+}
@@ -0,0 +1,152 @@
+package release
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/samcharles93/commiter/internal/changelog"
+	"github.com/samcharles93/commiter/internal/git"
+	"github.com/samcharles93/commiter/internal/llm"
+)
+
+type stubCompleter struct {
+	reply    string
+	messages []llm.Message
+}
+
+func (s *stubCompleter) Complete(_ context.Context, messages []llm.Message) (string, error) {
+	s.messages = messages
+	return s.reply, nil
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s failed: %v\n%s", strings.Join(args, " "), err, string(out))
+	}
+	return string(out)
+}
+
+func mustParse(t *testing.T, tag string) Version {
+	t.Helper()
+	version, ok := ParseVersion(tag)
+	if !ok {
+		t.Fatalf("ParseVersion(%q) failed", tag)
+	}
+	return version
+}
+
+func TestParseVersion(t *testing.T) {
+	version := mustParse(t, "v1.20.3-rc.1+build.5")
+	if version != (Version{Prefix: "v", Major: 1, Minor: 20, Patch: 3, Pre: "rc.1"}) {
+		t.Fatalf("unexpected version: %+v", version)
+	}
+	if version.String() != "v1.20.3-rc.1" {
+		t.Fatalf("String() = %q", version.String())
+	}
+	for _, tag := range []string{"1.2", "v01.2.3", "release-1.2.3", "latest"} {
+		if _, ok := ParseVersion(tag); ok {
+			t.Fatalf("expected %q to be rejected", tag)
+		}
+	}
+}
+
+func TestCompareFollowsSemverPrecedence(t *testing.T) {
+	ordered := []string{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "v1.0.1", "1.1.0", "2.0.0"}
+	for i := 1; i < len(ordered); i++ {
+		if Compare(mustParse(t, ordered[i-1]), mustParse(t, ordered[i])) >= 0 {
+			t.Fatalf("expected %s < %s", ordered[i-1], ordered[i])
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		stable, latest string
+		bump           Bump
+		pre, want      string
+	}{
+		{"v1.2.3", "v1.2.3", BumpPatch, "", "v1.2.4"},
+		{"v1.2.3", "v1.2.3", BumpMinor, "", "v1.3.0"},
+		{"v1.2.3", "v1.2.3", BumpMajor, "", "v2.0.0"},
+		{"v1.2.3", "v1.2.3", BumpMinor, "rc", "v1.3.0-rc.1"},
+		{"v1.2.3", "v1.3.0-rc.1", BumpMinor, "rc", "v1.3.0-rc.2"},
+		{"v1.2.3", "v1.3.0-beta.4", BumpPatch, "rc", "v1.3.0-rc.1"},
+		{"v1.2.3", "v1.3.0-rc.2", BumpMinor, "", "v1.3.0"},
+		{"v1.2.3", "v1.3.0-rc.2", BumpNone, "", "v1.3.0"},
+		{"v1.2.3", "v1.2.4-rc.1", BumpMajor, "rc", "v2.0.0-rc.1"},
+	}
+	for _, tt := range tests {
+		got := Next(mustParse(t, tt.stable), mustParse(t, tt.latest), tt.bump, tt.pre)
+		if got.String() != tt.want {
+			t.Errorf("Next(%s, %s, %s, %q) = %s, want %s", tt.stable, tt.latest, tt.bump, tt.pre, got, tt.want)
+		}
+	}
+}
+
+func TestBumpForUsesTemplateTypes(t *testing.T) {
+	entries := []changelog.Entry{{Type: "fix"}, {Type: "docs"}, {Type: "feat"}}
+	if bump := BumpFor(entries, nil); bump != BumpMinor {
+		t.Fatalf("BumpFor() = %s, want minor", bump)
+	}
+	if bump := BumpFor(entries, []string{"fix", "docs"}); bump != BumpPatch {
+		t.Fatalf("expected feat to be ignored when the template lacks it, got %s", bump)
+	}
+	entries = append(entries, changelog.Entry{Type: "refactor", Breaking: "drops the old API"})
+	if bump := BumpFor(entries, nil); bump != BumpMajor {
+		t.Fatalf("BumpFor() = %s, want major", bump)
+	}
+}
+
+func TestPlanRelease(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repoDir := t.TempDir()
+	runGit(t, repoDir, "init", "-b", "main")
+	runGit(t, repoDir, "config", "user.email", "test@example.com")
+	runGit(t, repoDir, "config", "user.name", "Test User")
+	runGit(t, repoDir, "commit", "--allow-empty", "-m", "feat: first")
+	runGit(t, repoDir, "tag", "v1.4.2")
+	runGit(t, repoDir, "tag", "not-a-version")
+	runGit(t, repoDir, "commit", "--allow-empty", "-m", "chore: tidy")
+	repo := git.NewRepo(repoDir, git.ExecRunner{})
+
+	if _, err := PlanRelease(repo, nil, ""); err == nil || !strings.Contains(err.Error(), "nothing to release") {
+		t.Fatalf("expected nothing to release, got %v", err)
+	}
+
+	runGit(t, repoDir, "commit", "--allow-empty", "-m", "fix(api): handle timeouts")
+	plan, err := PlanRelease(repo, nil, "rc")
+	if err != nil {
+		t.Fatalf("PlanRelease() error = %v", err)
+	}
+	if plan.Tag() != "v1.4.3-rc.1" || plan.Previous != "v1.4.2" || len(plan.Commits) != 2 {
+		t.Fatalf("unexpected plan: tag %s previous %s commits %d", plan.Tag(), plan.Previous, len(plan.Commits))
+	}
+
+	runGit(t, repoDir, "tag", "v1.4.3-rc.1")
+	if plan, err = PlanRelease(repo, nil, "rc"); err != nil || plan.Tag() != "v1.4.3-rc.2" {
+		t.Fatalf("expected the next release candidate, got %v, %v", plan, err)
+	}
+	if plan, err = PlanRelease(repo, nil, ""); err != nil || plan.Tag() != "v1.4.3" {
+		t.Fatalf("expected the release candidate to be promoted, got %v, %v", plan, err)
+	}
+
+	completer := &stubCompleter{reply: "```\nTimeout fixes\n\n- API calls no longer hang\n```"}
+	notes, err := GenerateNotes(context.Background(), completer, plan, "", "")
+	if err != nil || notes != "Timeout fixes\n\n- API calls no longer hang" {
+		t.Fatalf("GenerateNotes() = %q, %v", notes, err)
+	}
+	if prompt := completer.messages[1].Content; !strings.Contains(prompt, "Release v1.4.3 (previous release v1.4.2), a patch version bump") ||
+		!strings.Contains(prompt, "**api:** handle timeouts") {
+		t.Fatalf("unexpected prompt:\n%s", prompt)
+	}
+}
@@ -0,0 +1,156 @@
+// Package release computes the next semantic version from Conventional
+// Commits history and writes the release notes for its tag.
+package release
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Version is a semantic version as written in a tag, such as "v1.2.3-rc.1".
+type Version struct {
+	// Prefix is kept from the tag, usually "v" or empty.
+	Prefix string
+	Major  int
+	Minor  int
+	Patch  int
+	// Pre is the pre-release part without the dash, such as "rc.1".
+	Pre string
+}
+
+var versionPattern = regexp.MustCompile(`^(v?)(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`)
+
+// ParseVersion parses a tag name. Build metadata is accepted and dropped.
+func ParseVersion(tag string) (Version, bool) {
+	match := versionPattern.FindStringSubmatch(strings.TrimSpace(tag))
+	if match == nil {
+		return Version{}, false
+	}
+	major, _ := strconv.Atoi(match[2])
+	minor, _ := strconv.Atoi(match[3])
+	patch, _ := strconv.Atoi(match[4])
+	return Version{Prefix: match[1], Major: major, Minor: minor, Patch: patch, Pre: match[5]}, true
+}
+
+func (v Version) String() string {
+	s := fmt.Sprintf("%s%d.%d.%d", v.Prefix, v.Major, v.Minor, v.Patch)
+	if v.Pre != "" {
+		s += "-" + v.Pre
+	}
+	return s
+}
+
+// Core returns the version without its pre-release part.
+func (v Version) Core() Version {
+	v.Pre = ""
+	return v
+}
+
+// Compare orders versions by semver precedence, ignoring the prefix.
+func Compare(a, b Version) int {
+	if c := cmp.Compare(a.Major, b.Major); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.Minor, b.Minor); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.Patch, b.Patch); c != 0 {
+		return c
+	}
+	switch {
+	case a.Pre == b.Pre:
+		return 0
+	case a.Pre == "":
+		return 1
+	case b.Pre == "":
+		return -1
+	}
+
+	left, right := strings.Split(a.Pre, "."), strings.Split(b.Pre, ".")
+	for i := 0; i < len(left) && i < len(right); i++ {
+		ln, lerr := strconv.Atoi(left[i])
+		rn, rerr := strconv.Atoi(right[i])
+		var c int
+		switch {
+		case lerr == nil && rerr == nil:
+			c = cmp.Compare(ln, rn)
+		case lerr == nil:
+			c = -1
+		case rerr == nil:
+			c = 1
+		default:
+			c = strings.Compare(left[i], right[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(left), len(right))
+}
+
+// Bump is the size of a version increment.
+type Bump int
+
+const (
+	BumpNone Bump = iota
+	BumpPatch
+	BumpMinor
+	BumpMajor
+)
+
+func (b Bump) String() string {
+	switch b {
+	case BumpPatch:
+		return "patch"
+	case BumpMinor:
+		return "minor"
+	case BumpMajor:
+		return "major"
+	default:
+		return "none"
+	}
+}
+
+// Apply increments the version, dropping any pre-release part.
+func (v Version) Apply(bump Bump) Version {
+	v.Pre = ""
+	switch bump {
+	case BumpMajor:
+		v.Major, v.Minor, v.Patch = v.Major+1, 0, 0
+	case BumpMinor:
+		v.Minor, v.Patch = v.Minor+1, 0
+	case BumpPatch:
+		v.Patch++
+	}
+	return v
+}
+
+// Next computes the version after stable, the latest release, given the
+// latest tag overall (which may be a newer pre-release) and an optional
+// pre-release identifier such as "rc".
+//
+// A pending pre-release whose core already covers the bump is continued:
+// "v1.3.0-rc.1" becomes "v1.3.0-rc.2" with the same identifier, or
+// "v1.3.0" without one.
+func Next(stable, latest Version, bump Bump, pre string) Version {
+	next := stable.Apply(bump)
+	if latest.Pre != "" && Compare(latest.Core(), next) > 0 {
+		next = latest.Core()
+	}
+	if pre == "" {
+		return next
+	}
+
+	number := 1
+	if latest.Pre != "" && Compare(latest.Core(), next) == 0 {
+		id, n, ok := strings.Cut(latest.Pre, ".")
+		if current, err := strconv.Atoi(n); ok && id == pre && err == nil {
+			number = current + 1
+		}
+	}
+	next.Pre = fmt.Sprintf("%s.%d", pre, number)
+	return next
+}
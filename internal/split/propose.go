@@ -0,0 +1,128 @@
+package split
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/samcharles93/commiter/internal/config"
+	"github.com/samcharles93/commiter/internal/llm"
+)
+
+const (
+	// maxUnitChars caps how much of each unit's diff is sent to the model.
+	maxUnitChars = 2000
+	// maxPromptChars caps the diff text across all units.
+	maxPromptChars = 40000
+)
+
+const proposeSystemPrompt = `You split a developer's uncommitted changes into a series of small, logical commits.
+Each change is a numbered unit: a whole file or a single hunk. Put related units in the same commit,
+keep unrelated changes apart, and order commits so each one builds on the ones before it.
+Write a commit message for every commit.
+
+Reply with JSON only, in this shape:
+{"commits": [{"message": "subject line\n\noptional body", "units": ["u1", "u3"]}]}
+Every unit must appear in exactly one commit.`
+
+type proposal struct {
+	Commits []struct {
+		Message string   `json:"message"`
+		Units   []string `json:"units"`
+	} `json:"commits"`
+}
+
+// Propose asks the model to group the plan's units into commits and replaces
+// the plan's groups with its answer. Units the model leaves out or does not
+// recognise are collected into a final group so nothing is lost. memory is
+// the project's MEMORY.md, if any.
+func Propose(ctx context.Context, completer llm.Completer, plan *Plan, template *config.CommitTemplate, memory string) error {
+	if len(plan.Units) == 0 {
+		return fmt.Errorf("no changes to split")
+	}
+
+	system := proposeSystemPrompt
+	if memory != "" {
+		system += "\n\nUser Preferences:\n" + memory
+	}
+	system += llm.TemplateInstructions(template)
+
+	reply, err := completer.Complete(ctx, []llm.Message{
+		{Role: "system", Content: system},
+		{Role: "user", Content: buildProposePrompt(plan)},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to propose commits: %w", err)
+	}
+
+	groups, err := parseProposal(reply, plan)
+	if err != nil {
+		return err
+	}
+	plan.Groups = groups
+	return nil
+}
+
+func buildProposePrompt(plan *Plan) string {
+	var b strings.Builder
+	b.WriteString("Group these changes into commits:\n")
+
+	budget := maxPromptChars
+	for _, unit := range plan.Units {
+		diff := unit.Diff
+		if len(diff) > maxUnitChars {
+			diff = diff[:maxUnitChars] + "\n...[truncated]"
+		}
+		if len(diff) > budget {
+			diff = "[diff omitted]"
+		}
+		budget -= len(diff)
+
+		fmt.Fprintf(&b, "\n### %s: %s\n%s\n", unit.ID, unit.Title(), diff)
+	}
+	return b.String()
+}
+
+// parseProposal reads the model's JSON reply, tolerating code fences and
+// surrounding prose.
+func parseProposal(reply string, plan *Plan) ([]Group, error) {
+	start := strings.Index(reply, "{")
+	end := strings.LastIndex(reply, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("model did not return a commit plan")
+	}
+
+	var parsed proposal
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse commit plan: %w", err)
+	}
+
+	assigned := map[string]bool{}
+	var groups []Group
+	for _, commit := range parsed.Commits {
+		group := Group{Message: strings.TrimSpace(commit.Message)}
+		for _, id := range commit.Units {
+			id = strings.TrimSpace(id)
+			if _, ok := plan.Unit(id); !ok || assigned[id] {
+				continue
+			}
+			assigned[id] = true
+			group.Units = append(group.Units, id)
+		}
+		if len(group.Units) > 0 {
+			groups = append(groups, group)
+		}
+	}
+
+	var leftover []string
+	for _, unit := range plan.Units {
+		if !assigned[unit.ID] {
+			leftover = append(leftover, unit.ID)
+		}
+	}
+	if len(leftover) > 0 {
+		groups = append(groups, Group{Units: leftover})
+	}
+	return groups, nil
+}
@@ -0,0 +1,289 @@
+// Package split breaks a set of working tree changes into several commits.
+package split
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/samcharles93/commiter/internal/git"
+)
+
+// Unit is the smallest piece of a change that can be moved between commits:
+// a whole file, or one hunk of a modified file.
+type Unit struct {
+	ID   string
+	Path string
+	// OrigPath is the source of a rename or copy.
+	OrigPath string
+	// HunkID is set when the unit is a single hunk rather than the whole file.
+	HunkID string
+	// Label describes the unit for display, e.g. "modified" or "hunk 2/3".
+	Label string
+	// Diff is the unit's diff text, or a note for files git shows no diff for.
+	Diff string
+}
+
+// Title describes the unit in one line, e.g. "main.go (hunk 1/2)".
+func (u Unit) Title() string {
+	if u.OrigPath != "" {
+		return fmt.Sprintf("%s → %s (%s)", u.OrigPath, u.Path, u.Label)
+	}
+	return fmt.Sprintf("%s (%s)", u.Path, u.Label)
+}
+
+// Group is one planned commit.
+type Group struct {
+	Message string
+	Units   []string
+}
+
+// Plan is a proposed series of commits covering every unit.
+type Plan struct {
+	Units  []Unit
+	Groups []Group
+	files  map[string]git.FileDiff
+}
+
+// BuildPlan collects the working tree changes, staged or not, as units. Files
+// with more than one hunk are broken up so their hunks can go to different
+// commits; new, deleted, renamed and binary files always move as a whole.
+func BuildPlan(repo *git.Repo) (*Plan, error) {
+	changes, err := repo.ListChanges()
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{files: map[string]git.FileDiff{}}
+	if repo.CanAmend() {
+		diff, err := repo.GetWorkingDiff()
+		if err != nil {
+			return nil, err
+		}
+		files, err := git.ParseDiff(string(diff))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse working tree changes: %w", err)
+		}
+		for _, file := range files {
+			plan.files[file.Path()] = file
+		}
+	}
+
+	for _, change := range changes {
+		if change.Conflict {
+			return nil, fmt.Errorf("resolve the conflict in %s before splitting", change.Path)
+		}
+		if change.IsSubmodule() {
+			plan.addUnit(Unit{Path: change.Path, OrigPath: change.OrigPath, Label: "submodule", Diff: "submodule change"})
+			continue
+		}
+
+		file, ok := plan.files[change.Path]
+		if !ok || !splittable(file, change) {
+			unit := Unit{Path: change.Path, OrigPath: change.OrigPath, Label: unitLabel(change)}
+			switch {
+			case change.Untracked:
+				unit.Diff = "new untracked file"
+			case ok && file.Binary:
+				unit.Diff = "binary file"
+			case ok:
+				unit.Diff = fileText(file)
+			default:
+				unit.Diff = unit.Label + " file"
+			}
+			plan.addUnit(unit)
+			continue
+		}
+
+		for i, hunk := range file.Hunks {
+			plan.addUnit(Unit{
+				Path:   change.Path,
+				HunkID: hunk.ID,
+				Label:  fmt.Sprintf("hunk %d/%d", i+1, len(file.Hunks)),
+				Diff:   hunkText(hunk),
+			})
+		}
+	}
+
+	return plan, nil
+}
+
+// splittable reports whether a file's hunks can be committed separately.
+func splittable(file git.FileDiff, change git.ChangedFile) bool {
+	return !file.Binary &&
+		len(file.Hunks) > 1 &&
+		change.OrigPath == "" &&
+		!change.Untracked &&
+		!file.IsDeletion() &&
+		file.OldPath == file.NewPath
+}
+
+func unitLabel(change git.ChangedFile) string {
+	if change.Untracked {
+		return "new"
+	}
+	return change.Status
+}
+
+func (p *Plan) addUnit(unit Unit) {
+	unit.ID = fmt.Sprintf("u%d", len(p.Units)+1)
+	p.Units = append(p.Units, unit)
+}
+
+// Unit returns the unit with the given ID.
+func (p *Plan) Unit(id string) (Unit, bool) {
+	for _, unit := range p.Units {
+		if unit.ID == id {
+			return unit, true
+		}
+	}
+	return Unit{}, false
+}
+
+// GroupOf returns the index of the group holding the unit, or -1.
+func (p *Plan) GroupOf(id string) int {
+	for i, group := range p.Groups {
+		if slices.Contains(group.Units, id) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Move puts the unit into the group at index to. An index one past the last
+// group starts a new group. Groups left empty are removed.
+func (p *Plan) Move(id string, to int) error {
+	if _, ok := p.Unit(id); !ok {
+		return fmt.Errorf("unknown unit %s", id)
+	}
+	if to < 0 || to > len(p.Groups) {
+		return fmt.Errorf("there is no commit %d", to+1)
+	}
+	if to == len(p.Groups) {
+		p.Groups = append(p.Groups, Group{})
+	}
+
+	if from := p.GroupOf(id); from >= 0 {
+		p.Groups[from].Units = slices.DeleteFunc(p.Groups[from].Units, func(u string) bool { return u == id })
+	}
+	p.Groups[to].Units = append(p.Groups[to].Units, id)
+	p.Groups = slices.DeleteFunc(p.Groups, func(g Group) bool { return len(g.Units) == 0 })
+	return nil
+}
+
+// Validate checks that every group has a message and every unit a group.
+func (p *Plan) Validate() error {
+	for i, group := range p.Groups {
+		if strings.TrimSpace(group.Message) == "" {
+			return fmt.Errorf("commit %d has no message", i+1)
+		}
+	}
+	for _, unit := range p.Units {
+		if p.GroupOf(unit.ID) < 0 {
+			return fmt.Errorf("%s is not in any commit", unit.Title())
+		}
+	}
+	return nil
+}
+
+// GroupPaths returns the paths touched by the group at index i.
+func (p *Plan) GroupPaths(i int) []string {
+	var paths []string
+	for _, id := range p.Groups[i].Units {
+		unit, _ := p.Unit(id)
+		for _, path := range []string{unit.OrigPath, unit.Path} {
+			if path != "" && !slices.Contains(paths, path) {
+				paths = append(paths, path)
+			}
+		}
+	}
+	return paths
+}
+
+// Reset unstages everything so the index matches HEAD before the first group
+// is staged.
+func (p *Plan) Reset(repo *git.Repo) error {
+	changes, err := repo.ListChanges()
+	if err != nil {
+		return err
+	}
+
+	var staged []string
+	for _, change := range changes {
+		if change.HasStagedChanges() {
+			staged = append(staged, change.Path)
+			if change.OrigPath != "" {
+				staged = append(staged, change.OrigPath)
+			}
+		}
+	}
+	return repo.UnstageFiles(staged)
+}
+
+// Stage adds the group at index i to the index. Hunks are matched against a
+// fresh diff, since earlier groups may already have committed other hunks of
+// the same file.
+func (p *Plan) Stage(repo *git.Repo, i int) error {
+	var whole []string
+	hunks := map[string]git.Selection{}
+	for _, id := range p.Groups[i].Units {
+		unit, _ := p.Unit(id)
+		if unit.HunkID == "" {
+			whole = append(whole, unit.Path)
+			if unit.OrigPath != "" {
+				whole = append(whole, unit.OrigPath)
+			}
+			continue
+		}
+		if hunks[unit.Path] == nil {
+			hunks[unit.Path] = git.Selection{}
+		}
+		hunks[unit.Path][unit.HunkID] = nil
+	}
+
+	if err := repo.StageFiles(whole); err != nil {
+		return err
+	}
+
+	for _, path := range slices.Sorted(maps.Keys(hunks)) {
+		selection := hunks[path]
+		diff, err := repo.GetFileDiff(path)
+		if err != nil {
+			return err
+		}
+		files, err := git.ParseDiff(string(diff))
+		if err != nil || len(files) != 1 {
+			return fmt.Errorf("failed to read changes to %s", path)
+		}
+
+		for id := range selection {
+			if !slices.ContainsFunc(files[0].Hunks, func(h git.Hunk) bool { return h.ID == id }) {
+				return fmt.Errorf("%s changed since the split was planned", path)
+			}
+		}
+		if err := repo.ApplyCached(files[0].Patch(selection)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func fileText(file git.FileDiff) string {
+	var parts []string
+	for _, hunk := range file.Hunks {
+		parts = append(parts, hunkText(hunk))
+	}
+	return strings.Join(parts, "\n")
+}
+
+func hunkText(hunk git.Hunk) string {
+	var b strings.Builder
+	b.WriteString(hunk.Header())
+	for _, line := range hunk.Lines {
+		b.WriteByte('\n')
+		b.WriteByte(line.Kind)
+		b.WriteString(line.Text)
+	}
+	return b.String()
+}
@@ -0,0 +1,159 @@
+package split
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/samcharles93/commiter/internal/git"
+	"github.com/samcharles93/commiter/internal/llm"
+)
+
+type stubCompleter struct {
+	reply    string
+	messages []llm.Message
+}
+
+func (s *stubCompleter) Complete(_ context.Context, messages []llm.Message) (string, error) {
+	s.messages = messages
+	return s.reply, nil
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s failed: %v\n%s", strings.Join(args, " "), err, string(out))
+	}
+	return string(out)
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+}
+
+func TestSplitCommitsHunksSeparately(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	runGit(t, dir, "init")
+	runGit(t, dir, "config", "user.name", "Test User")
+	runGit(t, dir, "config", "user.email", "test@example.com")
+
+	var lines []string
+	for i := 1; i <= 20; i++ {
+		lines = append(lines, "line")
+	}
+	writeFile(t, dir, "main.txt", strings.Join(lines, "\n")+"\n")
+	runGit(t, dir, "add", "main.txt")
+	runGit(t, dir, "commit", "-m", "initial")
+
+	lines[0] = "top"
+	lines[19] = "bottom"
+	writeFile(t, dir, "main.txt", strings.Join(lines, "\n")+"\n")
+	writeFile(t, dir, "new.txt", "new\n")
+	// Staged changes are part of the split too.
+	runGit(t, dir, "add", "main.txt")
+
+	repo, err := git.Open(dir, git.ExecRunner{})
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	plan, err := BuildPlan(repo)
+	if err != nil {
+		t.Fatalf("BuildPlan() error = %v", err)
+	}
+	if len(plan.Units) != 3 {
+		t.Fatalf("expected two hunks and one new file, got %+v", plan.Units)
+	}
+	if plan.Units[0].HunkID == "" || plan.Units[1].HunkID == "" || plan.Units[2].Path != "new.txt" {
+		t.Fatalf("unexpected units: %+v", plan.Units)
+	}
+
+	completer := &stubCompleter{reply: "```json\n" + `{"commits": [
+		{"message": "feat: change the top", "units": ["u1", "u3"]},
+		{"message": "fix: change the bottom", "units": ["u2", "u9", "u1"]}
+	]}` + "\n```"}
+	if err := Propose(context.Background(), completer, plan, nil, ""); err != nil {
+		t.Fatalf("Propose() error = %v", err)
+	}
+	if !strings.Contains(completer.messages[1].Content, "### u2: main.txt (hunk 2/2)") {
+		t.Fatalf("prompt does not list units: %s", completer.messages[1].Content)
+	}
+	if len(plan.Groups) != 2 || strings.Join(plan.Groups[1].Units, ",") != "u2" {
+		t.Fatalf("unexpected groups: %+v", plan.Groups)
+	}
+
+	if err := plan.Reset(repo); err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+	for i, group := range plan.Groups {
+		if err := plan.Stage(repo, i); err != nil {
+			t.Fatalf("Stage(%d) error = %v", i, err)
+		}
+		if err := repo.Commit(group.Message, git.CommitOptions{}); err != nil {
+			t.Fatalf("Commit(%d) error = %v", i, err)
+		}
+	}
+
+	if status := runGit(t, dir, "status", "--porcelain"); status != "" {
+		t.Fatalf("expected a clean tree, got %q", status)
+	}
+	first := runGit(t, dir, "show", "--format=", "HEAD~1")
+	if !strings.Contains(first, "+top") || strings.Contains(first, "+bottom") || !strings.Contains(first, "new.txt") {
+		t.Fatalf("first commit has the wrong changes:\n%s", first)
+	}
+	second := runGit(t, dir, "show", "--format=", "HEAD")
+	if !strings.Contains(second, "+bottom") || strings.Contains(second, "+top") {
+		t.Fatalf("second commit has the wrong changes:\n%s", second)
+	}
+}
+
+func TestMoveAndValidate(t *testing.T) {
+	plan := &Plan{}
+	plan.addUnit(Unit{Path: "a.go", Label: "modified"})
+	plan.addUnit(Unit{Path: "b.go", Label: "modified"})
+
+	groups, err := parseProposal(`{"commits": [{"message": "feat: a", "units": ["u1"]}]}`, plan)
+	if err != nil {
+		t.Fatalf("parseProposal() error = %v", err)
+	}
+	plan.Groups = groups
+	if len(plan.Groups) != 2 || plan.Groups[1].Units[0] != "u2" {
+		t.Fatalf("expected the unassigned unit in its own group, got %+v", plan.Groups)
+	}
+	if err := plan.Validate(); err == nil || !strings.Contains(err.Error(), "commit 2 has no message") {
+		t.Fatalf("expected a missing message error, got %v", err)
+	}
+
+	if err := plan.Move("u2", 0); err != nil {
+		t.Fatalf("Move() error = %v", err)
+	}
+	if len(plan.Groups) != 1 || len(plan.Groups[0].Units) != 2 {
+		t.Fatalf("expected the emptied group to be removed, got %+v", plan.Groups)
+	}
+	if err := plan.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	if err := plan.Move("u1", 1); err != nil {
+		t.Fatalf("Move() to a new group error = %v", err)
+	}
+	if len(plan.Groups) != 2 || plan.GroupOf("u1") != 1 {
+		t.Fatalf("expected u1 in a new group, got %+v", plan.Groups)
+	}
+	if err := plan.Move("u1", 5); err == nil {
+		t.Fatal("expected an error moving to a missing group")
+	}
+}
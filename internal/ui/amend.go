@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/samcharles93/commiter/internal/git"
+	"github.com/samcharles93/commiter/internal/llm"
+)
+
+// AmendHistory returns the follow-up prompt that seeds generation when
+// amending: the model sees the whole amended commit and its current message.
+func AmendHistory(last *git.CommitInfo) []llm.Message {
+	if last == nil {
+		return nil
+	}
+
+	var b strings.Builder
+	b.WriteString("This message replaces the message of the commit being amended.")
+	b.WriteString(" The diff is the whole amended commit, not only the newly staged changes.")
+	if current := lastCommitMessage(last); current != "" {
+		fmt.Fprintf(&b, " The commit's current message is:\n\n%s\n\nKeep its wording where it still fits and describe the whole commit.", current)
+	}
+	return []llm.Message{{Role: "user", Content: b.String()}}
+}
+
+func lastCommitMessage(last *git.CommitInfo) string {
+	return strings.TrimSpace(last.Subject + "\n\n" + strings.TrimSpace(last.Body))
+}
+
+// startAmend switches to amending HEAD and regenerates the message from the
+// combined diff. Nothing needs to be staged; amending only the message is fine.
+func (m *Model) startAmend() (tea.Cmd, error) {
+	lastCommit, err := m.repo.GetLastCommit()
+	if err != nil {
+		return nil, err
+	}
+	diff, err := m.repo.GetAmendDiff()
+	if err != nil {
+		return nil, err
+	}
+
+	m.lastCommit = lastCommit
+	m.amendDiff = string(diff)
+	m.isAmending = true
+	m.history = nil
+	m.state = StateGenerating
+	return tea.Batch(m.spinner.Tick, m.generateCommitMsg()), nil
+}
+
+// stopAmend goes back to a regular commit of the staged changes.
+func (m *Model) stopAmend() tea.Cmd {
+	m.isAmending = false
+	m.amendDiff = ""
+	m.history = nil
+	if m.diff == "" {
+		m.notice = "Nothing staged to commit"
+		m.state = StateFileSelection
+		return nil
+	}
+	m.state = StateGenerating
+	return tea.Batch(m.spinner.Tick, m.generateCommitMsg())
+}
+
+// promptDiff is the diff the message describes: the staged changes, or the
+// whole amended commit.
+func (m Model) promptDiff() string {
+	if m.isAmending {
+		return m.amendDiff
+	}
+	return m.diff
+}
+
+// rewordHistory seeds generation when rewording an earlier commit.
+func rewordHistory(commit *git.CommitInfo) []llm.Message {
+	content := "This message replaces the message of an earlier commit; the diff is that commit's change."
+	if current := lastCommitMessage(commit); current != "" {
+		content += fmt.Sprintf(" Its current message is:\n\n%s\n\nImprove it so it describes the change accurately.", current)
+	}
+	return []llm.Message{{Role: "user", Content: content}}
+}
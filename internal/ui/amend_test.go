@@ -0,0 +1,118 @@
+package ui
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/samcharles93/commiter/internal/config"
+	"github.com/samcharles93/commiter/internal/git"
+	"github.com/samcharles93/commiter/internal/llm"
+)
+
+// recordingProvider remembers the diff and history it was asked about.
+type recordingProvider struct {
+	diff    *string
+	history *[]llm.Message
+}
+
+func (p recordingProvider) GenerateMessage(_ context.Context, diff string, history []llm.Message, _ *config.CommitTemplate) (string, error) {
+	*p.diff = diff
+	*p.history = history
+	return "chore: init the tracked file", nil
+}
+
+func (recordingProvider) SummarizeChanges(context.Context, string) (string, error) {
+	return "summary", nil
+}
+
+func TestAmendRootCommitMessageOnly(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repoDir := initRepoForModelHookTests(t)
+	var diff string
+	var history []llm.Message
+	provider := recordingProvider{diff: &diff, history: &history}
+
+	m := NewModel(provider, nil, "", &config.Config{}, Options{Repo: git.NewRepo(repoDir, nil), Amend: true})
+	if m.state != StateGenerating || !m.isAmending {
+		t.Fatalf("expected amend generation to start, got state %q", m.state)
+	}
+
+	updated, _ := m.Update(m.generateCommitMsg()())
+	m = updated.(Model)
+	if !strings.Contains(diff, "+initial") {
+		t.Fatalf("expected the root commit's content in the prompt, got %q", diff)
+	}
+	if len(history) == 0 || !strings.Contains(history[0].Content, "chore: init") {
+		t.Fatalf("expected the current message in the prompt, got %+v", history)
+	}
+	if !strings.Contains(m.View(), "Current message:") {
+		t.Fatal("expected the review to show the current message")
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = updated.(Model)
+	if m.state != StateAmendConfirm {
+		t.Fatalf("expected amend confirmation, got %q", m.state)
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = updated.(Model)
+	if msg, ok := m.commitChanges()().(CommitSuccessMsg); !ok {
+		t.Fatalf("expected the amend to succeed, got %#v", msg)
+	}
+
+	if count := commitCountForModelHookTest(t, repoDir); count != 1 {
+		t.Fatalf("expected the root commit to be amended, got %d commits", count)
+	}
+	if subject := strings.TrimSpace(runGitForModelHookTest(t, repoDir, "log", "-1", "--pretty=%s")); subject != "chore: init the tracked file" {
+		t.Fatalf("unexpected subject %q", subject)
+	}
+}
+
+func TestAmendToggleUsesCombinedDiff(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repoDir := initRepoForModelHookTests(t)
+	writeFileForModelHookTest(t, filepath.Join(repoDir, "second.txt"), "second\n")
+	runGitForModelHookTest(t, repoDir, "add", "second.txt")
+	runGitForModelHookTest(t, repoDir, "commit", "-m", "feat: add second")
+	writeFileForModelHookTest(t, filepath.Join(repoDir, "second.txt"), "second\nmore\n")
+	runGitForModelHookTest(t, repoDir, "add", "second.txt")
+
+	var diff string
+	var history []llm.Message
+	repo := git.NewRepo(repoDir, nil)
+	staged, _ := repo.GetStagedDiff()
+	m := NewModel(recordingProvider{diff: &diff, history: &history}, nil, string(staged), &config.Config{}, Options{Repo: repo})
+	updated, _ := m.Update(m.generateCommitMsg()())
+	m = updated.(Model)
+	if strings.Contains(diff, "+second") {
+		t.Fatalf("expected only the staged delta before amending, got %q", diff)
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	m = updated.(Model)
+	if cmd == nil || !m.isAmending {
+		t.Fatal("expected a to start amending")
+	}
+	updated, _ = m.Update(m.generateCommitMsg()())
+	m = updated.(Model)
+	if !strings.Contains(diff, "+second") || !strings.Contains(diff, "+more") || strings.Contains(diff, "tracked.txt") {
+		t.Fatalf("expected HEAD's changes plus the staged delta, got %q", diff)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	m = updated.(Model)
+	if m.isAmending || m.state != StateGenerating {
+		t.Fatalf("expected a to switch back to a new commit, got state %q", m.state)
+	}
+}
@@ -0,0 +1,133 @@
+package ui
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/samcharles93/commiter/internal/branch"
+	"github.com/samcharles93/commiter/internal/llm"
+)
+
+type branchSuggestedMsg struct {
+	suggestion branch.Suggestion
+	err        error
+}
+
+// startBranch opens the branch prompt and asks the model for a name. The
+// prompt can be typed into while the suggestion loads. When the branch
+// pattern has a {ticket}, the ticket is asked for first, as with
+// "commiter branch --ticket".
+func (m *Model) startBranch() tea.Cmd {
+	completer, ok := m.provider.(llm.Completer)
+	if !ok || m.repo == nil {
+		m.notice = "Branch suggestions need a configured provider"
+		return nil
+	}
+	if m.reword != nil || m.operation.InProgress() {
+		m.notice = "Finish the current operation before switching branches"
+		return nil
+	}
+
+	input := textinput.New()
+	input.Placeholder = "Suggesting a branch name..."
+	input.CharLimit = 200
+	input.Width = 60
+	m.branchInput = input
+	m.branchSuggestion = nil
+	m.state = StateBranch
+
+	m.askingTicket = strings.Contains(m.cfg.GetBranchPattern(), "{ticket}")
+	if m.askingTicket {
+		ticket := textinput.New()
+		ticket.Placeholder = "e.g. ABC-123 (optional)"
+		ticket.CharLimit = 100
+		ticket.Width = 60
+		ticket.SetValue(m.branchTicket)
+		ticket.CursorEnd()
+		ticket.Focus()
+		m.ticketInput = ticket
+	} else {
+		m.branchInput.Focus()
+	}
+
+	diff, hint := m.promptDiff(), m.commitMsg
+	var types []string
+	if m.template != nil {
+		types = m.template.Types
+	}
+	memory := readMemory(m.repo)
+	return tea.Batch(textinput.Blink, func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		suggestion, err := branch.Suggest(ctx, completer, diff, types, hint, memory)
+		return branchSuggestedMsg{suggestion: suggestion, err: err}
+	})
+}
+
+// setBranchTicket takes the ticket from its prompt and moves on to the name.
+func (m *Model) setBranchTicket() {
+	m.branchTicket = strings.TrimSpace(m.ticketInput.Value())
+	m.askingTicket = false
+	m.ticketInput.Blur()
+	m.branchInput.Focus()
+	m.fillBranchName()
+}
+
+// fillBranchName puts the suggested name into an empty prompt once both the
+// suggestion and the ticket are known.
+func (m *Model) fillBranchName() {
+	if m.askingTicket || m.branchSuggestion == nil || m.branchInput.Value() != "" {
+		return
+	}
+	m.branchInput.SetValue(branch.Name(m.cfg.GetBranchPattern(), *m.branchSuggestion, m.branchTicket))
+	m.branchInput.CursorEnd()
+}
+
+// createBranch switches to a new branch named in the prompt, keeping the
+// staged changes, and returns to the review.
+func (m *Model) createBranch() {
+	name := strings.TrimSpace(m.branchInput.Value())
+	if name == "" {
+		m.notice = "Enter a branch name"
+		return
+	}
+	name, err := m.repo.CheckBranchName(name)
+	if err != nil {
+		m.notice = err.Error()
+		return
+	}
+	if err := m.repo.SwitchNewBranch(name); err != nil {
+		m.notice = err.Error()
+		return
+	}
+
+	m.branch = name
+	m.notice = ""
+	m.state = StateReview
+}
+
+func (m Model) renderBranch() string {
+	var b strings.Builder
+	b.WriteString(TitleStyle.Render("🌿 New Branch") + "\n")
+	b.WriteString(SubtleStyle.Render("Create a branch for these changes and switch to it before committing.") + "\n\n")
+	if m.askingTicket {
+		b.WriteString("Ticket for " + m.cfg.GetBranchPattern() + ":\n")
+		b.WriteString(m.ticketInput.View() + "\n\n")
+		if m.notice != "" {
+			b.WriteString(ErrorStyle.Render(m.notice) + "\n")
+		}
+		b.WriteString(HelpStyle.Render("enter: next • esc: cancel"))
+		return b.String()
+	}
+	b.WriteString(m.branchInput.View() + "\n\n")
+	if m.notice != "" {
+		b.WriteString(ErrorStyle.Render(m.notice) + "\n")
+	}
+	b.WriteString(HelpStyle.Render("enter: create and switch • esc: cancel"))
+	return b.String()
+}
@@ -0,0 +1,146 @@
+package ui
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/samcharles93/commiter/internal/config"
+	"github.com/samcharles93/commiter/internal/git"
+	"github.com/samcharles93/commiter/internal/llm"
+)
+
+// completingProvider is a stubProvider that can also answer free-form
+// prompts, as the real providers do.
+type completingProvider struct {
+	stubProvider
+	reply string
+}
+
+func (p completingProvider) Complete(context.Context, []llm.Message) (string, error) {
+	return p.reply, nil
+}
+
+func TestReviewCreatesBranchFromSuggestion(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Setenv("HOME", t.TempDir())
+
+	repoDir := initRepoForModelHookTests(t)
+	writeFileForModelHookTest(t, filepath.Join(repoDir, "tracked.txt"), "changed\n")
+	runGitForModelHookTest(t, repoDir, "add", "tracked.txt")
+
+	provider := completingProvider{reply: `{"type": "fix", "slug": "update tracked file"}`}
+	cfg := &config.Config{BranchPattern: "{type}/{slug}"}
+	m := NewModel(provider, nil, "diff --git a/tracked.txt b/tracked.txt", cfg, Options{Repo: git.NewRepo(repoDir, nil)})
+	m.state = StateReview
+	m.commitMsg = "fix: update tracked file"
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")})
+	m = updated.(Model)
+	if m.state != StateBranch || cmd == nil {
+		t.Fatalf("expected the branch prompt with a suggestion command, got state %q", m.state)
+	}
+
+	updated, _ = m.Update(branchSuggestionFromBatch(t, cmd))
+	m = updated.(Model)
+	if got := m.branchInput.Value(); got != "fix/update-tracked-file" {
+		t.Fatalf("expected the suggested name in the prompt, got %q", got)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.state != StateReview || m.branch != "fix/update-tracked-file" {
+		t.Fatalf("expected to return to review on the new branch, got state %q branch %q (notice %q)", m.state, m.branch, m.notice)
+	}
+	if head := strings.TrimSpace(runGitForModelHookTest(t, repoDir, "branch", "--show-current")); head != "fix/update-tracked-file" {
+		t.Fatalf("expected the repository to be on the new branch, got %q", head)
+	}
+	if staged := runGitForModelHookTest(t, repoDir, "diff", "--cached", "--name-only"); strings.TrimSpace(staged) != "tracked.txt" {
+		t.Fatalf("expected the staged change to carry over, got %q", staged)
+	}
+	if !strings.Contains(m.View(), "Branch: fix/update-tracked-file") {
+		t.Fatalf("expected the branch in the review view, got:\n%s", m.View())
+	}
+}
+
+func TestReviewBranchAsksForTicket(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Setenv("HOME", t.TempDir())
+
+	repoDir := initRepoForModelHookTests(t)
+	provider := completingProvider{reply: `{"type": "fix", "slug": "update tracked file"}`}
+	cfg := &config.Config{BranchPattern: "{type}/{ticket}-{slug}"}
+	m := NewModel(provider, nil, "diff --git a/tracked.txt b/tracked.txt", cfg, Options{Repo: git.NewRepo(repoDir, nil)})
+	m.state = StateReview
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")})
+	m = updated.(Model)
+	if !m.askingTicket || !strings.Contains(m.View(), "Ticket for {type}/{ticket}-{slug}") {
+		t.Fatalf("expected the ticket prompt first, got:\n%s", m.View())
+	}
+
+	// The suggestion waits for the ticket.
+	updated, _ = m.Update(branchSuggestionFromBatch(t, cmd))
+	m = updated.(Model)
+	if got := m.branchInput.Value(); got != "" {
+		t.Fatalf("expected no name before the ticket, got %q", got)
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("ABC-123")})
+	m = updated.(Model)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if got := m.branchInput.Value(); got != "fix/ABC-123-update-tracked-file" {
+		t.Fatalf("expected the ticket in the suggested name, got %q", got)
+	}
+
+	// The ticket is offered again for the next branch.
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")})
+	m = updated.(Model)
+	if !m.askingTicket || m.ticketInput.Value() != "ABC-123" {
+		t.Fatalf("expected the last ticket in the prompt, got %q", m.ticketInput.Value())
+	}
+}
+
+func TestReviewBranchNeedsCompleter(t *testing.T) {
+	m := NewModel(stubProvider{}, nil, "diff --git a/file b/file", &config.Config{}, Options{})
+	m.state = StateReview
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")})
+	m = updated.(Model)
+	if m.state != StateReview || cmd != nil || m.notice == "" {
+		t.Fatalf("expected a notice without a completer, got state %q notice %q", m.state, m.notice)
+	}
+}
+
+// branchSuggestionFromBatch runs the batched commands from startBranch and returns
+// the branch suggestion among their messages.
+func branchSuggestionFromBatch(t *testing.T, cmd tea.Cmd) tea.Msg {
+	t.Helper()
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok {
+		t.Fatal("expected a batch of commands")
+	}
+	for _, c := range batch {
+		if c == nil {
+			continue
+		}
+		if msg, ok := c().(branchSuggestedMsg); ok {
+			if msg.err != nil {
+				t.Fatalf("suggestion failed: %v", msg.err)
+			}
+			return msg
+		}
+	}
+	t.Fatal("no branch suggestion in the batch")
+	return nil
+}
@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/samcharles93/commiter/internal/clipboard"
+)
+
+// copyStatusDuration is how long the "copied" line stays on screen.
+const copyStatusDuration = 2 * time.Second
+
+type copiedMsg struct {
+	status string
+	err    error
+}
+
+type copyStatusExpiredMsg struct {
+	seq int
+}
+
+// copyToClipboard copies text in the background; what names it in the
+// status line, e.g. "hash".
+func copyToClipboard(what, text string) tea.Cmd {
+	return func() tea.Msg {
+		method, err := clipboard.Copy(text)
+		if err != nil {
+			return copiedMsg{err: err}
+		}
+		return copiedMsg{status: clipboard.Status(what, method)}
+	}
+}
+
+// copyStatus is the transient line shown after a copy. seq ties each expiry
+// to the copy that scheduled it, so a quick second copy is not cut short.
+type copyStatus struct {
+	text string
+	err  bool
+	seq  int
+}
+
+func (c *copyStatus) set(msg copiedMsg) tea.Cmd {
+	c.seq++
+	c.text, c.err = msg.status, msg.err != nil
+	if msg.err != nil {
+		c.text = msg.err.Error()
+	}
+	seq := c.seq
+	return tea.Tick(copyStatusDuration, func(time.Time) tea.Msg {
+		return copyStatusExpiredMsg{seq: seq}
+	})
+}
+
+func (c *copyStatus) expire(msg copyStatusExpiredMsg) {
+	if msg.seq == c.seq {
+		c.text = ""
+	}
+}
+
+func (c copyStatus) View() string {
+	switch {
+	case c.text == "":
+		return ""
+	case c.err:
+		return ErrorStyle.Render(c.text) + "\n"
+	default:
+		return SuccessStyle.Render(c.text) + "\n"
+	}
+}
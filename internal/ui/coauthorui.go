@@ -0,0 +1,149 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/samcharles93/commiter/internal/git"
+)
+
+const coAuthorTrailer = "Co-authored-by"
+
+type authorsLoadedMsg struct {
+	authors []git.Author
+	err     error
+}
+
+// authorItem implements list.Item for the co-author picker.
+type authorItem struct {
+	author   git.Author
+	selected bool
+}
+
+func (i authorItem) Title() string {
+	checkbox := "[ ] "
+	if i.selected {
+		checkbox = "[✓] "
+	}
+	return checkbox + i.author.String()
+}
+
+func (i authorItem) Description() string {
+	if i.author.PathCommits > 0 {
+		return fmt.Sprintf("%d commits on these files • %d recent commits", i.author.PathCommits, i.author.Commits)
+	}
+	return fmt.Sprintf("%d recent commits", i.author.Commits)
+}
+
+func (i authorItem) FilterValue() string { return i.author.String() }
+
+func newAuthorList() list.Model {
+	delegate := list.NewDefaultDelegate()
+	delegate.Styles.SelectedTitle = SelectedFileStyle
+	delegate.Styles.SelectedDesc = SubtleStyle
+
+	authorList := list.New([]list.Item{}, delegate, 0, 0)
+	authorList.Title = "Co-authors"
+	authorList.SetShowStatusBar(true)
+	authorList.SetFilteringEnabled(true)
+	authorList.Styles.Title = TitleStyle
+	return authorList
+}
+
+func (m Model) loadAuthors() tea.Cmd {
+	return func() tea.Msg {
+		var paths []string
+		if files, err := git.ParseDiff(m.diff); err == nil {
+			for _, file := range files {
+				paths = append(paths, file.Path())
+				if file.OldPath != "" && file.OldPath != file.Path() && file.OldPath != "/dev/null" {
+					paths = append(paths, file.OldPath)
+				}
+			}
+		}
+
+		authors, err := m.repo.RecentAuthors(paths)
+		return authorsLoadedMsg{authors: authors, err: err}
+	}
+}
+
+// setAuthors fills the picker, keeping co-authors picked earlier in the session at the top.
+func (m *Model) setAuthors(authors []git.Author) {
+	var items []list.Item
+	seen := map[string]bool{}
+	for _, coAuthor := range m.coAuthors {
+		for _, author := range authors {
+			if author.String() == coAuthor {
+				items = append(items, authorItem{author: author, selected: true})
+				seen[coAuthor] = true
+			}
+		}
+	}
+	for _, author := range authors {
+		if !seen[author.String()] {
+			items = append(items, authorItem{author: author})
+		}
+	}
+
+	m.authorList.ResetFilter()
+	m.authorList.SetItems(items)
+	m.authorList.Select(0)
+}
+
+// toggleAuthor flips the author under the cursor.
+func (m *Model) toggleAuthor() {
+	item, ok := m.authorList.SelectedItem().(authorItem)
+	if !ok {
+		return
+	}
+	item.selected = !item.selected
+
+	// The cursor index refers to the filtered view; find the item in the full list.
+	for i, listItem := range m.authorList.Items() {
+		if candidate, ok := listItem.(authorItem); ok && candidate.author.String() == item.author.String() {
+			m.authorList.SetItem(i, item)
+			return
+		}
+	}
+}
+
+// applyAuthors stores the picked authors for the rest of the session. Authors
+// picked earlier that are no longer in the list are kept.
+func (m *Model) applyAuthors() {
+	listed := map[string]bool{}
+	var picked []string
+	for _, listItem := range m.authorList.Items() {
+		item, ok := listItem.(authorItem)
+		if !ok {
+			continue
+		}
+		listed[item.author.String()] = true
+		if item.selected {
+			picked = append(picked, item.author.String())
+		}
+	}
+
+	var kept []string
+	for _, coAuthor := range m.coAuthors {
+		if !listed[coAuthor] {
+			kept = append(kept, coAuthor)
+		}
+	}
+	m.coAuthors = append(kept, picked...)
+}
+
+// commitOptions returns the options for the next commit, including co-author trailers.
+func (m Model) commitOptions() git.CommitOptions {
+	opts := m.commitOpts
+	if len(m.coAuthors) == 0 {
+		return opts
+	}
+
+	opts.Trailers = append([]string(nil), opts.Trailers...)
+	for _, coAuthor := range m.coAuthors {
+		opts.Trailers = append(opts.Trailers, coAuthorTrailer+": "+coAuthor)
+	}
+	return opts
+}
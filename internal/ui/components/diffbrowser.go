@@ -0,0 +1,190 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/samcharles93/commiter/internal/git"
+)
+
+var (
+	addedCountStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#04B575"))
+	deletedCountStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5F87"))
+)
+
+const (
+	minSidebarWidth = 24
+	maxSidebarWidth = 48
+)
+
+// DiffBrowser shows a diff one file at a time, with a sidebar listing every
+// file and its added and deleted line counts. ] and [ move between files;
+// other keys scroll the selected file's diff.
+type DiffBrowser struct {
+	files    []git.FileChange
+	whole    string
+	selected int
+	viewer   DiffViewer
+	width    int
+	height   int
+}
+
+// NewDiffBrowser creates an empty diff browser.
+func NewDiffBrowser() DiffBrowser {
+	return DiffBrowser{viewer: NewDiffViewer()}
+}
+
+// SetDiff splits diff into files and selects the first one. stats may be nil;
+// see git.SplitDiff.
+func (d *DiffBrowser) SetDiff(diff string, stats []git.FileStat) {
+	d.files = git.SplitDiff(diff, stats)
+	d.whole = diff
+	d.selected = 0
+	d.resize()
+	d.show()
+}
+
+// SetSize sets the size of the sidebar and diff together.
+func (d *DiffBrowser) SetSize(width, height int) {
+	d.width, d.height = width, height
+	d.resize()
+}
+
+// Selected returns the file whose diff is shown.
+func (d DiffBrowser) Selected() (git.FileChange, bool) {
+	if len(d.files) == 0 {
+		return git.FileChange{}, false
+	}
+	return d.files[d.selected], true
+}
+
+// Files returns the files of the diff.
+func (d DiffBrowser) Files() []git.FileChange {
+	return d.files
+}
+
+// Update moves between files and scrolls the diff.
+func (d DiffBrowser) Update(msg tea.Msg) (DiffBrowser, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "]":
+			d.Select(d.selected + 1)
+			return d, nil
+		case "[":
+			d.Select(d.selected - 1)
+			return d, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	d.viewer, cmd = d.viewer.Update(msg)
+	return d, cmd
+}
+
+// Select shows the file at index i, clamped to the list.
+func (d *DiffBrowser) Select(i int) {
+	if len(d.files) == 0 {
+		return
+	}
+	d.selected = max(0, min(i, len(d.files)-1))
+	d.show()
+}
+
+// View renders the sidebar next to the diff. A diff with no file headers,
+// such as a merge's combined diff, is shown whole without a sidebar.
+func (d DiffBrowser) View() string {
+	if len(d.files) == 0 {
+		return d.viewer.View()
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, d.renderSidebar(), " ", d.viewer.View())
+}
+
+func (d *DiffBrowser) show() {
+	if file, ok := d.Selected(); ok {
+		d.viewer.SetContent(file.Diff)
+		return
+	}
+	d.viewer.SetContent(d.whole)
+}
+
+func (d *DiffBrowser) resize() {
+	if d.width == 0 {
+		return
+	}
+	if len(d.files) == 0 {
+		d.viewer.SetSize(d.width, d.height)
+		return
+	}
+	d.viewer.SetSize(d.width-d.sidebarWidth()-1, d.height)
+}
+
+func (d DiffBrowser) sidebarWidth() int {
+	width := minSidebarWidth
+	for _, file := range d.files {
+		width = max(width, lipgloss.Width(file.Path)+len(counts(file.FileStat))+3)
+	}
+	return min(width, maxSidebarWidth, max(d.width/3, minSidebarWidth))
+}
+
+func (d DiffBrowser) renderSidebar() string {
+	width := d.sidebarWidth()
+	added, deleted := 0, 0
+	for _, file := range d.files {
+		added += file.Added
+		deleted += file.Deleted
+	}
+
+	var b strings.Builder
+	summary := fmt.Sprintf("%d file(s) ", len(d.files))
+	b.WriteString(descStyle.Render(summary) + addedCountStyle.Render(fmt.Sprintf("+%d", added)) + " " + deletedCountStyle.Render(fmt.Sprintf("-%d", deleted)) + "\n\n")
+
+	// Keep the selected file in view when the list is taller than the pane.
+	rows := max(d.height-2, 1)
+	first := 0
+	if d.selected >= rows {
+		first = d.selected - rows + 1
+	}
+	last := min(first+rows, len(d.files))
+
+	for i := first; i < last; i++ {
+		file := d.files[i]
+		stat := counts(file.FileStat)
+		path := truncateLeft(file.Path, width-len(stat)-3)
+		line := fmt.Sprintf("%-*s", width-len(stat)-3, path)
+
+		marker, style := "  ", normalTitleStyle
+		if i == d.selected {
+			marker, style = "▸ ", selectedTitleStyle
+		}
+		b.WriteString(checkboxStyle.Render(marker) + style.Render(line) + " " + renderCounts(file.FileStat))
+		if i < last-1 {
+			b.WriteString("\n")
+		}
+	}
+	return lipgloss.NewStyle().Width(width).Render(b.String())
+}
+
+func counts(stat git.FileStat) string {
+	if stat.Binary {
+		return "bin"
+	}
+	return fmt.Sprintf("+%d -%d", stat.Added, stat.Deleted)
+}
+
+func renderCounts(stat git.FileStat) string {
+	if stat.Binary {
+		return descStyle.Render("bin")
+	}
+	return addedCountStyle.Render(fmt.Sprintf("+%d", stat.Added)) + " " + deletedCountStyle.Render(fmt.Sprintf("-%d", stat.Deleted))
+}
+
+// truncateLeft shortens a path from the left, keeping the file name visible.
+func truncateLeft(path string, width int) string {
+	if width <= 1 || len(path) <= width {
+		return path
+	}
+	return "…" + path[len(path)-width+1:]
+}
@@ -0,0 +1,75 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/samcharles93/commiter/internal/git"
+)
+
+const twoFileDiff = "diff --git a/a.go b/a.go\n" +
+	"--- a/a.go\n" +
+	"+++ b/a.go\n" +
+	"@@ -1 +1,2 @@\n" +
+	"-old\n" +
+	"+new\n" +
+	"+more\n" +
+	"diff --git a/internal/b.go b/internal/b.go\n" +
+	"--- a/internal/b.go\n" +
+	"+++ b/internal/b.go\n" +
+	"@@ -1 +1 @@\n" +
+	"-before\n" +
+	"+after\n"
+
+func TestDiffBrowserNavigatesFiles(t *testing.T) {
+	browser := NewDiffBrowser()
+	browser.SetSize(120, 30)
+	browser.SetDiff(twoFileDiff, []git.FileStat{{Path: "a.go", Added: 2, Deleted: 1}, {Path: "internal/b.go", Added: 1, Deleted: 1}})
+
+	view := browser.View()
+	for _, want := range []string{"2 file(s)", "+3", "-2", "a.go", "internal/b.go", "more"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("expected %q in the view, got:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "after") {
+		t.Fatalf("expected only the first file's diff, got:\n%s", view)
+	}
+
+	browser, _ = browser.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("]")})
+	if file, _ := browser.Selected(); file.Path != "internal/b.go" || !strings.Contains(browser.View(), "after") {
+		t.Fatalf("expected ] to select the next file, got %q", file.Path)
+	}
+	browser, _ = browser.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("]")})
+	if file, _ := browser.Selected(); file.Path != "internal/b.go" {
+		t.Fatalf("expected the last file to stay selected, got %q", file.Path)
+	}
+	browser, _ = browser.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("[")})
+	if file, _ := browser.Selected(); file.Path != "a.go" {
+		t.Fatalf("expected [ to select the previous file, got %q", file.Path)
+	}
+}
+
+func TestDiffBrowserShowsCombinedDiffWhole(t *testing.T) {
+	browser := NewDiffBrowser()
+	browser.SetSize(100, 20)
+	browser.SetDiff("diff --cc merged.go\n@@@ -1,1 -1,1 +1,1 @@@\n++resolved\n", nil)
+
+	if _, ok := browser.Selected(); ok {
+		t.Fatal("expected no files for a combined diff")
+	}
+	if view := browser.View(); !strings.Contains(view, "resolved") || strings.Contains(view, "file(s)") {
+		t.Fatalf("expected the whole diff without a sidebar, got:\n%s", view)
+	}
+}
+
+func TestTruncateLeft(t *testing.T) {
+	if got := truncateLeft("internal/ui/components/diffbrowser.go", 16); got != "…/diffbrowser.go" {
+		t.Fatalf("truncateLeft() = %q", got)
+	}
+	if got := truncateLeft("a.go", 16); got != "a.go" {
+		t.Fatalf("truncateLeft() = %q", got)
+	}
+}
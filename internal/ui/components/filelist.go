@@ -24,8 +24,40 @@ var (
 
 	descStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#626262"))
+
+	sectionStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FFD700")).
+			Bold(true)
 )
 
+// FileItem is a changed file shown in either the staged or the unstaged section.
+type FileItem struct {
+	git.ChangedFile
+	Staged bool
+}
+
+// Description implements list.Item interface
+func (f FileItem) Description() string {
+	label := f.WorktreeLabel()
+	if f.Staged {
+		label = f.IndexLabel()
+	}
+	if f.OrigPath != "" {
+		return label + " from " + f.OrigPath
+	}
+	return label
+}
+
+// SectionItem is a heading that separates groups of files in the list.
+type SectionItem struct {
+	Name  string
+	Count int
+}
+
+func (s SectionItem) Title() string       { return s.Name }
+func (s SectionItem) Description() string { return "" }
+func (s SectionItem) FilterValue() string { return "" }
+
 // FileItemDelegate is a custom delegate for file items with checkbox support
 type FileItemDelegate struct{}
 
@@ -39,8 +71,19 @@ func (d FileItemDelegate) Spacing() int                            { return 0 }
 func (d FileItemDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
 
 func (d FileItemDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
-	file, ok := item.(git.ChangedFile)
-	if !ok {
+	var file git.ChangedFile
+	var desc string
+	switch item := item.(type) {
+	case SectionItem:
+		fmt.Fprint(w, sectionStyle.Render(fmt.Sprintf("%s (%d)", item.Name, item.Count)))
+		return
+	case FileItem:
+		file = item.ChangedFile
+		desc = item.Description()
+	case git.ChangedFile:
+		file = item
+		desc = item.Description()
+	default:
 		return
 	}
 
@@ -51,7 +94,6 @@ func (d FileItemDelegate) Render(w io.Writer, m list.Model, index int, item list
 	checkbox = checkboxStyle.Render(checkbox)
 
 	title := file.Path
-	desc := file.Status
 
 	titleStyle := normalTitleStyle
 	if index == m.Index() {
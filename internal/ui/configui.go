@@ -1,15 +1,19 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/samcharles93/commiter/internal/config"
+	"github.com/samcharles93/commiter/internal/llm"
 )
 
 // ConfigModel is the TUI model for configuration
@@ -24,6 +28,9 @@ type ConfigModel struct {
 	editingHookType  string
 	editingHookIndex int
 	errorReturnState string
+	modelList        list.Model
+	modelFetchErr    error
+	testResult       connectionTestMsg
 }
 
 const (
@@ -33,8 +40,40 @@ const (
 	configStateHookEdit = "hook-edit"
 	configStateSaved    = "saved"
 	configStateError    = "error"
+
+	configStateModelLoading = "model-loading"
+	configStateModelPicker  = "model-picker"
+	configStateTesting      = "testing"
+	configStateTestResult   = "test-result"
 )
 
+const configRequestTimeout = 15 * time.Second
+
+type modelsLoadedMsg struct {
+	models []string
+	err    error
+}
+
+type connectionTestMsg struct {
+	model   string
+	latency time.Duration
+	err     error
+}
+
+type modelItem struct {
+	id      string
+	current bool
+}
+
+func (i modelItem) Title() string {
+	if i.current {
+		return i.id + " (current)"
+	}
+	return i.id
+}
+func (i modelItem) Description() string { return "" }
+func (i modelItem) FilterValue() string { return i.id }
+
 type configMenuItem struct {
 	name        string
 	description string
@@ -88,6 +127,17 @@ func NewConfigModel(cfg *config.Config) ConfigModel {
 	hookList.SetFilteringEnabled(false)
 	hookList.Styles.Title = TitleStyle
 
+	modelDelegate := list.NewDefaultDelegate()
+	modelDelegate.ShowDescription = false
+	modelDelegate.SetSpacing(0)
+	modelDelegate.Styles.SelectedTitle = SelectedFileStyle
+
+	modelList := list.New([]list.Item{}, modelDelegate, 0, 0)
+	modelList.Title = "Models"
+	modelList.SetShowStatusBar(true)
+	modelList.SetFilteringEnabled(true)
+	modelList.Styles.Title = TitleStyle
+
 	ti := textinput.New()
 	ti.Placeholder = "Enter value..."
 	ti.CharLimit = 500
@@ -98,6 +148,7 @@ func NewConfigModel(cfg *config.Config) ConfigModel {
 		state:            configStateMenu,
 		list:             l,
 		hookList:         hookList,
+		modelList:        modelList,
 		textInput:        ti,
 		editingHookIndex: -1,
 	}
@@ -139,6 +190,16 @@ func (m ConfigModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						m.updateListItems()
 						return m, nil
 
+					case "Sign Commits":
+						m.config.SignCommits = !m.config.SignCommits
+						m.updateListItems()
+						return m, nil
+
+					case "Signoff":
+						m.config.Signoff = !m.config.Signoff
+						m.updateListItems()
+						return m, nil
+
 					case "Pre-Commit Hooks":
 						m.startHookList("pre")
 						return m, nil
@@ -147,6 +208,14 @@ func (m ConfigModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						m.startHookList("post")
 						return m, nil
 
+					case "Model":
+						m.state = configStateModelLoading
+						return m, m.fetchModels(false)
+
+					case "Test Connection":
+						m.state = configStateTesting
+						return m, m.testConnection()
+
 					default:
 						m.editingField = item.name
 						m.state = configStateEdit
@@ -259,10 +328,69 @@ func (m ConfigModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 
+		case configStateModelPicker:
+			// While the filter prompt is open, keys belong to the list.
+			if m.modelList.FilterState() == list.Filtering {
+				break
+			}
+			switch msg.String() {
+			case "enter":
+				if selected, ok := m.modelList.SelectedItem().(modelItem); ok {
+					m.config.Model = selected.id
+					m.updateListItems()
+					m.state = configStateMenu
+				}
+				return m, nil
+			case "e":
+				m.startModelEdit()
+				return m, textinput.Blink
+			case "r":
+				m.state = configStateModelLoading
+				return m, m.fetchModels(true)
+			case "q", "esc":
+				m.state = configStateMenu
+				return m, nil
+			}
+
+		case configStateModelLoading, configStateTesting:
+			if msg.String() == "esc" {
+				m.state = configStateMenu
+				return m, nil
+			}
+
+		case configStateTestResult:
+			m.state = configStateMenu
+			return m, nil
+
 		case configStateSaved:
 			return m, tea.Quit
 		}
 
+	case modelsLoadedMsg:
+		if m.state != configStateModelLoading {
+			return m, nil
+		}
+		if msg.err != nil || len(msg.models) == 0 {
+			m.modelFetchErr = msg.err
+			if msg.err == nil {
+				m.modelFetchErr = fmt.Errorf("provider returned no models")
+			}
+			m.startModelEdit()
+			return m, textinput.Blink
+		}
+		m.modelFetchErr = nil
+		m.setModelItems(msg.models)
+		m.state = configStateModelPicker
+		return m, nil
+
+	case connectionTestMsg:
+		if m.state != configStateTesting {
+			return m, nil
+		}
+		m.testResult = msg
+		m.state = configStateTestResult
+		return m, nil
+
 	case tea.WindowSizeMsg:
 		appH, appV := AppStyle.GetFrameSize()
 		h, v := BoxStyle.GetFrameSize()
@@ -276,6 +404,7 @@ func (m ConfigModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		m.list.SetSize(width, height)
 		m.hookList.SetSize(width, height)
+		m.modelList.SetSize(width, height)
 
 		inputWidth := width - 8
 		if inputWidth < 20 {
@@ -291,6 +420,8 @@ func (m ConfigModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.textInput, cmd = m.textInput.Update(msg)
 	case configStateHookList:
 		m.hookList, cmd = m.hookList.Update(msg)
+	case configStateModelPicker:
+		m.modelList, cmd = m.modelList.Update(msg)
 	}
 
 	return m, cmd
@@ -311,6 +442,14 @@ func (m ConfigModel) View() string {
 		content = m.renderSaved()
 	case configStateError:
 		content = m.renderError()
+	case configStateModelLoading:
+		content = m.renderModelLoading()
+	case configStateModelPicker:
+		content = m.renderModelPicker()
+	case configStateTesting:
+		content = m.renderTesting()
+	case configStateTestResult:
+		content = m.renderTestResult()
 	}
 	return AppStyle.Render(content)
 }
@@ -326,6 +465,14 @@ func (m ConfigModel) renderMenu() string {
 func (m ConfigModel) renderEdit() string {
 	var b strings.Builder
 	b.WriteString(TitleStyle.Render("✏️  Edit "+m.editingField) + "\n\n")
+	if m.editingField == "Model" && m.modelFetchErr != nil {
+		b.WriteString(ErrorStyle.Render("Could not load models: "+m.modelFetchErr.Error()) + "\n")
+		b.WriteString(SubtleStyle.Render("Enter the model name manually.") + "\n\n")
+	}
+	if m.editingField == "Language" {
+		b.WriteString(SubtleStyle.Render("Language code or name (e.g. en, de). Leave blank for English.") + "\n")
+		b.WriteString(SubtleStyle.Render("Repositories can override it with: git config commiter.language <code>") + "\n\n")
+	}
 	if m.editingField == "Default Template" {
 		b.WriteString(SubtleStyle.Render("Leave blank to prompt on startup.") + "\n")
 		b.WriteString(SubtleStyle.Render("Accepted values: "+formatTemplateOptions(m.config)) + "\n\n")
@@ -338,6 +485,49 @@ func (m ConfigModel) renderEdit() string {
 	return b.String()
 }
 
+func (m ConfigModel) renderModelLoading() string {
+	var b strings.Builder
+	b.WriteString(TitleStyle.Render("🔎 Models") + "\n\n")
+	b.WriteString(SubtleStyle.Render("Fetching models from "+m.modelsURL()+"...") + "\n\n")
+	b.WriteString(HelpStyle.Render("esc: cancel"))
+	return b.String()
+}
+
+func (m ConfigModel) renderModelPicker() string {
+	var b strings.Builder
+	b.WriteString(TitleStyle.Render("🔎 Select Model") + "\n\n")
+	b.WriteString(m.modelList.View() + "\n")
+	b.WriteString(HelpStyle.Render("↑↓: navigate • /: filter • enter: select • e: type manually • r: refresh • esc: back"))
+	return b.String()
+}
+
+func (m ConfigModel) renderTesting() string {
+	var b strings.Builder
+	b.WriteString(TitleStyle.Render("🔌 Test Connection") + "\n\n")
+	b.WriteString(SubtleStyle.Render("Sending a test prompt...") + "\n\n")
+	b.WriteString(HelpStyle.Render("esc: cancel"))
+	return b.String()
+}
+
+func (m ConfigModel) renderTestResult() string {
+	var b strings.Builder
+	b.WriteString(TitleStyle.Render("🔌 Test Connection") + "\n\n")
+	result := m.testResult
+	if result.err == nil {
+		b.WriteString(SuccessStyle.Render("✅ Connected") + "\n")
+		b.WriteString(BoxStyle.Render(fmt.Sprintf("Model: %s\nLatency: %s", result.model, result.latency.Round(time.Millisecond))) + "\n")
+	} else {
+		b.WriteString(ErrorStyle.Render("❌ Connection failed") + "\n")
+		message := result.err.Error()
+		if apiErr, ok := errors.AsType[*llm.APIError](result.err); ok && apiErr.IsAuthError() {
+			message = "Authentication failed, check the API key.\n\n" + message
+		}
+		b.WriteString(ErrorBoxStyle.Render(message) + "\n")
+	}
+	b.WriteString(HelpStyle.Render("press any key to return"))
+	return b.String()
+}
+
 func (m ConfigModel) renderHookList() string {
 	var b strings.Builder
 	b.WriteString(TitleStyle.Render("🪝 "+m.currentHookLabel()+" Hooks") + "\n\n")
@@ -390,10 +580,20 @@ func (m *ConfigModel) getFieldValue(field string) string {
 		return m.config.Model
 	case "Default Template":
 		return m.config.DefaultTemplate
+	case "Language":
+		return m.config.Language
 	case "Base URL":
 		return m.config.BaseURL
 	case "Hook Timeout (sec)":
 		return strconv.Itoa(m.config.GetHookTimeoutSeconds())
+	case "Lint Repair Attempts":
+		return strconv.Itoa(m.config.GetLintRepairAttempts())
+	case "Signing Key":
+		return m.config.SigningKey
+	case "PR Base Branch":
+		return m.config.PRBaseBranch
+	case "Branch Pattern":
+		return m.config.BranchPattern
 	default:
 		return ""
 	}
@@ -423,6 +623,9 @@ func (m *ConfigModel) setFieldValue(field, value string) error {
 		}
 		m.config.DefaultTemplate = tmpl.ConfigValue()
 		return nil
+	case "Language":
+		m.config.Language = value
+		return nil
 	case "Base URL":
 		m.config.BaseURL = value
 		return nil
@@ -433,11 +636,89 @@ func (m *ConfigModel) setFieldValue(field, value string) error {
 		}
 		m.config.HookTimeoutSeconds = seconds
 		return nil
+	case "Lint Repair Attempts":
+		attempts, err := strconv.Atoi(value)
+		if err != nil || attempts < 0 {
+			return fmt.Errorf("lint repair attempts must be zero or a positive integer")
+		}
+		m.config.LintRepairAttempts = &attempts
+		return nil
+	case "Signing Key":
+		m.config.SigningKey = value
+		return nil
+	case "PR Base Branch":
+		m.config.PRBaseBranch = value
+		return nil
+	case "Branch Pattern":
+		m.config.BranchPattern = value
+		return nil
 	default:
 		return fmt.Errorf("unknown field %q", field)
 	}
 }
 
+func (m ConfigModel) connection() (llm.Connection, error) {
+	return llm.ResolveConnection(m.config, "", "")
+}
+
+func (m ConfigModel) modelsURL() string {
+	conn, err := m.connection()
+	if err != nil {
+		return "provider"
+	}
+	return llm.ModelsURL(conn.BaseURL)
+}
+
+func (m ConfigModel) fetchModels(refresh bool) tea.Cmd {
+	conn, err := m.connection()
+	return func() tea.Msg {
+		if err != nil {
+			return modelsLoadedMsg{err: err}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), configRequestTimeout)
+		defer cancel()
+
+		models, err := conn.NewProvider().CachedModels(ctx, refresh)
+		return modelsLoadedMsg{models: models, err: err}
+	}
+}
+
+func (m ConfigModel) testConnection() tea.Cmd {
+	conn, err := m.connection()
+	return func() tea.Msg {
+		if err != nil {
+			return connectionTestMsg{err: err}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), configRequestTimeout)
+		defer cancel()
+
+		latency, err := conn.NewProvider().Ping(ctx)
+		return connectionTestMsg{model: conn.Model, latency: latency, err: err}
+	}
+}
+
+func (m *ConfigModel) setModelItems(models []string) {
+	items := make([]list.Item, len(models))
+	selected := 0
+	for i, id := range models {
+		current := id == m.config.Model
+		if current {
+			selected = i
+		}
+		items[i] = modelItem{id: id, current: current}
+	}
+	m.modelList.ResetFilter()
+	m.modelList.SetItems(items)
+	m.modelList.Select(selected)
+}
+
+func (m *ConfigModel) startModelEdit() {
+	m.editingField = "Model"
+	m.state = configStateEdit
+	m.textInput.SetValue(m.getFieldValue("Model"))
+	m.textInput.Focus()
+}
+
 func (m *ConfigModel) updateListItems() {
 	m.list.SetItems(configItems(m.config))
 }
@@ -501,13 +782,21 @@ func configItems(cfg *config.Config) []list.Item {
 	return []list.Item{
 		configMenuItem{"Provider", "LLM provider", cfg.Provider},
 		configMenuItem{"API Key", "API key for the provider", maskAPIKey(cfg.APIKey)},
-		configMenuItem{"Model", "Model name", cfg.Model},
+		configMenuItem{"Model", "Model name (picked from the provider's list)", cfg.Model},
 		configMenuItem{"Default Template", "Template used on startup", formatDefaultTemplateValue(cfg)},
+		configMenuItem{"Language", "Commit message language", formatLanguageValue(cfg.Language)},
 		configMenuItem{"Base URL", "API base URL", cfg.BaseURL},
 		configMenuItem{"Confirm Quit", "Ask before quitting", fmt.Sprintf("%t", cfg.GetConfirmQuit())},
+		configMenuItem{"Sign Commits", "GPG/SSH-sign new commits", fmt.Sprintf("%t", cfg.SignCommits)},
+		configMenuItem{"Signing Key", "Key ID for signing (empty uses git's user.signingkey)", cfg.SigningKey},
+		configMenuItem{"Signoff", "Add a Signed-off-by trailer", fmt.Sprintf("%t", cfg.Signoff)},
+		configMenuItem{"PR Base Branch", "Branch `commiter pr` compares against (empty uses the remote's default)", cfg.PRBaseBranch},
+		configMenuItem{"Branch Pattern", "Name pattern for `commiter branch`: {type}, {ticket}, {slug}", cfg.GetBranchPattern()},
 		configMenuItem{"Pre-Commit Hooks", "Commands to run before commit", formatHookSummary(cfg.PreCommitHooks)},
 		configMenuItem{"Post-Commit Hooks", "Commands to run after commit", formatHookSummary(cfg.PostCommitHooks)},
 		configMenuItem{"Hook Timeout (sec)", "Per-command timeout", strconv.Itoa(cfg.GetHookTimeoutSeconds())},
+		configMenuItem{"Lint Repair Attempts", "Corrective retries when a message breaks template rules", strconv.Itoa(cfg.GetLintRepairAttempts())},
+		configMenuItem{"Test Connection", "Send a tiny prompt and report latency", ""},
 		configMenuItem{"Save", "Save configuration", ""},
 	}
 }
@@ -526,6 +815,13 @@ func formatDefaultTemplateValue(cfg *config.Config) string {
 	return fmt.Sprintf("%s = %s", tmpl.ConfigValue(), tmpl.DisplayName())
 }
 
+func formatLanguageValue(lang string) string {
+	if strings.TrimSpace(lang) == "" {
+		return "(English)"
+	}
+	return fmt.Sprintf("%s = %s", lang, config.LanguageName(lang))
+}
+
 func formatTemplateOptions(cfg *config.Config) string {
 	if cfg == nil || len(cfg.Templates) == 0 {
 		return "default"
@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/samcharles93/commiter/internal/config"
+)
+
+func TestConfigModelPickerSelectsFetchedModel(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":[{"id":"deepseek-chat"},{"id":"deepseek-reasoner"}]}`))
+	}))
+	defer ts.Close()
+
+	cfg := &config.Config{Provider: "deepseek", APIKey: "key", BaseURL: ts.URL + "/v1/chat/completions"}
+	m := NewConfigModel(cfg)
+	selectConfigItem(t, &m, "Model")
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(ConfigModel)
+	if m.state != configStateModelLoading || cmd == nil {
+		t.Fatalf("expected model fetch to start, got state %q", m.state)
+	}
+
+	updated, _ = m.Update(cmd())
+	m = updated.(ConfigModel)
+	if m.state != configStateModelPicker {
+		t.Fatalf("expected picker state, got %q (err: %v)", m.state, m.modelFetchErr)
+	}
+
+	m.modelList.Select(1)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(ConfigModel)
+	if cfg.Model != "deepseek-reasoner" {
+		t.Fatalf("expected picked model to be stored, got %q", cfg.Model)
+	}
+	if m.state != configStateMenu {
+		t.Fatalf("expected return to menu, got %q", m.state)
+	}
+}
+
+func TestConfigModelFallsBackToManualEntryWhenFetchFails(t *testing.T) {
+	m := NewConfigModel(&config.Config{})
+	m.state = configStateModelLoading
+
+	updated, _ := m.Update(modelsLoadedMsg{err: errors.New("connection refused")})
+	m = updated.(ConfigModel)
+	if m.state != configStateEdit || m.editingField != "Model" {
+		t.Fatalf("expected manual model entry, got state %q field %q", m.state, m.editingField)
+	}
+}
+
+func selectConfigItem(t *testing.T, m *ConfigModel, name string) {
+	t.Helper()
+	for i, item := range m.list.Items() {
+		if item.(configMenuItem).name == name {
+			m.list.Select(i)
+			return
+		}
+	}
+	t.Fatalf("config item %q not found", name)
+}
@@ -0,0 +1,206 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/list"
+
+	"github.com/samcharles93/commiter/internal/git"
+	"github.com/samcharles93/commiter/internal/ui/components"
+)
+
+// fileKey identifies a file entry within its section; a partially staged file
+// has one entry in each.
+func fileKey(item components.FileItem) string {
+	if item.Staged {
+		return "staged:" + item.Path
+	}
+	return "changes:" + item.Path
+}
+
+// setFiles rebuilds the file list with "Staged" and "Changes" sections,
+// keeping the selection and cursor position where possible.
+func (m *Model) setFiles(files []git.ChangedFile) {
+	selected := map[string]bool{}
+	current := ""
+	for i, item := range m.fileList.Items() {
+		if file, ok := item.(components.FileItem); ok {
+			if file.Selected {
+				selected[fileKey(file)] = true
+			}
+			if i == m.fileList.Index() {
+				current = fileKey(file)
+			}
+		}
+	}
+
+	var staged, changes []list.Item
+	for _, f := range files {
+		if f.HasStagedChanges() {
+			item := components.FileItem{ChangedFile: f, Staged: true}
+			item.Selected = selected[fileKey(item)]
+			staged = append(staged, item)
+		}
+		if f.HasUnstagedChanges() {
+			item := components.FileItem{ChangedFile: f}
+			item.Selected = selected[fileKey(item)]
+			changes = append(changes, item)
+		}
+	}
+
+	var items []list.Item
+	if len(staged) > 0 {
+		items = append(items, components.SectionItem{Name: "Staged", Count: len(staged)})
+		items = append(items, staged...)
+	}
+	if len(changes) > 0 {
+		items = append(items, components.SectionItem{Name: "Changes", Count: len(changes)})
+		items = append(items, changes...)
+	}
+
+	m.files = files
+	m.fileList.SetItems(items)
+
+	cursor := 0
+	for i, item := range items {
+		if file, ok := item.(components.FileItem); ok && fileKey(file) == current {
+			cursor = i
+			break
+		}
+	}
+	m.fileList.Select(cursor)
+	m.skipSectionHeader(1)
+}
+
+// skipSectionHeader moves the cursor off a section heading in the given direction.
+func (m *Model) skipSectionHeader(direction int) {
+	items := m.fileList.Items()
+	idx := m.fileList.Index()
+	if idx < 0 || idx >= len(items) {
+		return
+	}
+	if _, ok := items[idx].(components.SectionItem); !ok {
+		return
+	}
+
+	if direction < 0 && idx > 0 {
+		m.fileList.Select(idx - 1)
+	} else if idx+1 < len(items) {
+		m.fileList.Select(idx + 1)
+	}
+}
+
+// currentFile returns the file entry under the cursor.
+func (m Model) currentFile() (components.FileItem, bool) {
+	file, ok := m.fileList.SelectedItem().(components.FileItem)
+	return file, ok
+}
+
+// targetFiles returns the selected entries, or the one under the cursor if none are selected.
+func (m Model) targetFiles() []components.FileItem {
+	var targets []components.FileItem
+	for _, item := range m.fileList.Items() {
+		if file, ok := item.(components.FileItem); ok && file.Selected {
+			targets = append(targets, file)
+		}
+	}
+	if len(targets) == 0 {
+		if file, ok := m.currentFile(); ok {
+			targets = append(targets, file)
+		}
+	}
+	return targets
+}
+
+// toggleCurrentFile flips the selection of the entry under the cursor.
+func (m *Model) toggleCurrentFile() {
+	file, ok := m.currentFile()
+	if !ok {
+		return
+	}
+	file.Selected = !file.Selected
+	m.fileList.SetItem(m.fileList.Index(), file)
+}
+
+// clearFileSelection unselects every entry.
+func (m *Model) clearFileSelection() {
+	for i, item := range m.fileList.Items() {
+		if file, ok := item.(components.FileItem); ok && file.Selected {
+			file.Selected = false
+			m.fileList.SetItem(i, file)
+		}
+	}
+}
+
+// toggleStaged stages unstaged entries and unstages staged ones.
+func (m *Model) toggleStaged(targets []components.FileItem) error {
+	var stage, unstage []string
+	for _, file := range targets {
+		if file.Staged {
+			unstage = append(unstage, file.Path)
+			if file.OrigPath != "" {
+				unstage = append(unstage, file.OrigPath)
+			}
+		} else {
+			stage = append(stage, file.Path)
+		}
+	}
+
+	if err := m.repo.StageFiles(stage); err != nil {
+		return err
+	}
+	if err := m.repo.UnstageFiles(unstage); err != nil {
+		return err
+	}
+
+	m.clearFileSelection()
+	return m.refreshFiles()
+}
+
+// refreshFiles reloads the changed files and the staged diff.
+func (m *Model) refreshFiles() error {
+	files, err := m.repo.ListChanges()
+	if err != nil {
+		return err
+	}
+	diff, err := m.repo.GetStagedDiff()
+	if err != nil {
+		return err
+	}
+
+	m.diff = string(diff)
+	m.setFiles(files)
+	return nil
+}
+
+// stagedSummary describes the staged diff, e.g. "Staged: 2 files, +10 -3".
+func (m Model) stagedSummary() string {
+	if m.diff == "" {
+		return "Nothing staged yet"
+	}
+
+	files, err := git.ParseDiff(m.diff)
+	if err != nil {
+		return "Staged changes ready"
+	}
+
+	added, removed := 0, 0
+	for _, file := range files {
+		for _, hunk := range file.Hunks {
+			for _, line := range hunk.Lines {
+				switch line.Kind {
+				case '+':
+					added++
+				case '-':
+					removed++
+				}
+			}
+		}
+	}
+
+	noun := "files"
+	if len(files) == 1 {
+		noun = "file"
+	}
+	return fmt.Sprintf("Staged: %d %s, +%d -%d", len(files), noun, added, removed)
+}
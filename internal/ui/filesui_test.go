@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/samcharles93/commiter/internal/config"
+	"github.com/samcharles93/commiter/internal/git"
+	"github.com/samcharles93/commiter/internal/ui/components"
+)
+
+func TestFileSelectionStagesAndUnstagesInBothDirections(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repoDir := initRepoForModelHookTests(t)
+	writeFileForModelHookTest(t, filepath.Join(repoDir, "tracked.txt"), "staged\n")
+	runGitForModelHookTest(t, repoDir, "add", "tracked.txt")
+	writeFileForModelHookTest(t, filepath.Join(repoDir, "new.txt"), "new\n")
+
+	repo := git.NewRepo(repoDir, nil)
+	files, err := repo.ListChanges()
+	if err != nil {
+		t.Fatalf("ListChanges returned error: %v", err)
+	}
+	diff, err := repo.GetStagedDiff()
+	if err != nil {
+		t.Fatalf("GetStagedDiff returned error: %v", err)
+	}
+
+	m := NewModel(stubProvider{}, files, string(diff), &config.Config{}, Options{Repo: repo})
+	if m.state != StateFileSelection {
+		t.Fatalf("expected file selection with staged changes present, got %q", m.state)
+	}
+	if got := sectionNames(m); got != "Staged,Changes" {
+		t.Fatalf("expected Staged and Changes sections, got %q", got)
+	}
+
+	// The cursor starts on the staged entry; move to the untracked file and stage it.
+	current, _ := m.currentFile()
+	if !current.Staged || current.Path != "tracked.txt" {
+		t.Fatalf("expected cursor on staged tracked.txt, got %+v", current)
+	}
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = updated.(Model)
+	if current, _ := m.currentFile(); current.Staged || current.Path != "new.txt" {
+		t.Fatalf("expected cursor to skip the section header onto new.txt, got %+v", current)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	m = updated.(Model)
+	if m.state != StateFileSelection {
+		t.Fatalf("expected to stay in file selection, got %q (%v)", m.state, m.err)
+	}
+	if got := sectionNames(m); got != "Staged" {
+		t.Fatalf("expected only the Staged section after staging, got %q", got)
+	}
+	if !strings.Contains(m.diff, "new.txt") {
+		t.Fatalf("expected staged diff to be refreshed, got:\n%s", m.diff)
+	}
+
+	// Unstage tracked.txt again.
+	selectFileForTest(t, &m, "tracked.txt", true)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	m = updated.(Model)
+	if strings.Contains(m.diff, "tracked.txt") {
+		t.Fatalf("expected tracked.txt to be unstaged, got:\n%s", m.diff)
+	}
+	if got := sectionNames(m); got != "Staged,Changes" {
+		t.Fatalf("expected both sections after unstaging, got %q", got)
+	}
+}
+
+func sectionNames(m Model) string {
+	var names []string
+	for _, item := range m.fileList.Items() {
+		if section, ok := item.(components.SectionItem); ok {
+			names = append(names, section.Name)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+func selectFileForTest(t *testing.T, m *Model, path string, staged bool) {
+	t.Helper()
+	for i, item := range m.fileList.Items() {
+		if file, ok := item.(components.FileItem); ok && file.Path == path && file.Staged == staged {
+			m.fileList.Select(i)
+			return
+		}
+	}
+	t.Fatalf("no entry for %s (staged=%t)", path, staged)
+}
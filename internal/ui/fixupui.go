@@ -0,0 +1,317 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/samcharles93/commiter/internal/git"
+	"github.com/samcharles93/commiter/internal/hooks"
+)
+
+const (
+	fixupStateList       = "list"
+	fixupStateCommitting = "committing"
+	fixupStateDone       = "done"
+	fixupStateError      = "error"
+)
+
+// FixupOptions carries per-run settings for the fixup model.
+type FixupOptions struct {
+	Commit        git.CommitOptions
+	PreHooks      []string
+	PostHooks     []string
+	HookTimeout   time.Duration
+	HooksDisabled bool
+	// Autosquash folds the fixups into their targets once they are created.
+	Autosquash bool
+}
+
+// PreCommit returns the git.CommitFixups callback that runs the configured
+// pre-commit hooks for each fixup once its hunks are staged, or nil when
+// hooks are disabled.
+func (o FixupOptions) PreCommit(repo *git.Repo) func(git.FixupTarget, string) error {
+	if o.HooksDisabled {
+		return nil
+	}
+	return func(_ git.FixupTarget, message string) error {
+		if err := hooks.Run(context.Background(), hooks.RunOptions{
+			Phase:         hooks.PhasePreCommit,
+			Dir:           repo.Dir(),
+			Commands:      o.PreHooks,
+			Timeout:       o.HookTimeout,
+			CommitMessage: message,
+		}); err != nil {
+			return fmt.Errorf("%s: pre-commit hook failed: %w", message, err)
+		}
+		return nil
+	}
+}
+
+// PostCommit returns the git.CommitFixups callback that runs the configured
+// post-commit hooks after each fixup is created, or nil when hooks are
+// disabled. A failing hook does not stop the fixups; it is passed to warn.
+func (o FixupOptions) PostCommit(repo *git.Repo, warn func(string)) func(git.FixupTarget, string) {
+	if o.HooksDisabled {
+		return nil
+	}
+	return func(_ git.FixupTarget, message string) {
+		if err := hooks.Run(context.Background(), hooks.RunOptions{
+			Phase:         hooks.PhasePostCommit,
+			Dir:           repo.Dir(),
+			Commands:      o.PostHooks,
+			Timeout:       o.HookTimeout,
+			CommitMessage: message,
+		}); err != nil {
+			warn(fmt.Sprintf("%s: post-commit hook failed: %v", message, err))
+		}
+	}
+}
+
+// FixupModel is the TUI model for turning staged hunks into fixup commits.
+type FixupModel struct {
+	repo     *git.Repo
+	plan     *git.FixupPlan
+	opts     FixupOptions
+	state    string
+	list     list.Model
+	spinner  spinner.Model
+	created  int
+	squashed bool
+	warning  string
+	notice   string
+	err      error
+}
+
+type fixupsCreatedMsg struct {
+	created  int
+	squashed bool
+	warning  string
+	err      error
+}
+
+// fixupItem implements list.Item for the fixup target picker.
+type fixupItem struct {
+	target   git.FixupTarget
+	selected bool
+}
+
+func (i fixupItem) Title() string {
+	checkbox := "[ ] "
+	if i.selected {
+		checkbox = "[✓] "
+	}
+	return fmt.Sprintf("%s%s %s", checkbox, i.target.Commit.Hash[:8], i.target.Commit.Subject)
+}
+
+func (i fixupItem) Description() string {
+	noun := "hunks"
+	if len(i.target.Hunks) == 1 {
+		noun = "hunk"
+	}
+	return fmt.Sprintf("%d %s in %s", len(i.target.Hunks), noun, strings.Join(i.target.Paths(), ", "))
+}
+
+func (i fixupItem) FilterValue() string { return i.target.Commit.Subject }
+
+// NewFixupModel creates the fixup picker with every target selected.
+func NewFixupModel(repo *git.Repo, plan *git.FixupPlan, opts FixupOptions) FixupModel {
+	items := make([]list.Item, len(plan.Targets))
+	for i, target := range plan.Targets {
+		items[i] = fixupItem{target: target, selected: true}
+	}
+
+	delegate := list.NewDefaultDelegate()
+	delegate.Styles.SelectedTitle = SelectedFileStyle
+	delegate.Styles.SelectedDesc = SubtleStyle
+
+	l := list.New(items, delegate, 0, 0)
+	l.Title = "Fixup targets"
+	l.SetShowStatusBar(false)
+	l.SetFilteringEnabled(false)
+	l.Styles.Title = TitleStyle
+
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = SelectedFileStyle
+
+	return FixupModel{
+		repo:    repo,
+		plan:    plan,
+		opts:    opts,
+		state:   fixupStateList,
+		list:    l,
+		spinner: s,
+	}
+}
+
+func (m FixupModel) Init() tea.Cmd {
+	return nil
+}
+
+// selectedTargets returns the checked targets, newest first.
+func (m FixupModel) selectedTargets() []git.FixupTarget {
+	var targets []git.FixupTarget
+	for _, item := range m.list.Items() {
+		if fixup, ok := item.(fixupItem); ok && fixup.selected {
+			targets = append(targets, fixup.target)
+		}
+	}
+	return targets
+}
+
+func (m FixupModel) createFixups(targets []git.FixupTarget) tea.Cmd {
+	return func() tea.Msg {
+		var warnings []string
+		warn := func(warning string) { warnings = append(warnings, warning) }
+		created, err := m.repo.CommitFixups(m.plan, targets, m.opts.Commit, m.opts.PreCommit(m.repo), m.opts.PostCommit(m.repo, warn))
+		warning := strings.Join(warnings, "\n⚠ ")
+		if err != nil {
+			return fixupsCreatedMsg{created: created, warning: warning, err: err}
+		}
+
+		if m.opts.Autosquash {
+			if err := m.repo.Autosquash(targets); err != nil {
+				return fixupsCreatedMsg{created: created, warning: warning, err: err}
+			}
+		}
+		return fixupsCreatedMsg{created: created, squashed: m.opts.Autosquash, warning: warning}
+	}
+}
+
+func (m FixupModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+
+		switch m.state {
+		case fixupStateList:
+			switch msg.String() {
+			case " ":
+				if item, ok := m.list.SelectedItem().(fixupItem); ok {
+					item.selected = !item.selected
+					m.list.SetItem(m.list.Index(), item)
+				}
+				return m, nil
+			case "a":
+				m.opts.Autosquash = !m.opts.Autosquash
+				return m, nil
+			case "enter":
+				targets := m.selectedTargets()
+				if len(targets) == 0 {
+					m.notice = "Select at least one commit to fix up"
+					return m, nil
+				}
+				m.notice = ""
+				m.state = fixupStateCommitting
+				return m, tea.Batch(m.spinner.Tick, m.createFixups(targets))
+			case "q", "esc":
+				return m, tea.Quit
+			}
+
+		case fixupStateDone, fixupStateError:
+			return m, tea.Quit
+		}
+
+	case fixupsCreatedMsg:
+		m.created = msg.created
+		m.squashed = msg.squashed
+		m.warning = msg.warning
+		if msg.err != nil {
+			m.err = msg.err
+			m.state = fixupStateError
+			return m, nil
+		}
+		m.state = fixupStateDone
+		return m, nil
+
+	case spinner.TickMsg:
+		if m.state == fixupStateCommitting {
+			m.spinner, cmd = m.spinner.Update(msg)
+			return m, cmd
+		}
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		appH, appV := AppStyle.GetFrameSize()
+		m.list.SetSize(msg.Width-appH, msg.Height-appV-8)
+	}
+
+	if m.state == fixupStateList {
+		m.list, cmd = m.list.Update(msg)
+	}
+	return m, cmd
+}
+
+func (m FixupModel) View() string {
+	var content string
+	switch m.state {
+	case fixupStateList:
+		content = m.renderList()
+	case fixupStateCommitting:
+		content = "\n" + m.spinner.View() + " Creating fixup commits...\n"
+	case fixupStateDone:
+		content = m.renderDone()
+	case fixupStateError:
+		content = m.renderError()
+	}
+	return AppStyle.Render(content)
+}
+
+func (m FixupModel) renderList() string {
+	var b strings.Builder
+	b.WriteString(m.list.View() + "\n")
+	if n := len(m.plan.Unmatched); n > 0 {
+		b.WriteString(SubtleStyle.Render(fmt.Sprintf("%d staged hunks match no unpushed commit and stay staged", n)) + "\n")
+	}
+
+	autosquash := "off"
+	if m.opts.Autosquash {
+		autosquash = "on"
+	}
+	b.WriteString(SubtleStyle.Render("Autosquash after committing: "+autosquash) + "\n")
+	if m.notice != "" {
+		b.WriteString(WarningStyle.Render(m.notice) + "\n")
+	}
+	b.WriteString(HelpStyle.Render("space: toggle • a: toggle autosquash • enter: create fixups • q: quit"))
+	return b.String()
+}
+
+func (m FixupModel) renderDone() string {
+	var b strings.Builder
+	b.WriteString("\n")
+	noun := "fixup commits"
+	if m.created == 1 {
+		noun = "fixup commit"
+	}
+	b.WriteString(SuccessStyle.Render(fmt.Sprintf("✓ Created %d %s", m.created, noun)) + "\n")
+	if m.squashed {
+		b.WriteString(SubtleStyle.Render("Squashed into their targets") + "\n")
+	}
+	if m.warning != "" {
+		b.WriteString("\n" + WarningStyle.Render("⚠ "+m.warning) + "\n")
+	}
+	b.WriteString(HelpStyle.Render("Press any key to exit"))
+	return b.String()
+}
+
+func (m FixupModel) renderError() string {
+	var b strings.Builder
+	b.WriteString("\n")
+	b.WriteString(ErrorStyle.Render("❌ Error") + "\n\n")
+	if m.created > 0 {
+		b.WriteString(SubtleStyle.Render(fmt.Sprintf("%d fixup commits were created before the failure", m.created)) + "\n")
+	}
+	b.WriteString(ErrorBoxStyle.Render(m.err.Error()) + "\n")
+	b.WriteString(HelpStyle.Render("Press any key to exit"))
+	return b.String()
+}
@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/samcharles93/commiter/internal/git"
+)
+
+func TestFixupModelTogglesTargetsAndAutosquash(t *testing.T) {
+	plan := &git.FixupPlan{Targets: []git.FixupTarget{
+		{Commit: git.CommitInfo{Hash: "0123456789abcdef0123456789abcdef01234567", Subject: "feat: add a"}, Hunks: []git.FixupHunk{{Path: "a.txt"}}},
+	}}
+	m := NewFixupModel(nil, plan, FixupOptions{})
+	if len(m.selectedTargets()) != 1 {
+		t.Fatal("expected targets to start selected")
+	}
+
+	press := func(key tea.KeyMsg) {
+		t.Helper()
+		updated, _ := m.Update(key)
+		m = updated.(FixupModel)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	if !m.opts.Autosquash {
+		t.Fatal("expected a to turn autosquash on")
+	}
+
+	press(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	if len(m.selectedTargets()) != 0 {
+		t.Fatal("expected space to unselect the target")
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.state != fixupStateList || m.notice == "" {
+		t.Fatalf("expected a notice when nothing is selected, got state %q", m.state)
+	}
+}
@@ -7,17 +7,22 @@ import (
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 
+	"github.com/samcharles93/commiter/internal/config"
 	"github.com/samcharles93/commiter/internal/git"
+	"github.com/samcharles93/commiter/internal/graph"
+	"github.com/samcharles93/commiter/internal/llm"
 	"github.com/samcharles93/commiter/internal/ui/components"
 )
 
 // HistoryModel is the TUI model for browsing commit history
 type HistoryModel struct {
+	repo         *git.Repo
 	state        string
 	commits      []git.CommitInfo
 	list         list.Model
-	diffViewer   components.DiffViewer
+	diffBrowser  components.DiffBrowser
 	markdown     components.MarkdownRenderer
 	selectedHash string
 	commitDetail *git.CommitInfo
@@ -26,25 +31,93 @@ type HistoryModel struct {
 	filterInput  textinput.Model
 	filtering    bool
 	err          error
+	opts         HistoryOptions
+	reword       *Model
+	notice       string
+	windowSize   tea.WindowSizeMsg
+	search       string
+	loading      bool
+	exhausted    bool
+	copying      bool
+	copied       copyStatus
+	reasonInput  textinput.Model
+	pickKind     git.OperationKind
+	pick         *git.Pick
+	pickReason   string
+	// pickConflicts are the paths still unmerged; pick.Conflicts keeps the
+	// original list for the message.
+	pickConflicts []string
+	pickFlow      *Model
+	// graph lays out each page as it loads; graphRows line up with commits.
+	graph     graph.Builder
+	graphRows []graph.Row
+	// pageErr is why the last page failed to load; no more pages are read.
+	pageErr error
+}
+
+// HistoryOptions carries the settings the history browser needs to reword commits.
+type HistoryOptions struct {
+	// Provider generates new messages; rewording is unavailable when it is nil.
+	Provider     llm.Provider
+	Config       *config.Config
+	ProviderName string
+	ModelName    string
+	Language     string
+	Commit       git.CommitOptions
+	// Filter is the git log filter the commits were loaded with.
+	Filter git.HistoryFilter
+	// PageSize is how many commits to load each time the end of the list is
+	// reached; zero shows only the commits passed in.
+	PageSize int
+	// Graph draws the commit graph beside the list. The commits must be in
+	// topological order, with parents, as Filter.Graph loads them.
+	Graph bool
+}
+
+type historyPageMsg struct {
+	commits []git.CommitInfo
+	err     error
 }
 
 const (
 	historyStateList   = "list"
 	historyStateDetail = "detail"
 	historyStateFilter = "filter"
+	historyStateReword = "reword"
 	historyStateError  = "error"
+	// historyStatePickReason asks why a commit is reverted or cherry-picked,
+	// historyStatePick waits on conflicts, and historyStatePickCommit writes
+	// the message.
+	historyStatePickReason = "pick-reason"
+	historyStatePick       = "pick"
+	historyStatePickCommit = "pick-commit"
 )
 
 type commitItem struct {
 	git.CommitInfo
+	// node and edge are the commit's graph lines, padded to the same width
+	// for every commit; they are empty when no graph is drawn.
+	node, edge string
 }
 
 func (c commitItem) Title() string {
-	return fmt.Sprintf("%s - %s", c.Hash[:8], c.Subject)
+	title := c.Hash[:8]
+	if len(c.Refs) > 0 {
+		title += " (" + strings.Join(c.Refs, ", ") + ")"
+	}
+	title += " - " + c.Subject
+	if c.node != "" {
+		return c.node + " " + title
+	}
+	return title
 }
 
 func (c commitItem) Description() string {
-	return fmt.Sprintf("%s by %s", c.Date, c.Author)
+	desc := fmt.Sprintf("%s by %s", c.Date, c.Author)
+	if c.node != "" {
+		return c.edge + " " + desc
+	}
+	return desc
 }
 
 func (c commitItem) FilterValue() string {
@@ -52,17 +125,19 @@ func (c commitItem) FilterValue() string {
 }
 
 // NewHistoryModel creates a new history browser model
-func NewHistoryModel(commits []git.CommitInfo) HistoryModel {
-	items := make([]list.Item, len(commits))
-	for i, c := range commits {
-		items[i] = commitItem{c}
-	}
-
+func NewHistoryModel(repo *git.Repo, commits []git.CommitInfo, opts HistoryOptions) HistoryModel {
 	delegate := list.NewDefaultDelegate()
 	delegate.Styles.SelectedTitle = SelectedFileStyle
 	delegate.Styles.SelectedDesc = SubtleStyle
+	if opts.Graph {
+		// The graph's lines run on from one commit to the next, so there is
+		// no gap between items, and the selected one keeps their indent.
+		delegate.SetSpacing(0)
+		delegate.Styles.SelectedTitle = SelectedFileStyle.PaddingLeft(2)
+		delegate.Styles.SelectedDesc = SubtleStyle.PaddingLeft(2)
+	}
 
-	l := list.New(items, delegate, 0, 0)
+	l := list.New(nil, delegate, 0, 0)
 	l.Title = "Commit History"
 	l.SetShowStatusBar(false)
 	l.SetFilteringEnabled(true)
@@ -73,14 +148,20 @@ func NewHistoryModel(commits []git.CommitInfo) HistoryModel {
 	filterInput.CharLimit = 100
 	filterInput.Width = 60
 
-	return HistoryModel{
+	m := HistoryModel{
+		repo:        repo,
 		state:       historyStateList,
 		commits:     commits,
 		list:        l,
-		diffViewer:  components.NewDiffViewer(),
+		diffBrowser: components.NewDiffBrowser(),
 		markdown:    components.NewMarkdownRenderer(),
 		filterInput: filterInput,
+		opts:        opts,
+		exhausted:   opts.PageSize <= 0 || len(commits) < opts.PageSize,
 	}
+	m.addGraphRows(commits)
+	m.filterCommits("")
+	return m
 }
 
 func (m HistoryModel) Init() tea.Cmd {
@@ -90,11 +171,19 @@ func (m HistoryModel) Init() tea.Cmd {
 func (m HistoryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
+	if m.state == historyStateReword {
+		return m.updateReword(msg)
+	}
+	if m.state == historyStatePickCommit {
+		return m.updatePickFlow(msg)
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		if msg.String() == "ctrl+c" {
 			return m, tea.Quit
 		}
+		m.notice = ""
 
 		switch m.state {
 		case historyStateList:
@@ -107,7 +196,7 @@ func (m HistoryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.selectedHash = commit.Hash
 
 					// Fetch commit details
-					detail, diff, err := git.GetCommitDetails(commit.Hash)
+					detail, diff, err := m.repo.GetCommitDetails(commit.Hash)
 					if err != nil {
 						m.state = historyStateError
 						m.err = err
@@ -116,7 +205,9 @@ func (m HistoryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 					m.commitDetail = detail
 					m.commitDiff = diff
-					m.diffViewer.SetContent(diff)
+					// Counts are read from the diff itself if numstat fails.
+					stats, _ := m.repo.CommitNumstat(commit.Hash)
+					m.diffBrowser.SetDiff(diff, stats)
 					m.showDiff = true
 					m.state = historyStateDetail
 					return m, nil
@@ -132,15 +223,24 @@ func (m HistoryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 		case historyStateDetail:
+			if m.copying {
+				m.copying = false
+				return m, m.copyDetail(msg.String())
+			}
 			switch msg.String() {
 			case "d":
 				// Toggle diff view
 				m.showDiff = !m.showDiff
 				return m, nil
+			case "r":
+				return m.startReword()
+			case "v":
+				return m.startPickReason(git.OperationRevert)
+			case "p":
+				return m.startPickReason(git.OperationCherryPick)
 			case "y":
-				// Copy hash to clipboard (would need clipboard library)
-				// For now, just show a message
-				// TODO: Implement clipboard copy
+				// Wait for what to copy
+				m.copying = true
 				return m, nil
 			case "q", "esc":
 				m.state = historyStateList
@@ -150,11 +250,8 @@ func (m HistoryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case historyStateFilter:
 			switch msg.String() {
 			case "enter":
-				// Apply filter
-				filter := strings.TrimSpace(m.filterInput.Value())
-				if filter != "" {
-					m.filterCommits(filter)
-				}
+				// Apply filter; an empty search shows every loaded commit again
+				m.filterCommits(strings.TrimSpace(m.filterInput.Value()))
 				m.filtering = false
 				m.filterInput.Blur()
 				m.state = historyStateList
@@ -166,17 +263,61 @@ func (m HistoryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 
+		case historyStatePickReason:
+			switch msg.String() {
+			case "enter":
+				return m.applyPick()
+			case "esc":
+				m.state = historyStateDetail
+				return m, nil
+			}
+
+		case historyStatePick:
+			switch msg.String() {
+			case "enter":
+				return m.continuePick()
+			case "a":
+				return m.abortPick()
+			case "q", "esc":
+				m.notice = fmt.Sprintf("The %s of %s is still staged; commit it with commiter or undo it with git reset --merge", m.pick.Kind, m.pick.Hash[:8])
+				m.pick = nil
+				m.state = historyStateList
+				return m, nil
+			}
+			return m, nil
+
 		case historyStateError:
 			return m, tea.Quit
 		}
 
+	case copiedMsg:
+		return m, m.copied.set(msg)
+
+	case copyStatusExpiredMsg:
+		m.copied.expire(msg)
+		return m, nil
+
+	case historyPageMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.pageErr = msg.err
+			m.exhausted = true
+			return m, nil
+		}
+		m.exhausted = len(msg.commits) < m.opts.PageSize
+		m.commits = append(m.commits, msg.commits...)
+		m.addGraphRows(msg.commits)
+		m.filterCommits(m.search)
+		return m, nil
+
 	case tea.WindowSizeMsg:
+		m.windowSize = msg
 		appH, appV := AppStyle.GetFrameSize()
 		h, v := BoxStyle.GetFrameSize()
 		contentWidth := msg.Width - appH - h
 		contentHeight := msg.Height - appV
 		m.list.SetSize(contentWidth, contentHeight-v-5)
-		m.diffViewer.SetSize(msg.Width-appH, contentHeight-10)
+		m.diffBrowser.SetSize(msg.Width-appH, contentHeight-10)
 		m.markdown.SetWidth(contentWidth - 4)
 	}
 
@@ -184,12 +325,17 @@ func (m HistoryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch m.state {
 	case historyStateList:
 		m.list, cmd = m.list.Update(msg)
+		if next := m.loadMore(); next != nil {
+			cmd = tea.Batch(cmd, next)
+		}
 	case historyStateDetail:
 		if m.showDiff {
-			m.diffViewer, cmd = m.diffViewer.Update(msg)
+			m.diffBrowser, cmd = m.diffBrowser.Update(msg)
 		}
 	case historyStateFilter:
 		m.filterInput, cmd = m.filterInput.Update(msg)
+	case historyStatePickReason:
+		m.reasonInput, cmd = m.reasonInput.Update(msg)
 	}
 
 	return m, cmd
@@ -204,6 +350,14 @@ func (m HistoryModel) View() string {
 		content = m.renderDetail()
 	case historyStateFilter:
 		content = m.renderFilter()
+	case historyStateReword:
+		return m.reword.View()
+	case historyStatePickReason:
+		content = m.renderPickReason()
+	case historyStatePick:
+		content = m.renderPick()
+	case historyStatePickCommit:
+		return m.pickFlow.View()
 	case historyStateError:
 		content = m.renderError()
 	}
@@ -212,8 +366,24 @@ func (m HistoryModel) View() string {
 
 func (m HistoryModel) renderList() string {
 	var b strings.Builder
-	b.WriteString(TitleStyle.Render("📜 Commit History") + "\n\n")
+	b.WriteString(TitleStyle.Render("📜 Commit History") + "\n")
+	if filter := m.opts.Filter.String(); filter != "" {
+		b.WriteString(SubtleStyle.Render("Filters: "+filter) + "\n")
+	}
+	if m.search != "" {
+		b.WriteString(SubtleStyle.Render(fmt.Sprintf("Search: %q (%d of %d loaded commits)", m.search, len(m.list.Items()), len(m.commits))) + "\n")
+	}
+	b.WriteString("\n")
 	b.WriteString(m.list.View() + "\n")
+	if m.loading {
+		b.WriteString(SubtleStyle.Render("Loading more commits...") + "\n")
+	}
+	if m.pageErr != nil {
+		b.WriteString(ErrorStyle.Render("Could not load more commits: "+m.pageErr.Error()) + "\n")
+	}
+	if m.notice != "" {
+		b.WriteString(SuccessStyle.Render(m.notice) + "\n")
+	}
 	b.WriteString(HelpStyle.Render("↑↓: navigate • enter/space: view details • /: filter • q: quit"))
 	return b.String()
 }
@@ -235,18 +405,52 @@ func (m HistoryModel) renderDetail() string {
 
 	if m.showDiff {
 		b.WriteString(SubtleStyle.Render("Diff:") + "\n")
-		b.WriteString(m.diffViewer.View() + "\n")
+		b.WriteString(m.diffBrowser.View() + "\n")
 	}
 
 	diffToggle := "[d] show diff"
 	if m.showDiff {
 		diffToggle = "[d] hide diff"
+		if len(m.diffBrowser.Files()) > 1 {
+			diffToggle += " • ]/[ next/prev file"
+		}
 	}
 
-	b.WriteString(HelpStyle.Render(diffToggle + " • [y] copy hash • q/esc: back"))
+	if m.notice != "" {
+		b.WriteString(WarningStyle.Render(m.notice) + "\n")
+	}
+	b.WriteString(m.copied.View())
+	if m.copying {
+		b.WriteString(HelpStyle.Render("copy: [y/h] hash • [s] subject • [m] message • [d] diff • esc: cancel"))
+		return b.String()
+	}
+	b.WriteString(HelpStyle.Render(diffToggle + " • [r] reword • [v] revert • [p] cherry-pick • [y] copy... • q/esc: back"))
 	return b.String()
 }
 
+// copyDetail copies part of the commit shown in the detail view; key is the
+// key pressed after "y".
+func (m HistoryModel) copyDetail(key string) tea.Cmd {
+	if m.commitDetail == nil {
+		return nil
+	}
+	switch key {
+	case "y", "h":
+		return copyToClipboard("hash", m.commitDetail.Hash)
+	case "s":
+		return copyToClipboard("subject", m.commitDetail.Subject)
+	case "m":
+		message := m.commitDetail.Subject
+		if body := strings.TrimSpace(m.commitDetail.Body); body != "" {
+			message += "\n\n" + body
+		}
+		return copyToClipboard("message", message)
+	case "d":
+		return copyToClipboard("diff", m.commitDiff)
+	}
+	return nil
+}
+
 func (m HistoryModel) renderFilter() string {
 	var b strings.Builder
 	b.WriteString(TitleStyle.Render("🔍 Filter Commits") + "\n\n")
@@ -264,15 +468,57 @@ func (m HistoryModel) renderError() string {
 	return b.String()
 }
 
+// loadMore fetches the next page of history once the cursor reaches the last
+// loaded commit.
+func (m *HistoryModel) loadMore() tea.Cmd {
+	if m.loading || m.exhausted || len(m.list.Items()) == 0 || m.list.Index() < len(m.list.Items())-1 {
+		return nil
+	}
+	m.loading = true
+
+	repo, filter, skip, limit := m.repo, m.opts.Filter, len(m.commits), m.opts.PageSize
+	return func() tea.Msg {
+		commits, err := repo.CommitHistory(filter, skip, limit)
+		return historyPageMsg{commits: commits, err: err}
+	}
+}
+
+// addGraphRows lays out newly loaded commits, continuing the graph of the
+// commits before them.
+func (m *HistoryModel) addGraphRows(commits []git.CommitInfo) {
+	if !m.opts.Graph {
+		return
+	}
+	for _, commit := range commits {
+		m.graphRows = append(m.graphRows, m.graph.Add(commit.Hash, commit.Parents))
+	}
+}
+
+// filterCommits lists the loaded commits matching filter. The graph is only
+// drawn for the full list, as its lines would not join up across a search.
 func (m *HistoryModel) filterCommits(filter string) {
+	m.search = filter
 	filter = strings.ToLower(filter)
 	var filtered []list.Item
 
-	for _, commit := range m.commits {
+	showGraph := filter == "" && len(m.graphRows) == len(m.commits)
+	width := 0
+	if showGraph {
+		for _, row := range m.graphRows {
+			width = max(width, lipgloss.Width(row.Node), lipgloss.Width(row.Edge))
+		}
+	}
+
+	for i, commit := range m.commits {
 		if strings.Contains(strings.ToLower(commit.Subject), filter) ||
 			strings.Contains(strings.ToLower(commit.Author), filter) ||
 			strings.Contains(strings.ToLower(commit.Hash), filter) {
-			filtered = append(filtered, commitItem{commit})
+			item := commitItem{CommitInfo: commit}
+			if showGraph && width > 0 {
+				item.node = fmt.Sprintf("%-*s", width, m.graphRows[i].Node)
+				item.edge = fmt.Sprintf("%-*s", width, m.graphRows[i].Edge)
+			}
+			filtered = append(filtered, item)
 		}
 	}
 
@@ -1,6 +1,9 @@
 package ui
 
 import (
+	"errors"
+	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -10,7 +13,7 @@ import (
 )
 
 func TestHistoryDetailMarkdownRenderAndResize(t *testing.T) {
-	m := NewHistoryModel([]git.CommitInfo{
+	m := NewHistoryModel(git.NewRepo("", nil), []git.CommitInfo{
 		{
 			Hash:    "0123456789abcdef",
 			Author:  "Test User",
@@ -18,7 +21,7 @@ func TestHistoryDetailMarkdownRenderAndResize(t *testing.T) {
 			Subject: "feat: summary",
 			Body:    "- item one\n- item two",
 		},
-	})
+	}, HistoryOptions{})
 
 	m.state = historyStateDetail
 	m.commitDetail = &git.CommitInfo{
@@ -42,3 +45,206 @@ func TestHistoryDetailMarkdownRenderAndResize(t *testing.T) {
 		t.Fatalf("expected commit detail header in view, got:\n%s", view)
 	}
 }
+
+func TestHistoryLoadsNextPageAtEndOfList(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repoDir := initRepoForModelHookTests(t)
+	for _, subject := range []string{"feat: one", "feat: two", "feat: three"} {
+		runGitForModelHookTest(t, repoDir, "commit", "--allow-empty", "-m", subject)
+	}
+	repo := git.NewRepo(repoDir, nil)
+	filter := git.HistoryFilter{Grep: "^feat"}
+	commits, err := repo.CommitHistory(filter, 0, 2)
+	if err != nil {
+		t.Fatalf("CommitHistory() error = %v", err)
+	}
+
+	m := NewHistoryModel(repo, commits, HistoryOptions{Filter: filter, PageSize: 2})
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	m = updated.(HistoryModel)
+	if !strings.Contains(m.View(), "Filters: message: ^feat") {
+		t.Fatalf("expected the active filters in the header, got:\n%s", m.View())
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = updated.(HistoryModel)
+	if !m.loading || cmd == nil {
+		t.Fatal("expected the next page to load at the end of the list")
+	}
+	updated, _ = m.Update(historyPageMsgFromBatch(t, cmd))
+	m = updated.(HistoryModel)
+	if len(m.commits) != 3 || len(m.list.Items()) != 3 || !m.exhausted {
+		t.Fatalf("expected all 3 matching commits and no more pages, got %d commits, exhausted %v", len(m.commits), m.exhausted)
+	}
+
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if updated.(HistoryModel).loading {
+		t.Fatalf("expected no further loading once the history is exhausted (cmd %v)", cmd)
+	}
+}
+
+func TestHistoryShowsPageLoadErrorAsError(t *testing.T) {
+	m := NewHistoryModel(git.NewRepo("", nil), []git.CommitInfo{
+		{Hash: "0123456789abcdef", Subject: "feat: summary"},
+	}, HistoryOptions{PageSize: 1})
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	updated, _ = updated.Update(historyPageMsg{err: errors.New("bad revision")})
+	m = updated.(HistoryModel)
+
+	if m.notice != "" || !m.exhausted {
+		t.Fatalf("expected the error apart from notices and no more pages, got notice %q, exhausted %v", m.notice, m.exhausted)
+	}
+	want := ErrorStyle.Render("Could not load more commits: bad revision")
+	if view := m.View(); !strings.Contains(view, want) {
+		t.Fatalf("expected the load error in the list, got:\n%s", view)
+	}
+}
+
+func TestHistoryDrawsGraphUnlessSearching(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repoDir := initRepoForModelHookTests(t)
+	runGitForModelHookTest(t, repoDir, "commit", "--allow-empty", "-m", "chore: base")
+	runGitForModelHookTest(t, repoDir, "checkout", "-b", "feature")
+	runGitForModelHookTest(t, repoDir, "commit", "--allow-empty", "-m", "feat: side")
+	runGitForModelHookTest(t, repoDir, "checkout", "-")
+	runGitForModelHookTest(t, repoDir, "commit", "--allow-empty", "-m", "fix: main")
+	runGitForModelHookTest(t, repoDir, "merge", "--no-ff", "-m", "merge feature", "feature")
+	repo := git.NewRepo(repoDir, nil)
+	filter := git.HistoryFilter{Graph: true}
+	commits, err := repo.CommitHistory(filter, 0, 3)
+	if err != nil {
+		t.Fatalf("CommitHistory() error = %v", err)
+	}
+
+	m := NewHistoryModel(repo, commits, HistoryOptions{Filter: filter, PageSize: 3, Graph: true})
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	m = updated.(HistoryModel)
+	view := m.View()
+	for _, want := range []string{commits[0].Hash[:8] + " (HEAD -> ", "├─╮", "│ ● " + commits[1].Hash[:8] + " (feature)", "├─╯"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("expected %q in the graph, got:\n%s", want, view)
+		}
+	}
+
+	// The next page continues the lanes of the first.
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnd})
+	m = updated.(HistoryModel)
+	if cmd == nil {
+		t.Fatal("expected the next page to load at the end of the list")
+	}
+	updated, _ = m.Update(historyPageMsgFromBatch(t, cmd))
+	m = updated.(HistoryModel)
+	if len(m.commits) <= 3 || len(m.graphRows) != len(m.commits) || !strings.Contains(m.View(), "chore: base") {
+		t.Fatalf("expected the graph to continue onto the next page, got:\n%s", m.View())
+	}
+
+	m.filterCommits("side")
+	if item := m.list.Items()[0].(commitItem); item.node != "" || strings.Contains(item.Title(), "●") {
+		t.Fatalf("expected no graph while searching, got %q", item.Title())
+	}
+}
+
+// historyPageMsgFromBatch runs the commands returned by a list update and
+// returns the loaded page among their messages.
+func historyPageMsgFromBatch(t *testing.T, cmd tea.Cmd) tea.Msg {
+	t.Helper()
+	msg := cmd()
+	if page, ok := msg.(historyPageMsg); ok {
+		return page
+	}
+	batch, ok := msg.(tea.BatchMsg)
+	if !ok {
+		t.Fatalf("expected a page message, got %T", msg)
+	}
+	for _, c := range batch {
+		if c == nil {
+			continue
+		}
+		if page, ok := c().(historyPageMsg); ok {
+			if page.err != nil {
+				t.Fatalf("loading page failed: %v", page.err)
+			}
+			return page
+		}
+	}
+	t.Fatal("no history page in the batch")
+	return nil
+}
+
+func TestHistoryDetailCopiesAndClearsStatus(t *testing.T) {
+	commit := git.CommitInfo{Hash: "0123456789abcdef", Author: "Test User", Date: "today", Subject: "feat: summary", Body: "Details."}
+	m := NewHistoryModel(git.NewRepo("", nil), []git.CommitInfo{commit}, HistoryOptions{})
+	m.state = historyStateDetail
+	m.commitDetail = &commit
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = updated.(HistoryModel)
+	if !m.copying || !strings.Contains(m.View(), "[m] message") {
+		t.Fatalf("expected the copy choices after y, got:\n%s", m.View())
+	}
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+	m = updated.(HistoryModel)
+	if m.copying || cmd == nil {
+		t.Fatal("expected a copy command for the message")
+	}
+
+	updated, expire := m.Update(copiedMsg{status: "✓ Copied message"})
+	m = updated.(HistoryModel)
+	if expire == nil || !strings.Contains(m.View(), "✓ Copied message") {
+		t.Fatalf("expected a transient copied line, got:\n%s", m.View())
+	}
+
+	// An expiry for an earlier copy leaves the newer status alone.
+	updated, _ = m.Update(copyStatusExpiredMsg{seq: m.copied.seq - 1})
+	m = updated.(HistoryModel)
+	if m.copied.text == "" {
+		t.Fatal("expected a stale expiry to be ignored")
+	}
+	updated, _ = m.Update(copyStatusExpiredMsg{seq: m.copied.seq})
+	if strings.Contains(updated.(HistoryModel).View(), "Copied") {
+		t.Fatal("expected the copied line to clear")
+	}
+}
+
+func TestHistoryDetailBrowsesFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repoDir := initRepoForModelHookTests(t)
+	writeFileForModelHookTest(t, filepath.Join(repoDir, "tracked.txt"), "initial\nchanged\n")
+	writeFileForModelHookTest(t, filepath.Join(repoDir, "zeta.txt"), "new\n")
+	runGitForModelHookTest(t, repoDir, "add", ".")
+	runGitForModelHookTest(t, repoDir, "commit", "-m", "feat: two files")
+
+	repo := git.NewRepo(repoDir, nil)
+	commits, err := repo.GetCommitHistory(1)
+	if err != nil {
+		t.Fatalf("GetCommitHistory() error = %v", err)
+	}
+	m := NewHistoryModel(repo, commits, HistoryOptions{})
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 140, Height: 50})
+	updated, _ = updated.(HistoryModel).Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(HistoryModel)
+	if m.state != historyStateDetail {
+		t.Fatalf("expected the detail view, got state %q", m.state)
+	}
+	if view := m.View(); !strings.Contains(view, "2 file(s)") || !strings.Contains(view, "zeta.txt") {
+		t.Fatalf("expected the file sidebar, got:\n%s", view)
+	}
+	if file, _ := m.diffBrowser.Selected(); file.Path != "tracked.txt" || file.Added != 1 {
+		t.Fatalf("expected tracked.txt (+1) first, got %+v", file.FileStat)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("]")})
+	m = updated.(HistoryModel)
+	if file, _ := m.diffBrowser.Selected(); file.Path != "zeta.txt" {
+		t.Fatalf("expected ] to show zeta.txt, got %q", file.Path)
+	}
+}
@@ -0,0 +1,291 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/samcharles93/commiter/internal/git"
+)
+
+var (
+	hunkAddedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#04B575"))
+	hunkRemovedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5F87"))
+)
+
+// hunkRow is one selectable row of the hunk picker. line is -1 for the hunk header.
+type hunkRow struct {
+	hunk int
+	line int
+}
+
+// hunkPicker lets the user choose hunks, or individual lines of a hunk, of one file.
+type hunkPicker struct {
+	file     git.FileDiff
+	selected map[string]map[int]bool
+	expanded map[string]bool
+	rows     []hunkRow
+	cursor   int
+	offset   int
+	height   int
+}
+
+func newHunkPicker(file git.FileDiff) hunkPicker {
+	p := hunkPicker{
+		file:     file,
+		selected: map[string]map[int]bool{},
+		expanded: map[string]bool{},
+		height:   20,
+	}
+	for _, hunk := range file.Hunks {
+		p.selected[hunk.ID] = map[int]bool{}
+	}
+	p.buildRows()
+	return p
+}
+
+func (p *hunkPicker) buildRows() {
+	p.rows = p.rows[:0]
+	for i, hunk := range p.file.Hunks {
+		p.rows = append(p.rows, hunkRow{hunk: i, line: -1})
+		if !p.expanded[hunk.ID] {
+			continue
+		}
+		for j, line := range hunk.Lines {
+			if line.IsChange() {
+				p.rows = append(p.rows, hunkRow{hunk: i, line: j})
+			}
+		}
+	}
+	if p.cursor >= len(p.rows) {
+		p.cursor = len(p.rows) - 1
+	}
+	if p.cursor < 0 {
+		p.cursor = 0
+	}
+}
+
+func (p *hunkPicker) setHeight(height int) {
+	if height < 3 {
+		height = 3
+	}
+	p.height = height
+	p.scroll()
+}
+
+func (p *hunkPicker) move(delta int) {
+	p.cursor += delta
+	if p.cursor < 0 {
+		p.cursor = 0
+	}
+	if p.cursor >= len(p.rows) {
+		p.cursor = len(p.rows) - 1
+	}
+	p.scroll()
+}
+
+func (p *hunkPicker) scroll() {
+	if p.cursor < p.offset {
+		p.offset = p.cursor
+	}
+	for p.offset < p.cursor && p.rowsHeight(p.offset, p.cursor+1) > p.height {
+		p.offset++
+	}
+}
+
+// rowsHeight returns the number of terminal lines rows [from, to) render to.
+func (p hunkPicker) rowsHeight(from, to int) int {
+	height := 0
+	for i := from; i < to; i++ {
+		height++
+		row := p.rows[i]
+		if hunk := p.file.Hunks[row.hunk]; row.line < 0 && !p.expanded[hunk.ID] {
+			height += strings.Count(p.previewLines(hunk), "\n")
+		}
+	}
+	return height
+}
+
+// toggle flips the row under the cursor. On a hunk header it selects the whole
+// hunk, or clears it if it was already fully selected.
+func (p *hunkPicker) toggle() {
+	if len(p.rows) == 0 {
+		return
+	}
+	row := p.rows[p.cursor]
+	hunk := p.file.Hunks[row.hunk]
+	lines := p.selected[hunk.ID]
+
+	if row.line >= 0 {
+		lines[row.line] = !lines[row.line]
+		return
+	}
+
+	selectAll := p.hunkState(row.hunk) != hunkSelected
+	for j, line := range hunk.Lines {
+		if line.IsChange() {
+			lines[j] = selectAll
+		}
+	}
+}
+
+// toggleAll selects every hunk, or clears the selection if everything is selected.
+func (p *hunkPicker) toggleAll() {
+	selectAll := false
+	for i := range p.file.Hunks {
+		if p.hunkState(i) != hunkSelected {
+			selectAll = true
+			break
+		}
+	}
+	for _, hunk := range p.file.Hunks {
+		for j, line := range hunk.Lines {
+			if line.IsChange() {
+				p.selected[hunk.ID][j] = selectAll
+			}
+		}
+	}
+}
+
+// split expands or collapses the lines of the hunk under the cursor.
+func (p *hunkPicker) split() {
+	if len(p.rows) == 0 {
+		return
+	}
+	row := p.rows[p.cursor]
+	id := p.file.Hunks[row.hunk].ID
+	p.expanded[id] = !p.expanded[id]
+	if !p.expanded[id] {
+		// Move back to the header so the cursor does not land on another hunk.
+		for i, r := range p.rows {
+			if r.hunk == row.hunk && r.line < 0 {
+				p.cursor = i
+				break
+			}
+		}
+	}
+	p.buildRows()
+	p.scroll()
+}
+
+const (
+	hunkUnselected = iota
+	hunkPartial
+	hunkSelected
+)
+
+func (p hunkPicker) hunkState(index int) int {
+	hunk := p.file.Hunks[index]
+	total, chosen := 0, 0
+	for j, line := range hunk.Lines {
+		if !line.IsChange() {
+			continue
+		}
+		total++
+		if p.selected[hunk.ID][j] {
+			chosen++
+		}
+	}
+	switch {
+	case chosen == 0:
+		return hunkUnselected
+	case chosen == total:
+		return hunkSelected
+	default:
+		return hunkPartial
+	}
+}
+
+// selection converts the picker state into a git.Selection.
+func (p hunkPicker) selection() git.Selection {
+	selection := git.Selection{}
+	for i, hunk := range p.file.Hunks {
+		switch p.hunkState(i) {
+		case hunkSelected:
+			selection[hunk.ID] = nil
+		case hunkPartial:
+			lines := map[int]bool{}
+			for j, chosen := range p.selected[hunk.ID] {
+				if chosen {
+					lines[j] = true
+				}
+			}
+			selection[hunk.ID] = lines
+		}
+	}
+	return selection
+}
+
+func (p hunkPicker) view() string {
+	var b strings.Builder
+	for i := p.offset; i < len(p.rows) && (i == p.offset || p.rowsHeight(p.offset, i+1) <= p.height); i++ {
+		row := p.rows[i]
+		hunk := p.file.Hunks[row.hunk]
+
+		cursor := "  "
+		if i == p.cursor {
+			cursor = SelectedFileStyle.Render("> ")
+		}
+
+		if row.line < 0 {
+			checkbox := "[ ]"
+			switch p.hunkState(row.hunk) {
+			case hunkSelected:
+				checkbox = "[✓]"
+			case hunkPartial:
+				checkbox = "[~]"
+			}
+			title := hunk.Header()
+			if i == p.cursor {
+				title = SelectedFileStyle.Render(title)
+			}
+			b.WriteString(fmt.Sprintf("%s%s %s %s\n", cursor, checkbox, title,
+				SubtleStyle.Render(fmt.Sprintf("(%d changed lines)", hunk.ChangeCount()))))
+			if !p.expanded[hunk.ID] {
+				b.WriteString(p.previewLines(hunk))
+			}
+			continue
+		}
+
+		line := hunk.Lines[row.line]
+		checkbox := "[ ]"
+		if p.selected[hunk.ID][row.line] {
+			checkbox = "[✓]"
+		}
+		text := string(line.Kind) + line.Text
+		if line.Kind == '+' {
+			text = hunkAddedStyle.Render(text)
+		} else {
+			text = hunkRemovedStyle.Render(text)
+		}
+		b.WriteString(fmt.Sprintf("%s    %s %s\n", cursor, checkbox, text))
+	}
+	return b.String()
+}
+
+// previewLines shows the first changed lines of a collapsed hunk.
+func (p hunkPicker) previewLines(hunk git.Hunk) string {
+	const previewLimit = 3
+
+	var b strings.Builder
+	shown := 0
+	for _, line := range hunk.Lines {
+		if !line.IsChange() {
+			continue
+		}
+		if shown == previewLimit {
+			b.WriteString(SubtleStyle.Render("        …") + "\n")
+			break
+		}
+		text := string(line.Kind) + line.Text
+		if line.Kind == '+' {
+			text = hunkAddedStyle.Render(text)
+		} else {
+			text = hunkRemovedStyle.Render(text)
+		}
+		b.WriteString("        " + text + "\n")
+		shown++
+	}
+	return b.String()
+}
@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/samcharles93/commiter/internal/git"
+)
+
+func testFileDiff(t *testing.T) git.FileDiff {
+	t.Helper()
+
+	files, err := git.ParseDiff(`diff --git a/a.txt b/a.txt
+--- a/a.txt
++++ b/a.txt
+@@ -1,2 +1,2 @@
+-one
++ONE
+ two
+@@ -8,2 +8,3 @@
+ eight
++eight and a half
++eight and three quarters
+ nine
+`)
+	if err != nil {
+		t.Fatalf("ParseDiff returned error: %v", err)
+	}
+	return files[0]
+}
+
+func TestHunkPickerSelectsHunksAndLines(t *testing.T) {
+	file := testFileDiff(t)
+	p := newHunkPicker(file)
+
+	p.toggle()
+	p.move(1)
+	p.split()
+	if len(p.rows) != 4 {
+		t.Fatalf("expected 2 hunk rows and 2 line rows, got %d", len(p.rows))
+	}
+	p.move(1)
+	p.toggle()
+
+	selection := p.selection()
+	if lines, ok := selection[file.Hunks[0].ID]; !ok || lines != nil {
+		t.Fatalf("expected first hunk to be selected whole, got %v", selection)
+	}
+	lines := selection[file.Hunks[1].ID]
+	if len(lines) != 1 || !lines[1] {
+		t.Fatalf("expected only the first added line of the second hunk, got %v", lines)
+	}
+	if p.hunkState(1) != hunkPartial {
+		t.Fatalf("expected second hunk to be partially selected")
+	}
+
+	p.toggleAll()
+	if p.hunkState(0) != hunkSelected || p.hunkState(1) != hunkSelected {
+		t.Fatalf("expected toggleAll to select everything")
+	}
+	p.toggleAll()
+	if len(p.selection()) != 0 {
+		t.Fatalf("expected toggleAll to clear a full selection, got %v", p.selection())
+	}
+}
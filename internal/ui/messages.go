@@ -1,11 +1,15 @@
 package ui
 
-import "github.com/samcharles93/commiter/internal/git"
+import (
+	"github.com/samcharles93/commiter/internal/git"
+	"github.com/samcharles93/commiter/internal/lint"
+)
 
 // Tea Messages for async operations
 
 type GenerateMsg struct {
 	Message string
+	Issues  []lint.Issue
 	Err     error
 }
 
@@ -19,6 +23,7 @@ type CommitSuccessMsg struct {
 	RemainingDiff       string
 	RemainingFiles      []git.ChangedFile
 	HookWarning         string
+	Operation           git.Operation
 }
 
 type CommitErrorMsg struct {
@@ -9,11 +9,14 @@ import (
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/samcharles93/commiter/internal/branch"
 	"github.com/samcharles93/commiter/internal/config"
 	"github.com/samcharles93/commiter/internal/git"
 	"github.com/samcharles93/commiter/internal/hooks"
+	"github.com/samcharles93/commiter/internal/lint"
 	"github.com/samcharles93/commiter/internal/llm"
 	"github.com/samcharles93/commiter/internal/ui/components"
 )
@@ -23,15 +26,19 @@ const successExitDelay = 1200 * time.Millisecond
 // Model is the main TUI model
 type Model struct {
 	cfg           *config.Config
+	repo          *git.Repo
 	state         string
 	provider      llm.Provider
 	diff          string
 	commitMsg     string
+	lintIssues    []lint.Issue
 	summary       string
 	history       []llm.Message
 	err           error
 	spinner       spinner.Model
 	fileList      list.Model
+	notice        string
+	hunks         hunkPicker
 	textarea      textarea.Model
 	templateList  list.Model
 	files         []git.ChangedFile
@@ -42,8 +49,11 @@ type Model struct {
 	modelName     string
 	confirmQuit   bool
 	isAmending    bool
+	amendDiff     string
+	reword        *git.CommitInfo
+	canAmend      bool
 	previousState string
-	diffViewer    components.DiffViewer
+	diffBrowser   components.DiffBrowser
 	markdown      components.MarkdownRenderer
 	lastCommit    *git.CommitInfo
 	hookWarning   string
@@ -51,13 +61,58 @@ type Model struct {
 	postHooks     []string
 	hookTimeout   time.Duration
 	hooksDisabled bool
+	language      string
+	commitOpts    git.CommitOptions
+	authorList    list.Model
+	coAuthors     []string
+	operation     git.Operation
+	branchInput   textinput.Model
+	branch        string
+	copied        copyStatus
+	pick          *git.Pick
+	pickReason    string
+	// The branch prompt first asks for the {ticket} of the branch pattern
+	// when it has one; the ticket is kept for the next branch.
+	ticketInput      textinput.Model
+	askingTicket     bool
+	branchTicket     string
+	branchSuggestion *branch.Suggestion
+}
+
+// Options carries per-run settings for the interactive model.
+type Options struct {
+	// Repo is the repository to operate on; nil means the current directory.
+	Repo          *git.Repo
+	ProviderName  string
+	ModelName     string
+	HooksDisabled bool
+	// Language overrides the configured commit language (CLI flag or repository setting).
+	Language string
+	// Commit holds the signing, signoff and author options; signing and signoff can be toggled on the review screen.
+	Commit git.CommitOptions
+	// Operation is the merge, rebase, cherry-pick, revert or am in progress, if any.
+	Operation git.Operation
+	// Amend starts by regenerating HEAD's message from the whole amended commit.
+	Amend bool
+	// Reword replaces the message of this earlier commit instead of committing;
+	// the diff passed to NewModel should be the commit's own diff.
+	Reword *git.CommitInfo
+	// Pick is the revert or cherry-pick being committed; the diff passed to
+	// NewModel should be the staged result.
+	Pick *git.Pick
+	// PickReason says why the commit is reverted or picked, for the message.
+	PickReason string
 }
 
 // NewModel creates a new TUI model
-func NewModel(provider llm.Provider, files []git.ChangedFile, diff string, providerName, modelName string, cfg *config.Config, hooksDisabled bool) Model {
+func NewModel(provider llm.Provider, files []git.ChangedFile, diff string, cfg *config.Config, opts Options) Model {
 	if cfg == nil {
 		cfg = &config.Config{}
 	}
+	repo := opts.Repo
+	if repo == nil {
+		repo = git.NewRepo("", nil)
+	}
 
 	s := spinner.New()
 	s.Spinner = spinner.Dot
@@ -65,7 +120,7 @@ func NewModel(provider llm.Provider, files []git.ChangedFile, diff string, provi
 
 	delegate := components.NewFileItemDelegate()
 	fileList := list.New([]list.Item{}, delegate, 0, 0)
-	fileList.Title = "Select Files to Stage"
+	fileList.Title = "Changed Files"
 	fileList.SetShowStatusBar(false)
 	fileList.SetFilteringEnabled(true)
 	fileList.Styles.Title = TitleStyle
@@ -73,14 +128,7 @@ func NewModel(provider llm.Provider, files []git.ChangedFile, diff string, provi
 	var initialState string
 	selectedTemplate := cfg.ResolveDefaultTemplate()
 
-	if len(diff) == 0 {
-		items := make([]list.Item, len(files))
-		for i, f := range files {
-			items[i] = f
-		}
-
-		fileList.SetItems(items)
-
+	if len(files) > 0 || len(diff) == 0 {
 		// Check if we should show template selection first
 		if len(cfg.Templates) > 0 {
 			if selectedTemplate == nil {
@@ -127,6 +175,7 @@ func NewModel(provider llm.Provider, files []git.ChangedFile, diff string, provi
 	m := Model{
 		state:         initialState,
 		cfg:           cfg,
+		repo:          repo,
 		provider:      provider,
 		diff:          diff,
 		spinner:       s,
@@ -136,15 +185,34 @@ func NewModel(provider llm.Provider, files []git.ChangedFile, diff string, provi
 		files:         files,
 		templates:     templates,
 		template:      selectedTemplate,
-		providerName:  providerName,
-		modelName:     modelName,
+		providerName:  opts.ProviderName,
+		modelName:     opts.ModelName,
 		confirmQuit:   cfg.GetConfirmQuit(),
-		diffViewer:    components.NewDiffViewer(),
+		diffBrowser:   components.NewDiffBrowser(),
 		markdown:      components.NewMarkdownRenderer(),
 		preHooks:      append([]string(nil), cfg.PreCommitHooks...),
 		postHooks:     append([]string(nil), cfg.PostCommitHooks...),
 		hookTimeout:   time.Duration(cfg.GetHookTimeoutSeconds()) * time.Second,
-		hooksDisabled: hooksDisabled,
+		hooksDisabled: opts.HooksDisabled,
+		language:      opts.Language,
+		commitOpts:    opts.Commit,
+		authorList:    newAuthorList(),
+		operation:     opts.Operation,
+		reword:        opts.Reword,
+		pick:          opts.Pick,
+		pickReason:    opts.PickReason,
+	}
+	m.setFiles(files)
+
+	if opts.Amend {
+		initialState := m.state
+		if _, err := m.startAmend(); err != nil {
+			m.state = StateError
+			m.err = err
+		} else if initialState == StateTemplateSelection {
+			// Generation starts once a template is picked.
+			m.state = initialState
+		}
 	}
 
 	return m
@@ -160,29 +228,56 @@ func (m Model) Init() tea.Cmd {
 
 func (m Model) generateCommitMsg() tea.Cmd {
 	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
+		template := m.generationTemplate()
+		generate := func(ctx context.Context, history []llm.Message) (string, error) {
+			ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+			defer cancel()
+			return m.provider.GenerateMessage(ctx, m.promptDiff(), history, template)
+		}
 
-		msg, err := m.provider.GenerateMessage(ctx, m.diff, m.history, m.template)
-		return GenerateMsg{Message: msg, Err: err}
+		history := append(OperationHistory(m.operation), m.history...)
+		switch {
+		case m.isAmending:
+			history = append(AmendHistory(m.lastCommit), m.history...)
+		case m.reword != nil:
+			history = append(rewordHistory(m.reword), m.history...)
+		case m.pick != nil:
+			history = append(PickHistory(*m.pick, m.pickReason), m.history...)
+		}
+		rules := lint.RulesFor(template, readMemory(m.repo))
+		msg, issues, err := lint.GenerateWithRepair(context.Background(), generate, history, rules, m.cfg.GetLintRepairAttempts())
+		return GenerateMsg{Message: msg, Issues: issues, Err: err}
 	}
 }
 
+// generationTemplate returns the selected template with the resolved commit language applied.
+func (m Model) generationTemplate() *config.CommitTemplate {
+	return m.template.WithLanguage(m.cfg.ResolveLanguage(m.template, m.language))
+}
+
 func (m Model) generateSummary() tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
-		summary, err := m.provider.SummarizeChanges(ctx, m.diff)
+		summary, err := m.provider.SummarizeChanges(ctx, m.promptDiff())
 		return SummaryMsg{Summary: summary, Err: err}
 	}
 }
 
 func (m Model) commitChanges() tea.Cmd {
 	return func() tea.Msg {
+		if m.reword != nil {
+			if err := m.repo.Reword(m.reword.Hash, m.commitMsg, m.commitOptions()); err != nil {
+				return CommitErrorMsg{Err: err}
+			}
+			return CommitSuccessMsg{}
+		}
+
 		if !m.hooksDisabled {
 			if err := hooks.Run(context.Background(), hooks.RunOptions{
 				Phase:         hooks.PhasePreCommit,
+				Dir:           m.repo.Dir(),
 				Commands:      m.preHooks,
 				Timeout:       m.hookTimeout,
 				CommitMessage: m.commitMsg,
@@ -193,10 +288,13 @@ func (m Model) commitChanges() tea.Cmd {
 		}
 
 		var err error
-		if m.isAmending {
-			err = git.AmendCommit(m.commitMsg)
-		} else {
-			err = git.Commit(m.commitMsg)
+		switch {
+		case m.isAmending:
+			err = m.repo.AmendCommit(m.commitMsg, m.commitOptions())
+		case m.operation.InProgress():
+			err = m.repo.ContinueOperation(m.operation, m.commitMsg, m.commitOptions())
+		default:
+			err = m.repo.Commit(m.commitMsg, m.commitOptions())
 		}
 
 		if err != nil {
@@ -207,6 +305,7 @@ func (m Model) commitChanges() tea.Cmd {
 		if !m.hooksDisabled {
 			if err := hooks.Run(context.Background(), hooks.RunOptions{
 				Phase:         hooks.PhasePostCommit,
+				Dir:           m.repo.Dir(),
 				Commands:      m.postHooks,
 				Timeout:       m.hookTimeout,
 				CommitMessage: m.commitMsg,
@@ -216,10 +315,13 @@ func (m Model) commitChanges() tea.Cmd {
 			}
 		}
 
+		// A continued rebase may stop again at the next conflict.
+		operation, _ := m.repo.InProgress()
+
 		remainingDiff, remainingFiles, err := m.collectRemainingChanges()
 		if err != nil {
 			// Commit already succeeded; skip follow-up prompt if we cannot inspect remaining changes.
-			return CommitSuccessMsg{HookWarning: hookWarning}
+			return CommitSuccessMsg{HookWarning: hookWarning, Operation: operation}
 		}
 
 		return CommitSuccessMsg{
@@ -227,6 +329,7 @@ func (m Model) commitChanges() tea.Cmd {
 			RemainingDiff:       remainingDiff,
 			RemainingFiles:      remainingFiles,
 			HookWarning:         hookWarning,
+			Operation:           operation,
 		}
 	}
 }
@@ -238,20 +341,16 @@ func (m Model) quitAfterSuccess() tea.Cmd {
 }
 
 func (m Model) collectRemainingChanges() (string, []git.ChangedFile, error) {
-	stagedDiff, err := git.GetStagedDiff()
+	stagedDiff, err := m.repo.GetStagedDiff()
 	if err != nil {
 		return "", nil, err
 	}
-	if len(stagedDiff) > 0 {
-		return string(stagedDiff), nil, nil
-	}
-
-	unstaged, err := git.ListUnstagedChanges()
+	files, err := m.repo.ListChanges()
 	if err != nil {
 		return "", nil, err
 	}
 
-	return "", unstaged, nil
+	return string(stagedDiff), files, nil
 }
 
 // Update handles messages and updates the model
@@ -265,19 +364,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		}
 
-		// Handle space key for file selection BEFORE other processing
-		if m.state == StateFileSelection && msg.String() == " " {
-			idx := m.fileList.Index()
-			if idx >= 0 && idx < len(m.files) {
-				m.files[idx].Selected = !m.files[idx].Selected
-				// Update the list items
-				items := make([]list.Item, len(m.files))
-				for i, f := range m.files {
-					items[i] = f
-				}
-				m.fileList.SetItems(items)
-			}
-			return m, nil
+		if m.state == StateCoAuthors && m.authorList.FilterState() == list.Filtering {
+			// While the filter prompt is open, keys belong to the list.
+			break
 		}
 
 		if msg.String() == "?" && m.state != StateRefining {
@@ -303,7 +392,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						}
 					}
 
-					if len(m.diff) > 0 {
+					if len(m.diff) > 0 || m.isAmending {
 						m.state = StateGenerating
 						return m, tea.Batch(m.spinner.Tick, m.generateCommitMsg())
 					}
@@ -321,89 +410,131 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 		case StateFileSelection:
+			m.notice = ""
 			switch msg.String() {
-			case "u":
-				// Unselect all
-				for i := range m.files {
-					m.files[i].Selected = false
+			case " ":
+				m.toggleCurrentFile()
+				return m, nil
+			case "s":
+				if err := m.toggleStaged(m.targetFiles()); err != nil {
+					m.state = StateError
+					m.err = err
+				}
+				return m, nil
+			case "p":
+				file, ok := m.currentFile()
+				if !ok {
+					return m, nil
+				}
+				if file.Staged {
+					m.notice = "Unstage the file with s to pick its hunks"
+					return m, nil
+				}
+				if file.Untracked {
+					m.notice = "Untracked files can only be staged as a whole"
+					return m, nil
+				}
+
+				diff, err := m.repo.GetFileDiff(file.Path)
+				if err != nil {
+					m.state = StateError
+					m.err = err
+					return m, nil
+				}
+				files, err := git.ParseDiff(string(diff))
+				if err != nil {
+					m.state = StateError
+					m.err = err
+					return m, nil
 				}
-				items := make([]list.Item, len(m.files))
-				for i, f := range m.files {
-					items[i] = f
+				if len(files) == 0 || len(files[0].Hunks) == 0 {
+					m.notice = "No hunks to select in " + file.Path
+					return m, nil
 				}
-				m.fileList.SetItems(items)
+
+				m.hunks = newHunkPicker(files[0])
+				m.hunks.setHeight(m.fileList.Height())
+				m.state = StateHunkSelection
 				return m, nil
+			case "u":
+				m.clearFileSelection()
+				return m, nil
+			case "A":
+				// Amend HEAD with whatever is staged, or just reword it
+				if m.operation.InProgress() || !m.repo.CanAmend() {
+					m.notice = "There is no commit to amend"
+					return m, nil
+				}
+				cmd, err := m.startAmend()
+				if err != nil {
+					m.state = StateError
+					m.err = err
+					return m, nil
+				}
+				return m, cmd
 			case "enter":
-				// Stage selected files, or current if none selected
+				// Stage selected files (or the current one when nothing is staged yet), then generate
 				var paths []string
-				hasSelected := false
-				for _, f := range m.files {
-					if f.Selected {
-						paths = append(paths, f.Path)
-						hasSelected = true
+				for _, file := range m.targetFiles() {
+					if file.Selected || m.diff == "" {
+						if !file.Staged {
+							paths = append(paths, file.Path)
+						}
 					}
 				}
 
-				if !hasSelected {
-					// No files selected, stage current
-					selected := m.fileList.SelectedItem()
-					if selected != nil {
-						file := selected.(git.ChangedFile)
-						paths = []string{file.Path}
-					}
+				if err := m.repo.StageFiles(paths); err != nil {
+					m.state = StateError
+					m.err = err
+					return m, nil
 				}
-
-				if len(paths) > 0 {
-					if err := git.StageFiles(paths); err != nil {
-						m.state = StateError
-						m.err = err
-						return m, nil
-					}
-
-					diff, err := git.GetStagedDiff()
-					if err != nil {
-						m.state = StateError
-						m.err = err
-						return m, nil
-					}
-
-					m.diff = string(diff)
-					m.state = StateGenerating
-					return m, tea.Batch(m.spinner.Tick, m.generateCommitMsg())
+				if err := m.refreshFiles(); err != nil {
+					m.state = StateError
+					m.err = err
+					return m, nil
+				}
+				if m.diff == "" {
+					m.notice = "Nothing staged yet"
+					return m, nil
 				}
+
+				m.state = StateGenerating
+				return m, tea.Batch(m.spinner.Tick, m.generateCommitMsg())
 			case "a":
-				paths := make([]string, len(m.files))
-				for i, f := range m.files {
-					paths[i] = f.Path
+				var paths []string
+				for _, f := range m.files {
+					if f.HasUnstagedChanges() {
+						paths = append(paths, f.Path)
+					}
 				}
-				if err := git.StageFiles(paths); err != nil {
+				if err := m.repo.StageFiles(paths); err != nil {
 					m.state = StateError
 					m.err = err
 					return m, nil
 				}
-
-				diff, err := git.GetStagedDiff()
-				if err != nil {
+				if err := m.refreshFiles(); err != nil {
 					m.state = StateError
 					m.err = err
 					return m, nil
 				}
 
-				m.diff = string(diff)
 				m.state = StateGenerating
 				return m, tea.Batch(m.spinner.Tick, m.generateCommitMsg())
 			case "d":
-				// Show diff preview for selected file
-				selected := m.fileList.SelectedItem()
-				if selected != nil {
-					file := selected.(git.ChangedFile)
-					diff, err := git.GetFileDiff(file.Path)
+				// Show diff preview for the current entry
+				file, ok := m.currentFile()
+				if ok {
+					diffFn := m.repo.GetFileDiff
+					if file.Staged {
+						diffFn = m.repo.GetStagedFileDiff
+					}
+					diff, err := diffFn(file.Path)
 					if err != nil {
 						m.state = StateError
 						m.err = err
 						return m, nil
 					}
-					m.diffViewer.SetContent(string(diff))
+					m.diffBrowser.SetDiff(string(diff), nil)
 					m.previousState = m.state
 					m.state = StateDiffPreview
 					return m, nil
@@ -417,9 +548,60 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, tea.Quit
 			}
 
+		case StateHunkSelection:
+			m.notice = ""
+			switch msg.String() {
+			case "up", "k":
+				m.hunks.move(-1)
+			case "down", "j":
+				m.hunks.move(1)
+			case " ":
+				m.hunks.toggle()
+			case "a":
+				m.hunks.toggleAll()
+			case "s", "tab":
+				m.hunks.split()
+			case "enter":
+				patch := m.hunks.file.Patch(m.hunks.selection())
+				if patch == nil {
+					m.notice = "Select at least one hunk or line to stage"
+					return m, nil
+				}
+				if err := m.repo.ApplyCached(patch); err != nil {
+					m.state = StateError
+					m.err = err
+					return m, nil
+				}
+				if err := m.refreshFiles(); err != nil {
+					m.state = StateError
+					m.err = err
+					return m, nil
+				}
+				m.state = StateFileSelection
+			case "q", "esc":
+				m.state = StateFileSelection
+			}
+			return m, nil
+
 		case StateReview:
+			m.notice = ""
 			switch msg.String() {
 			case "y":
+				if m.operation.InProgress() {
+					if err := m.refreshFiles(); err != nil {
+						m.state = StateError
+						m.err = err
+						return m, nil
+					}
+					if conflicts := conflictedPaths(m.files); len(conflicts) > 0 {
+						m.notice = "Resolve conflicts before committing: " + strings.Join(conflicts, ", ")
+						return m, nil
+					}
+				}
+				if m.isAmending {
+					m.state = StateAmendConfirm
+					return m, nil
+				}
 				m.hookWarning = ""
 				m.state = StateCommitting
 				return m, tea.Batch(m.spinner.Tick, m.commitChanges())
@@ -437,22 +619,39 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.state = StateSummary
 				return m, tea.Batch(m.spinner.Tick, m.generateSummary())
 			case "a":
-				// Amend option
-				if git.CanAmend() {
-					lastCommit, err := git.GetLastCommit()
+				// Toggle between a new commit and amending HEAD
+				if m.isAmending {
+					return m, m.stopAmend()
+				}
+				if m.canAmend {
+					cmd, err := m.startAmend()
 					if err != nil {
 						m.state = StateError
 						m.err = err
 						return m, nil
 					}
-					m.lastCommit = lastCommit
-					m.previousState = m.state
-					m.state = StateAmendConfirm
-					return m, nil
+					return m, cmd
 				}
+			case "c":
+				m.notice = ""
+				m.authorList.Title = "Co-authors (loading...)"
+				m.authorList.SetItems(nil)
+				m.state = StateCoAuthors
+				return m, m.loadAuthors()
+			case "g":
+				m.commitOpts.Sign = !m.commitOpts.Sign
+				return m, nil
+			case "o":
+				m.commitOpts.Signoff = !m.commitOpts.Signoff
+				return m, nil
+			case "b":
+				return m, m.startBranch()
+			case "Y":
+				// Copy the message without committing
+				return m, copyToClipboard("commit message", m.commitMsg)
 			case "d":
-				// Show full staged diff
-				m.diffViewer.SetContent(m.diff)
+				// Show the full diff the message describes
+				m.diffBrowser.SetDiff(m.promptDiff(), nil)
 				m.previousState = m.state
 				m.state = StateDiffPreview
 				return m, nil
@@ -465,6 +664,21 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, tea.Quit
 			}
 
+		case StateBranch:
+			switch msg.String() {
+			case "enter":
+				if m.askingTicket {
+					m.setBranchTicket()
+					return m, textinput.Blink
+				}
+				m.createBranch()
+				return m, nil
+			case "esc":
+				m.notice = ""
+				m.state = StateReview
+				return m, nil
+			}
+
 		case StateRefining:
 			switch msg.String() {
 			case "esc":
@@ -482,6 +696,20 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, tea.Batch(m.spinner.Tick, m.generateCommitMsg())
 			}
 
+		case StateCoAuthors:
+			switch msg.String() {
+			case " ":
+				m.toggleAuthor()
+				return m, nil
+			case "enter":
+				m.applyAuthors()
+				m.state = StateReview
+				return m, nil
+			case "q", "esc":
+				m.state = StateReview
+				return m, nil
+			}
+
 		case StateSummary:
 			if msg.String() != "?" {
 				m.state = StateReview
@@ -499,12 +727,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			switch msg.String() {
 			case "y":
 				m.hookWarning = ""
-				m.isAmending = true
 				m.state = StateCommitting
 				return m, tea.Batch(m.spinner.Tick, m.commitChanges())
 			case "n", "esc":
-				m.isAmending = false
-				m.state = m.previousState
+				m.state = StateReview
 				return m, nil
 			}
 
@@ -521,26 +747,23 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			switch msg.String() {
 			case "enter":
 				m.isAmending = false
+				m.amendDiff = ""
 				m.lastCommit = nil
+				m.lintIssues = nil
 				m.summary = ""
 				m.history = nil
 				m.hookWarning = ""
 
-				if len(m.diff) > 0 {
-					m.state = StateGenerating
-					return m, tea.Batch(m.spinner.Tick, m.generateCommitMsg())
-				}
-
 				if len(m.files) == 0 {
+					if len(m.diff) > 0 {
+						m.state = StateGenerating
+						return m, tea.Batch(m.spinner.Tick, m.generateCommitMsg())
+					}
 					return m, tea.Quit
 				}
 
-				items := make([]list.Item, len(m.files))
-				for i := range m.files {
-					m.files[i].Selected = false
-					items[i] = m.files[i]
-				}
-				m.fileList.SetItems(items)
+				m.clearFileSelection()
+				m.setFiles(m.files)
 
 				if len(m.templates) > 0 && m.template == nil {
 					m.state = StateTemplateSelection
@@ -579,6 +802,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		m.fileList.SetSize(fileListWidth, fileListHeight)
+		m.authorList.SetSize(fileListWidth, fileListHeight)
+		m.hunks.setHeight(fileListHeight)
 		if len(m.templates) > 0 {
 			m.templateList.SetSize(fileListWidth, fileListHeight)
 		}
@@ -588,7 +813,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		m.textarea.SetWidth(textareaWidth)
 		m.textarea.SetHeight(8)
-		m.diffViewer.SetSize(contentWidth, contentHeight-4)
+		m.diffBrowser.SetSize(contentWidth, contentHeight-4)
 		m.markdown.SetWidth(textareaWidth)
 
 	case GenerateMsg:
@@ -598,9 +823,43 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		m.commitMsg = msg.Message
+		m.lintIssues = msg.Issues
+		m.canAmend = !m.operation.InProgress() && m.reword == nil && m.pick == nil && m.repo.CanAmend()
 		m.state = StateReview
 		return m, nil
 
+	case authorsLoadedMsg:
+		m.authorList.Title = "Co-authors"
+		if msg.err != nil {
+			m.notice = msg.err.Error()
+			return m, nil
+		}
+		m.setAuthors(msg.authors)
+		if len(msg.authors) == 0 {
+			m.notice = "No other authors found in the history"
+		}
+		return m, nil
+
+	case copiedMsg:
+		return m, m.copied.set(msg)
+
+	case copyStatusExpiredMsg:
+		m.copied.expire(msg)
+		return m, nil
+
+	case branchSuggestedMsg:
+		if m.state != StateBranch {
+			return m, nil
+		}
+		m.branchInput.Placeholder = "feat/short-description"
+		if msg.err != nil {
+			m.notice = msg.err.Error()
+			return m, nil
+		}
+		m.branchSuggestion = &msg.suggestion
+		m.fillBranchName()
+		return m, nil
+
 	case SummaryMsg:
 		if msg.Err != nil {
 			m.state = StateError
@@ -612,6 +871,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case CommitSuccessMsg:
 		m.hookWarning = msg.HookWarning
+		m.operation = msg.Operation
 		if msg.HasRemainingChanges {
 			m.diff = msg.RemainingDiff
 			m.files = msg.RemainingFiles
@@ -643,14 +903,29 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case StateFileSelection:
 		m.fileList, cmd = m.fileList.Update(msg)
 		cmds = append(cmds, cmd)
+		if key, ok := msg.(tea.KeyMsg); ok && (key.String() == "up" || key.String() == "k") {
+			m.skipSectionHeader(-1)
+		} else {
+			m.skipSectionHeader(1)
+		}
 	case StateTemplateSelection:
 		m.templateList, cmd = m.templateList.Update(msg)
 		cmds = append(cmds, cmd)
 	case StateRefining:
 		m.textarea, cmd = m.textarea.Update(msg)
 		cmds = append(cmds, cmd)
+	case StateBranch:
+		if m.askingTicket {
+			m.ticketInput, cmd = m.ticketInput.Update(msg)
+		} else {
+			m.branchInput, cmd = m.branchInput.Update(msg)
+		}
+		cmds = append(cmds, cmd)
+	case StateCoAuthors:
+		m.authorList, cmd = m.authorList.Update(msg)
+		cmds = append(cmds, cmd)
 	case StateDiffPreview:
-		m.diffViewer, cmd = m.diffViewer.Update(msg)
+		m.diffBrowser, cmd = m.diffBrowser.Update(msg)
 		cmds = append(cmds, cmd)
 	}
 
@@ -669,12 +944,16 @@ func (m Model) View() string {
 		content = m.renderTemplateSelection()
 	case StateFileSelection:
 		content = m.renderFileSelection()
+	case StateHunkSelection:
+		content = m.renderHunkSelection()
 	case StateGenerating:
 		content = m.renderGenerating()
 	case StateReview:
 		content = m.renderReview()
 	case StateRefining:
 		content = m.renderRefining()
+	case StateCoAuthors:
+		content = m.renderCoAuthors()
 	case StateSummary:
 		content = m.renderSummary()
 	case StateCommitting:
@@ -691,6 +970,8 @@ func (m Model) View() string {
 		content = m.renderAmendConfirm()
 	case StateQuitConfirm:
 		content = m.renderQuitConfirm()
+	case StateBranch:
+		content = m.renderBranch()
 	}
 
 	return AppStyle.Render(content)
@@ -704,3 +985,15 @@ type templateItem struct {
 func (t templateItem) Title() string       { return t.Name }
 func (t templateItem) Description() string { return t.Format }
 func (t templateItem) FilterValue() string { return t.Name }
+
+// readMemory returns the MEMORY.md notes of the repository's project.
+func readMemory(repo *git.Repo) string {
+	if repo == nil {
+		return config.ReadMemory("")
+	}
+	root, err := repo.Root()
+	if err != nil {
+		return config.ReadMemory("")
+	}
+	return config.ReadMemory(root)
+}
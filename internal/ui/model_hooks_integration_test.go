@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/samcharles93/commiter/internal/config"
+	"github.com/samcharles93/commiter/internal/git"
 )
 
 func TestInteractiveCommitPreHookFailureBlocksCommit(t *testing.T) {
@@ -25,19 +26,17 @@ func TestInteractiveCommitPreHookFailureBlocksCommit(t *testing.T) {
 		PreCommitHooks:     []string{failingHookCommandForModelTest()},
 		HookTimeoutSeconds: 5,
 	}
-	m := NewModel(stubProvider{}, nil, "", "openai", "gpt-4o", cfg, false)
+	m := NewModel(stubProvider{}, nil, "", cfg, Options{Repo: git.NewRepo(repoDir, nil), ProviderName: "openai", ModelName: "gpt-4o"})
 	m.commitMsg = "feat: blocked by pre hook"
 
-	withWorkingDirForModelHookTest(t, repoDir, func() {
-		msg := m.commitChanges()()
-		commitErr, ok := msg.(CommitErrorMsg)
-		if !ok {
-			t.Fatalf("expected CommitErrorMsg, got %T", msg)
-		}
-		if !strings.Contains(commitErr.Err.Error(), "pre-commit hook failed") {
-			t.Fatalf("expected pre-hook failure error, got %v", commitErr.Err)
-		}
-	})
+	msg := m.commitChanges()()
+	commitErr, ok := msg.(CommitErrorMsg)
+	if !ok {
+		t.Fatalf("expected CommitErrorMsg, got %T", msg)
+	}
+	if !strings.Contains(commitErr.Err.Error(), "pre-commit hook failed") {
+		t.Fatalf("expected pre-hook failure error, got %v", commitErr.Err)
+	}
 
 	if count := commitCountForModelHookTest(t, repoDir); count != 1 {
 		t.Fatalf("expected pre-hook to block commit, got commit count %d", count)
@@ -56,20 +55,18 @@ func TestInteractiveAmendPreHookFailureBlocksAmend(t *testing.T) {
 		PreCommitHooks:     []string{failingHookCommandForModelTest()},
 		HookTimeoutSeconds: 5,
 	}
-	m := NewModel(stubProvider{}, nil, "", "openai", "gpt-4o", cfg, false)
+	m := NewModel(stubProvider{}, nil, "", cfg, Options{Repo: git.NewRepo(repoDir, nil), ProviderName: "openai", ModelName: "gpt-4o"})
 	m.commitMsg = "feat: amended message"
 	m.isAmending = true
 
-	withWorkingDirForModelHookTest(t, repoDir, func() {
-		msg := m.commitChanges()()
-		commitErr, ok := msg.(CommitErrorMsg)
-		if !ok {
-			t.Fatalf("expected CommitErrorMsg, got %T", msg)
-		}
-		if !strings.Contains(commitErr.Err.Error(), "pre-commit hook failed") {
-			t.Fatalf("expected pre-hook failure error, got %v", commitErr.Err)
-		}
-	})
+	msg := m.commitChanges()()
+	commitErr, ok := msg.(CommitErrorMsg)
+	if !ok {
+		t.Fatalf("expected CommitErrorMsg, got %T", msg)
+	}
+	if !strings.Contains(commitErr.Err.Error(), "pre-commit hook failed") {
+		t.Fatalf("expected pre-hook failure error, got %v", commitErr.Err)
+	}
 
 	if count := commitCountForModelHookTest(t, repoDir); count != 1 {
 		t.Fatalf("expected amend to be blocked, got commit count %d", count)
@@ -115,25 +112,6 @@ func runGitForModelHookTest(t *testing.T, dir string, args ...string) string {
 	return string(out)
 }
 
-func withWorkingDirForModelHookTest(t *testing.T, dir string, fn func()) {
-	t.Helper()
-
-	original, err := os.Getwd()
-	if err != nil {
-		t.Fatalf("get working directory: %v", err)
-	}
-	if err := os.Chdir(dir); err != nil {
-		t.Fatalf("change working directory: %v", err)
-	}
-	defer func() {
-		if err := os.Chdir(original); err != nil {
-			t.Fatalf("restore working directory: %v", err)
-		}
-	}()
-
-	fn()
-}
-
 func commitCountForModelHookTest(t *testing.T, dir string) int {
 	t.Helper()
 	out := strings.TrimSpace(runGitForModelHookTest(t, dir, "rev-list", "--count", "HEAD"))
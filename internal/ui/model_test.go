@@ -3,6 +3,7 @@ package ui
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/samcharles93/commiter/internal/config"
 	"github.com/samcharles93/commiter/internal/git"
+	"github.com/samcharles93/commiter/internal/lint"
 	"github.com/samcharles93/commiter/internal/llm"
 )
 
@@ -26,7 +28,7 @@ func (stubProvider) SummarizeChanges(context.Context, string) (string, error) {
 }
 
 func TestModelWindowResizeWithStagedDiffDoesNotPanic(t *testing.T) {
-	m := NewModel(stubProvider{}, nil, "diff --git a/file b/file", "openai", "gpt-4o", &config.Config{}, false)
+	m := NewModel(stubProvider{}, nil, "diff --git a/file b/file", &config.Config{}, Options{ProviderName: "openai", ModelName: "gpt-4o"})
 
 	runResize := func(msg tea.WindowSizeMsg) {
 		t.Helper()
@@ -44,7 +46,7 @@ func TestModelWindowResizeWithStagedDiffDoesNotPanic(t *testing.T) {
 }
 
 func TestCommitSuccessWithRemainingChangesShowsContinuePrompt(t *testing.T) {
-	m := NewModel(stubProvider{}, nil, "", "openai", "gpt-4o", &config.Config{}, false)
+	m := NewModel(stubProvider{}, nil, "", &config.Config{}, Options{ProviderName: "openai", ModelName: "gpt-4o"})
 	m.commitMsg = "feat: test"
 
 	updated, cmd := m.Update(CommitSuccessMsg{
@@ -71,7 +73,7 @@ func TestCommitSuccessWithRemainingChangesShowsContinuePrompt(t *testing.T) {
 }
 
 func TestCommitSuccessStoresHookWarning(t *testing.T) {
-	m := NewModel(stubProvider{}, nil, "", "openai", "gpt-4o", &config.Config{}, false)
+	m := NewModel(stubProvider{}, nil, "", &config.Config{}, Options{ProviderName: "openai", ModelName: "gpt-4o"})
 	m.commitMsg = "feat: test"
 
 	updated, _ := m.Update(CommitSuccessMsg{
@@ -88,7 +90,7 @@ func TestCommitSuccessStoresHookWarning(t *testing.T) {
 }
 
 func TestContinueConfirmEnterStartsGeneratingWhenDiffExists(t *testing.T) {
-	m := NewModel(stubProvider{}, nil, "", "openai", "gpt-4o", &config.Config{}, false)
+	m := NewModel(stubProvider{}, nil, "", &config.Config{}, Options{ProviderName: "openai", ModelName: "gpt-4o"})
 	m.state = StateContinueConfirm
 	m.diff = "diff --git a/main.go b/main.go"
 	m.history = []llm.Message{{Role: "assistant", Content: "old"}}
@@ -112,7 +114,7 @@ func TestContinueConfirmEnterStartsGeneratingWhenDiffExists(t *testing.T) {
 }
 
 func TestModelWindowResizeWithMarkdownDoesNotPanic(t *testing.T) {
-	m := NewModel(stubProvider{}, nil, "", "openai", "gpt-4o", &config.Config{}, false)
+	m := NewModel(stubProvider{}, nil, "", &config.Config{}, Options{ProviderName: "openai", ModelName: "gpt-4o"})
 	m.summary = "### Summary\n\n- one\n- two"
 	m.state = StateSummary
 
@@ -126,7 +128,7 @@ func TestModelWindowResizeWithMarkdownDoesNotPanic(t *testing.T) {
 }
 
 func TestRenderSuccessIncludesHookWarning(t *testing.T) {
-	m := NewModel(stubProvider{}, nil, "", "openai", "gpt-4o", &config.Config{}, false)
+	m := NewModel(stubProvider{}, nil, "", &config.Config{}, Options{ProviderName: "openai", ModelName: "gpt-4o"})
 	m.state = StateSuccess
 	m.commitMsg = "feat: test"
 	m.hookWarning = "### Post-commit hook failed\n\n```text\nboom\n```"
@@ -144,7 +146,7 @@ func TestTemplateSelectionPersistsDefaultAndGeneratesWithStagedDiff(t *testing.T
 	cfg := &config.Config{
 		Templates: config.GetDefaultTemplates(),
 	}
-	m := NewModel(stubProvider{}, nil, "diff --git a/file b/file", "openai", "gpt-4o", cfg, false)
+	m := NewModel(stubProvider{}, nil, "diff --git a/file b/file", cfg, Options{ProviderName: "openai", ModelName: "gpt-4o"})
 	if m.state != StateTemplateSelection {
 		t.Fatalf("expected state %q, got %q", StateTemplateSelection, m.state)
 	}
@@ -185,7 +187,7 @@ func TestNewModelUsesConfiguredDefaultTemplate(t *testing.T) {
 		DefaultTemplate: "Simple",
 	}
 
-	withDiff := NewModel(stubProvider{}, nil, "diff --git a/file b/file", "openai", "gpt-4o", cfg, false)
+	withDiff := NewModel(stubProvider{}, nil, "diff --git a/file b/file", cfg, Options{ProviderName: "openai", ModelName: "gpt-4o"})
 	if withDiff.state != StateGenerating {
 		t.Fatalf("expected staged-diff flow to start in %q, got %q", StateGenerating, withDiff.state)
 	}
@@ -194,7 +196,7 @@ func TestNewModelUsesConfiguredDefaultTemplate(t *testing.T) {
 	}
 
 	files := []git.ChangedFile{{Path: "main.go", Status: "modified"}}
-	noDiff := NewModel(stubProvider{}, files, "", "openai", "gpt-4o", cfg, false)
+	noDiff := NewModel(stubProvider{}, files, "", cfg, Options{ProviderName: "openai", ModelName: "gpt-4o"})
 	if noDiff.state != StateFileSelection {
 		t.Fatalf("expected unstaged flow to skip template prompt and start in %q, got %q", StateFileSelection, noDiff.state)
 	}
@@ -226,3 +228,113 @@ func setHomeForModelTest(t *testing.T, home string) {
 		}
 	})
 }
+
+func TestRenderReviewShowsRemainingLintIssues(t *testing.T) {
+	m := NewModel(stubProvider{}, nil, "diff --git a/file b/file", &config.Config{}, Options{ProviderName: "openai", ModelName: "gpt-4o"})
+
+	updated, _ := m.Update(GenerateMsg{
+		Message: "feat: added lint",
+		Issues: []lint.Issue{
+			{Rule: lint.RuleSubjectMood, Severity: lint.SeverityWarning, Line: 1, Message: "subject should use the imperative mood"},
+		},
+	})
+
+	model := updated.(Model)
+	if model.state != StateReview {
+		t.Fatalf("expected state %q, got %q", StateReview, model.state)
+	}
+	view := model.View()
+	if !strings.Contains(view, "imperative mood") {
+		t.Fatalf("expected lint warning in review view, got:\n%s", view)
+	}
+}
+
+func TestReviewTogglesSigningAndSignoff(t *testing.T) {
+	m := NewModel(stubProvider{}, nil, "diff --git a/file b/file", &config.Config{}, Options{
+		ProviderName: "openai",
+		ModelName:    "gpt-4o",
+		Commit:       git.CommitOptions{Signoff: true},
+	})
+	m.state = StateReview
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	updated, _ = updated.(Model).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+	model := updated.(Model)
+
+	if !model.commitOpts.Sign || model.commitOpts.Signoff {
+		t.Fatalf("expected signing on and signoff off, got %+v", model.commitOpts)
+	}
+	if !strings.Contains(model.View(), "Commit: signed") {
+		t.Fatalf("expected commit options in review view, got:\n%s", model.View())
+	}
+}
+
+func TestCoAuthorsPersistAcrossContinueLoop(t *testing.T) {
+	m := NewModel(stubProvider{}, nil, "diff --git a/file b/file", &config.Config{}, Options{ProviderName: "openai", ModelName: "gpt-4o"})
+	m.state = StateReview
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	if cmd == nil {
+		t.Fatal("expected author loading command")
+	}
+	updated, _ = updated.(Model).Update(authorsLoadedMsg{authors: []git.Author{
+		{Name: "Ada", Email: "ada@example.com", PathCommits: 2},
+		{Name: "Linus", Email: "linus@example.com"},
+	}})
+	updated, _ = updated.(Model).Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	updated, _ = updated.(Model).Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model := updated.(Model)
+
+	if model.state != StateReview {
+		t.Fatalf("expected to return to review, got %q", model.state)
+	}
+	trailers := model.commitOptions().Trailers
+	if len(trailers) != 1 || trailers[0] != "Co-authored-by: Ada <ada@example.com>" {
+		t.Fatalf("expected co-author trailer, got %v", trailers)
+	}
+
+	model.state = StateContinueConfirm
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if got := updated.(Model).coAuthors; len(got) != 1 {
+		t.Fatalf("expected co-authors to persist for the next commit, got %v", got)
+	}
+}
+
+func TestReviewCopiesMessageWithoutCommitting(t *testing.T) {
+	m := NewModel(stubProvider{}, nil, "diff --git a/file b/file", &config.Config{}, Options{ProviderName: "openai", ModelName: "gpt-4o"})
+	m.state = StateReview
+	m.commitMsg = "feat: add export"
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("Y")})
+	model := updated.(Model)
+	if model.state != StateReview || cmd == nil {
+		t.Fatalf("expected to stay in review with a copy command, got state %q", model.state)
+	}
+
+	updated, _ = model.Update(copiedMsg{err: errors.New("failed to copy to the clipboard: no terminal")})
+	if view := updated.(Model).View(); !strings.Contains(view, "no terminal") {
+		t.Fatalf("expected the copy error in the review, got:\n%s", view)
+	}
+}
+
+func TestReviewDiffPreviewBrowsesFiles(t *testing.T) {
+	diff := "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n-old\n+new\n" +
+		"diff --git a/b.go b/b.go\n--- a/b.go\n+++ b/b.go\n@@ -1 +1 @@\n-before\n+after\n"
+	m := NewModel(stubProvider{}, nil, diff, &config.Config{}, Options{ProviderName: "openai", ModelName: "gpt-4o"})
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model := updated.(Model)
+	model.state = StateReview
+
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	updated, _ = updated.(Model).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("]")})
+	model = updated.(Model)
+	if model.state != StateDiffPreview {
+		t.Fatalf("expected the diff preview, got %q", model.state)
+	}
+	if file, _ := model.diffBrowser.Selected(); file.Path != "b.go" {
+		t.Fatalf("expected ] to select b.go, got %q", file.Path)
+	}
+	if view := model.View(); !strings.Contains(view, "2 file(s)") || !strings.Contains(view, "next/prev file") {
+		t.Fatalf("expected the file sidebar in the preview, got:\n%s", view)
+	}
+}
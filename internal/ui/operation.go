@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/samcharles93/commiter/internal/git"
+	"github.com/samcharles93/commiter/internal/llm"
+)
+
+// OperationHistory returns the follow-up prompt that seeds generation while a
+// merge, rebase, cherry-pick, revert or am is in progress.
+func OperationHistory(op git.Operation) []llm.Message {
+	if !op.InProgress() {
+		return nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "This commit concludes an in-progress git %s.", op.Kind)
+	if op.Message != "" {
+		fmt.Fprintf(&b, " Git prepared this message:\n\n%s\n\nKeep its subject and intent", op.Message)
+		if op.Kind == git.OperationMerge {
+			b.WriteString(" (keep the merge subject as it is)")
+		}
+		b.WriteString(", and")
+	} else {
+		b.WriteString(" Write the message, and")
+	}
+
+	if len(op.Conflicts) > 0 {
+		fmt.Fprintf(&b, " add a body that explains how the conflicts in %s were resolved.", strings.Join(op.Conflicts, ", "))
+	} else {
+		b.WriteString(" if the diff shows resolved conflicts, explain how they were resolved in the body.")
+	}
+
+	return []llm.Message{{Role: "user", Content: b.String()}}
+}
+
+// conflictedPaths returns the files that still have unresolved conflicts.
+func conflictedPaths(files []git.ChangedFile) []string {
+	var paths []string
+	for _, f := range files {
+		if f.Conflict {
+			paths = append(paths, f.Path)
+		}
+	}
+	return paths
+}
+
+// operationBanner describes an in-progress operation for the file list and review screens.
+func (m Model) operationBanner() string {
+	if !m.operation.InProgress() {
+		return ""
+	}
+
+	banner := "⚠ " + m.operation.Title()
+	if head := m.operation.Head; head != "" {
+		if len(head) > 7 {
+			head = head[:7]
+		}
+		banner += " (" + head + ")"
+	}
+	if conflicts := conflictedPaths(m.files); len(conflicts) > 0 {
+		banner += fmt.Sprintf(" • %d conflicted file(s) to resolve", len(conflicts))
+	}
+	return banner
+}
@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/samcharles93/commiter/internal/config"
+	"github.com/samcharles93/commiter/internal/git"
+)
+
+func TestOperationHistorySeedsPreparedMessage(t *testing.T) {
+	if history := OperationHistory(git.Operation{}); history != nil {
+		t.Fatalf("expected no seed without an operation, got %v", history)
+	}
+
+	history := OperationHistory(git.Operation{
+		Kind:      git.OperationMerge,
+		Message:   "Merge branch 'side'",
+		Conflicts: []string{"f.txt"},
+	})
+	if len(history) != 1 {
+		t.Fatalf("expected one seed message, got %v", history)
+	}
+	content := history[0].Content
+	if !strings.Contains(content, "Merge branch 'side'") || !strings.Contains(content, "conflicts in f.txt") {
+		t.Fatalf("expected prepared message and conflicts in seed, got %q", content)
+	}
+}
+
+func TestReviewBlocksCommitWhileConflictsRemain(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repoDir := initRepoForModelHookTests(t)
+	runGitForModelHookTest(t, repoDir, "checkout", "-b", "side")
+	writeFileForModelHookTest(t, filepath.Join(repoDir, "tracked.txt"), "side\n")
+	runGitForModelHookTest(t, repoDir, "commit", "-am", "feat: side")
+	runGitForModelHookTest(t, repoDir, "checkout", "-")
+	writeFileForModelHookTest(t, filepath.Join(repoDir, "tracked.txt"), "main\n")
+	runGitForModelHookTest(t, repoDir, "commit", "-am", "feat: main")
+	merge := exec.Command("git", "merge", "side")
+	merge.Dir = repoDir
+	_ = merge.Run()
+
+	repo := git.NewRepo(repoDir, nil)
+	op, err := repo.InProgress()
+	if err != nil || op.Kind != git.OperationMerge {
+		t.Fatalf("expected merge in progress, got %+v (%v)", op, err)
+	}
+
+	m := NewModel(stubProvider{}, nil, "diff --git a/tracked.txt b/tracked.txt", &config.Config{}, Options{Repo: repo, Operation: op})
+	m.state = StateReview
+	m.commitMsg = "Merge branch 'side'"
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	model := updated.(Model)
+	if cmd != nil || model.state != StateReview {
+		t.Fatalf("expected commit to be blocked, got state %q", model.state)
+	}
+	if !strings.Contains(model.View(), "Resolve conflicts before committing: tracked.txt") {
+		t.Fatalf("expected conflict notice, got:\n%s", model.View())
+	}
+}
@@ -0,0 +1,247 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/samcharles93/commiter/internal/git"
+	"github.com/samcharles93/commiter/internal/llm"
+)
+
+// PickHistory returns the follow-up prompt that seeds generation for a revert
+// or cherry-pick made from the history browser.
+func PickHistory(pick git.Pick, reason string) []llm.Message {
+	var b strings.Builder
+	switch pick.Kind {
+	case git.OperationRevert:
+		fmt.Fprintf(&b, "This commit reverts commit %s; the diff undoes its change.", pick.Hash)
+		if pick.Message != "" {
+			fmt.Fprintf(&b, " Git prepared this message:\n\n%s\n\n", pick.Message)
+		}
+		if reason != "" {
+			fmt.Fprintf(&b, " It is reverted because: %s\nExplain that reason in the body.", reason)
+		} else {
+			b.WriteString(" Explain in the body what is undone and, if the diff shows it, why.")
+		}
+		fmt.Fprintf(&b, " Keep the line \"This reverts commit %s.\" in the body.", pick.Hash)
+	default:
+		fmt.Fprintf(&b, "This commit cherry-picks commit %s onto the current branch.", pick.Hash)
+		if pick.Message != "" {
+			fmt.Fprintf(&b, " Its original message is:\n\n%s\n\nKeep its wording where it still fits the diff.", pick.Message)
+		}
+		if reason != "" {
+			fmt.Fprintf(&b, " It is picked because: %s\nMention that in the body.", reason)
+		}
+		fmt.Fprintf(&b, " End the body with \"(cherry picked from commit %s)\".", pick.Hash)
+	}
+	if len(pick.Conflicts) > 0 {
+		fmt.Fprintf(&b, " Conflicts in %s were resolved by hand; say how if the diff shows it.", strings.Join(pick.Conflicts, ", "))
+	}
+	return []llm.Message{{Role: "user", Content: b.String()}}
+}
+
+func pickTitle(pick git.Pick) string {
+	if pick.Kind == git.OperationRevert {
+		return "↩️  Reverting " + pick.Hash[:8]
+	}
+	return "🍒 Cherry-picking " + pick.Hash[:8]
+}
+
+// pickVerb is the past tense for the status line, e.g. "Reverted".
+func pickVerb(kind git.OperationKind) string {
+	if kind == git.OperationRevert {
+		return "Reverted"
+	}
+	return "Cherry-picked"
+}
+
+// startPickReason asks why the commit in the detail view is being reverted
+// or cherry-picked before applying it.
+func (m HistoryModel) startPickReason(kind git.OperationKind) (tea.Model, tea.Cmd) {
+	if m.commitDetail == nil {
+		return m, nil
+	}
+	if m.opts.Provider == nil {
+		m.notice = "Reverting and cherry-picking need a configured provider and API key"
+		return m, nil
+	}
+
+	input := textinput.New()
+	input.Placeholder = "Optional"
+	input.CharLimit = 300
+	input.Width = 60
+	input.Focus()
+	m.reasonInput = input
+	m.pickKind = kind
+	m.state = historyStatePickReason
+	return m, textinput.Blink
+}
+
+// applyPick reverts or cherry-picks the commit without committing, then
+// either writes the message or stops for conflicts to be resolved.
+func (m HistoryModel) applyPick() (tea.Model, tea.Cmd) {
+	pick, err := m.repo.StartPick(m.pickKind, m.commitDetail.Hash)
+	if err != nil {
+		m.notice = err.Error()
+		m.state = historyStateDetail
+		return m, nil
+	}
+
+	m.pick = &pick
+	m.pickReason = strings.TrimSpace(m.reasonInput.Value())
+	m.pickConflicts = pick.Conflicts
+	if len(pick.Conflicts) > 0 {
+		m.state = historyStatePick
+		return m, nil
+	}
+	return m.startPickFlow()
+}
+
+// continuePick checks that the conflicts are resolved and moves on to the
+// message.
+func (m HistoryModel) continuePick() (tea.Model, tea.Cmd) {
+	conflicts, err := m.repo.PickConflicts()
+	if err != nil {
+		m.state = historyStateError
+		m.err = err
+		return m, nil
+	}
+	m.pickConflicts = conflicts
+	if len(conflicts) > 0 {
+		m.notice = "Still conflicted: " + strings.Join(conflicts, ", ") + ". Resolve and git add them first."
+		return m, nil
+	}
+	return m.startPickFlow()
+}
+
+// abortPick undoes the revert or cherry-pick and returns to the history.
+func (m HistoryModel) abortPick() (tea.Model, tea.Cmd) {
+	if err := m.repo.AbortPick(); err != nil {
+		m.notice = err.Error()
+		return m, nil
+	}
+	m.notice = "Aborted the " + string(m.pick.Kind) + " of " + m.pick.Hash[:8]
+	m.pick = nil
+	m.state = historyStateList
+	return m, nil
+}
+
+// startPickFlow opens the commit message flow for the staged result.
+func (m HistoryModel) startPickFlow() (tea.Model, tea.Cmd) {
+	diff, err := m.repo.GetStagedDiff()
+	if err != nil {
+		m.state = historyStateError
+		m.err = err
+		return m, nil
+	}
+	if len(diff) == 0 {
+		// A cherry-pick of changes the branch already has leaves nothing.
+		kind, hash := m.pick.Kind, m.pick.Hash
+		if err := m.repo.AbortPick(); err != nil {
+			m.notice = err.Error()
+			return m, nil
+		}
+		m.pick = nil
+		m.notice = fmt.Sprintf("Nothing to commit: the %s of %s changes nothing on this branch", kind, hash[:8])
+		m.state = historyStateList
+		return m, nil
+	}
+
+	model := NewModel(m.opts.Provider, nil, string(diff), m.opts.Config, Options{
+		Repo:         m.repo,
+		ProviderName: m.opts.ProviderName,
+		ModelName:    m.opts.ModelName,
+		Language:     m.opts.Language,
+		Commit:       m.opts.Commit,
+		Pick:         m.pick,
+		PickReason:   m.pickReason,
+	})
+	if m.windowSize.Width > 0 {
+		updated, _ := model.Update(m.windowSize)
+		model = updated.(Model)
+	}
+
+	m.pickFlow = &model
+	m.state = historyStatePickCommit
+	return m, model.Init()
+}
+
+// updatePickFlow forwards messages to the embedded commit message flow.
+// Cancelling it leaves the changes staged and returns to the pick screen,
+// where they can be committed later or aborted.
+func (m HistoryModel) updatePickFlow(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		switch m.pickFlow.state {
+		case StateError:
+			m.pickFlow = nil
+			m.state = historyStatePick
+			return m, nil
+		case StateReview, StateGenerating, StateTemplateSelection:
+			if msg.String() == "q" || msg.String() == "esc" {
+				m.pickFlow = nil
+				m.state = historyStatePick
+				return m, nil
+			}
+		}
+
+	case CommitSuccessMsg:
+		kind, hash := m.pick.Kind, m.pick.Hash
+		m.pickFlow = nil
+		m.pick = nil
+		if err := m.reloadCommits(); err != nil {
+			m.state = historyStateError
+			m.err = err
+			return m, nil
+		}
+		m.notice = "✓ " + pickVerb(kind) + " " + hash[:8]
+		m.state = historyStateList
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		m.windowSize = msg
+	}
+
+	updated, cmd := m.pickFlow.Update(msg)
+	model := updated.(Model)
+	m.pickFlow = &model
+	return m, cmd
+}
+
+func (m HistoryModel) renderPickReason() string {
+	var b strings.Builder
+	verb := "revert"
+	if m.pickKind == git.OperationCherryPick {
+		verb = "cherry-pick"
+	}
+	b.WriteString(TitleStyle.Render(fmt.Sprintf("Why %s %s?", verb, m.commitDetail.Hash[:8])) + "\n")
+	b.WriteString(SubtleStyle.Render(m.commitDetail.Subject) + "\n\n")
+	b.WriteString(m.reasonInput.View() + "\n\n")
+	b.WriteString(HelpStyle.Render("enter: " + verb + " • esc: cancel"))
+	return b.String()
+}
+
+func (m HistoryModel) renderPick() string {
+	var b strings.Builder
+	b.WriteString(TitleStyle.Render(pickTitle(*m.pick)) + "\n\n")
+	if len(m.pickConflicts) > 0 {
+		b.WriteString(ErrorStyle.Render(fmt.Sprintf("⚠ Conflicts in %d file(s):", len(m.pickConflicts))) + "\n")
+		for _, path := range m.pickConflicts {
+			b.WriteString(SubtleStyle.Render("  • "+path) + "\n")
+		}
+		b.WriteString("\n" + SubtleStyle.Render("Resolve them in your editor and stage them with git add, then press enter.") + "\n\n")
+	} else {
+		b.WriteString(SubtleStyle.Render("The changes are staged. Press enter to write the message.") + "\n\n")
+	}
+	if m.notice != "" {
+		b.WriteString(WarningStyle.Render(m.notice) + "\n")
+	}
+	b.WriteString(HelpStyle.Render("enter: continue • a: abort " + string(m.pick.Kind) + " • esc: back (changes stay staged)"))
+	return b.String()
+}
@@ -0,0 +1,130 @@
+package ui
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/samcharles93/commiter/internal/config"
+	"github.com/samcharles93/commiter/internal/git"
+)
+
+func TestPickHistoryIncludesReason(t *testing.T) {
+	pick := git.Pick{Kind: git.OperationRevert, Hash: "0123456789abcdef", Message: "Revert \"feat: add cache\""}
+	content := PickHistory(pick, "the cache serves stale prices")[0].Content
+	for _, want := range []string{"reverts commit 0123456789abcdef", "the cache serves stale prices", "This reverts commit 0123456789abcdef."} {
+		if !strings.Contains(content, want) {
+			t.Fatalf("expected %q in the prompt, got:\n%s", want, content)
+		}
+	}
+
+	pick.Kind = git.OperationCherryPick
+	if content := PickHistory(pick, "")[0].Content; !strings.Contains(content, "(cherry picked from commit 0123456789abcdef)") {
+		t.Fatalf("expected the cherry-pick trailer in the prompt, got:\n%s", content)
+	}
+}
+
+func TestHistoryRevertsCommitWithGeneratedMessage(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repoDir := initRepoForModelHookTests(t)
+	writeFileForModelHookTest(t, filepath.Join(repoDir, "tracked.txt"), "changed\n")
+	runGitForModelHookTest(t, repoDir, "commit", "-am", "feat: change tracked")
+
+	repo := git.NewRepo(repoDir, nil)
+	commits, err := repo.GetCommitHistory(10)
+	if err != nil {
+		t.Fatalf("GetCommitHistory() error = %v", err)
+	}
+	m := NewHistoryModel(repo, commits, HistoryOptions{Provider: stubProvider{}, Config: &config.Config{}})
+	detail, diff, err := repo.GetCommitDetails(commits[0].Hash)
+	if err != nil {
+		t.Fatalf("GetCommitDetails() error = %v", err)
+	}
+	m.commitDetail, m.commitDiff, m.state = detail, diff, historyStateDetail
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("v")})
+	m = updated.(HistoryModel)
+	if m.state != historyStatePickReason {
+		t.Fatalf("expected the reason prompt, got state %q", m.state)
+	}
+	m.reasonInput.SetValue("it broke the build")
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(HistoryModel)
+	if m.state != historyStatePickCommit || m.pickFlow.state != StateGenerating {
+		t.Fatalf("expected message generation for the revert, got state %q (notice %q)", m.state, m.notice)
+	}
+	if m.pickFlow.pickReason != "it broke the build" {
+		t.Fatalf("expected the reason to reach the message flow, got %q", m.pickFlow.pickReason)
+	}
+
+	updated, _ = m.Update(m.pickFlow.generateCommitMsg()())
+	m = updated.(HistoryModel)
+	if !strings.Contains(m.View(), "Reverting") || !strings.Contains(m.View(), "[y] commit revert") {
+		t.Fatalf("expected the revert review, got:\n%s", m.View())
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = updated.(HistoryModel)
+	updated, _ = m.Update(m.pickFlow.commitChanges()())
+	m = updated.(HistoryModel)
+	if m.state != historyStateList || !strings.Contains(m.notice, "Reverted") {
+		t.Fatalf("expected to return to the list, got state %q notice %q", m.state, m.notice)
+	}
+
+	if log := runGitForModelHookTest(t, repoDir, "log", "--format=%s"); log != "test commit\nfeat: change tracked\nchore: init\n" {
+		t.Fatalf("unexpected history after revert:\n%s", log)
+	}
+	if content := runGitForModelHookTest(t, repoDir, "show", "HEAD:tracked.txt"); content != "initial\n" {
+		t.Fatalf("expected the change to be reverted, got %q", content)
+	}
+}
+
+func TestHistoryCherryPickConflictCanBeAborted(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repoDir := initRepoForModelHookTests(t)
+	runGitForModelHookTest(t, repoDir, "checkout", "-b", "side")
+	writeFileForModelHookTest(t, filepath.Join(repoDir, "tracked.txt"), "side\n")
+	runGitForModelHookTest(t, repoDir, "commit", "-am", "feat: side change")
+	side := strings.TrimSpace(runGitForModelHookTest(t, repoDir, "rev-parse", "HEAD"))
+	runGitForModelHookTest(t, repoDir, "checkout", "-")
+	writeFileForModelHookTest(t, filepath.Join(repoDir, "tracked.txt"), "main\n")
+	runGitForModelHookTest(t, repoDir, "commit", "-am", "feat: main change")
+
+	repo := git.NewRepo(repoDir, nil)
+	m := NewHistoryModel(repo, nil, HistoryOptions{Provider: stubProvider{}, Config: &config.Config{}})
+	detail, _, err := repo.GetCommitDetails(side)
+	if err != nil {
+		t.Fatalf("GetCommitDetails() error = %v", err)
+	}
+	m.commitDetail, m.state = detail, historyStateDetail
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	updated, _ = updated.(HistoryModel).Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(HistoryModel)
+	if m.state != historyStatePick || !strings.Contains(m.View(), "Conflicts in 1 file(s)") {
+		t.Fatalf("expected the conflict screen, got state %q:\n%s", m.state, m.View())
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(HistoryModel)
+	if m.state != historyStatePick || !strings.Contains(m.notice, "Still conflicted: tracked.txt") {
+		t.Fatalf("expected unresolved conflicts to block the commit, got state %q notice %q", m.state, m.notice)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	m = updated.(HistoryModel)
+	if m.state != historyStateList || !strings.Contains(m.notice, "Aborted the cherry-pick") {
+		t.Fatalf("expected the abort to return to the list, got state %q notice %q", m.state, m.notice)
+	}
+	if status := runGitForModelHookTest(t, repoDir, "status", "--porcelain"); status != "" {
+		t.Fatalf("expected a clean tree after abort, got %q", status)
+	}
+}
@@ -0,0 +1,264 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/samcharles93/commiter/internal/git"
+	"github.com/samcharles93/commiter/internal/llm"
+	"github.com/samcharles93/commiter/internal/release"
+)
+
+const (
+	releaseStateGenerating = "generating"
+	releaseStateReview     = "review"
+	releaseStateEditing    = "editing"
+	releaseStateTagging    = "tagging"
+	releaseStateDone       = "done"
+	releaseStateError      = "error"
+)
+
+const releaseNotesTimeout = 60 * time.Second
+
+// ReleaseOptions carries per-run settings for the release model.
+type ReleaseOptions struct {
+	Language     string
+	Tag          git.TagOptions
+	ProviderName string
+	ModelName    string
+}
+
+// ReleaseModel is the TUI model for reviewing release notes and creating the
+// release tag.
+type ReleaseModel struct {
+	repo      *git.Repo
+	completer llm.Completer
+	plan      *release.Plan
+	opts      ReleaseOptions
+	state     string
+	notes     string
+	err       error
+	spinner   spinner.Model
+	textarea  textarea.Model
+}
+
+type releaseNotesMsg struct {
+	notes string
+	err   error
+}
+
+type releaseTaggedMsg struct {
+	err error
+}
+
+// NewReleaseModel creates the release screen for a planned version.
+func NewReleaseModel(completer llm.Completer, repo *git.Repo, plan *release.Plan, opts ReleaseOptions) ReleaseModel {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = SelectedFileStyle
+
+	ta := textarea.New()
+	ta.Placeholder = "Tag message..."
+	ta.ShowLineNumbers = false
+	ta.SetWidth(72)
+	ta.SetHeight(12)
+
+	return ReleaseModel{
+		repo:      repo,
+		completer: completer,
+		plan:      plan,
+		opts:      opts,
+		state:     releaseStateGenerating,
+		spinner:   s,
+		textarea:  ta,
+	}
+}
+
+func (m ReleaseModel) Init() tea.Cmd {
+	return tea.Batch(m.spinner.Tick, m.generateNotes())
+}
+
+func (m ReleaseModel) generateNotes() tea.Cmd {
+	memory := readMemory(m.repo)
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), releaseNotesTimeout)
+		defer cancel()
+
+		notes, err := release.GenerateNotes(ctx, m.completer, m.plan, m.opts.Language, memory)
+		return releaseNotesMsg{notes: notes, err: err}
+	}
+}
+
+func (m ReleaseModel) createTag() tea.Cmd {
+	return func() tea.Msg {
+		return releaseTaggedMsg{err: m.repo.CreateTag(m.plan.Tag(), m.notes, m.opts.Tag)}
+	}
+}
+
+func (m ReleaseModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+
+		switch m.state {
+		case releaseStateGenerating:
+			if msg.String() == "q" || msg.String() == "esc" {
+				return m, tea.Quit
+			}
+			return m, nil
+
+		case releaseStateReview:
+			switch msg.String() {
+			case "y":
+				m.state = releaseStateTagging
+				return m, tea.Batch(m.spinner.Tick, m.createTag())
+			case "e":
+				m.textarea.SetValue(m.notes)
+				m.textarea.Focus()
+				m.state = releaseStateEditing
+				return m, textarea.Blink
+			case "n":
+				m.state = releaseStateGenerating
+				return m, tea.Batch(m.spinner.Tick, m.generateNotes())
+			case "q", "esc":
+				return m, tea.Quit
+			}
+			return m, nil
+
+		case releaseStateEditing:
+			switch msg.String() {
+			case "esc":
+				m.textarea.Blur()
+				m.state = releaseStateReview
+				return m, nil
+			case "ctrl+s":
+				if notes := strings.TrimSpace(m.textarea.Value()); notes != "" {
+					m.notes = notes
+				}
+				m.textarea.Blur()
+				m.state = releaseStateReview
+				return m, nil
+			}
+
+		case releaseStateDone, releaseStateError:
+			return m, tea.Quit
+		}
+
+	case releaseNotesMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			m.state = releaseStateError
+			return m, nil
+		}
+		m.notes = msg.notes
+		m.state = releaseStateReview
+		return m, nil
+
+	case releaseTaggedMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			m.state = releaseStateError
+			return m, nil
+		}
+		m.state = releaseStateDone
+		return m, nil
+
+	case spinner.TickMsg:
+		if m.state == releaseStateGenerating || m.state == releaseStateTagging {
+			m.spinner, cmd = m.spinner.Update(msg)
+			return m, cmd
+		}
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		appH, _ := AppStyle.GetFrameSize()
+		h, _ := BoxStyle.GetFrameSize()
+		m.textarea.SetWidth(max(msg.Width-appH-h-4, 20))
+	}
+
+	if m.state == releaseStateEditing {
+		m.textarea, cmd = m.textarea.Update(msg)
+	}
+	return m, cmd
+}
+
+func (m ReleaseModel) View() string {
+	var content string
+	switch m.state {
+	case releaseStateGenerating:
+		content = m.renderTitle() + "\n" + m.spinner.View() + " Writing release notes...\n"
+	case releaseStateReview:
+		content = m.renderReview()
+	case releaseStateEditing:
+		content = m.renderTitle() + "\n" + m.textarea.View() + "\n" + HelpStyle.Render("ctrl+s: save • esc: cancel")
+	case releaseStateTagging:
+		content = m.renderTitle() + "\n" + m.spinner.View() + " Creating tag " + m.plan.Tag() + "...\n"
+	case releaseStateDone:
+		content = m.renderDone()
+	case releaseStateError:
+		content = m.renderError()
+	}
+	return AppStyle.Render(content)
+}
+
+func (m ReleaseModel) renderTitle() string {
+	var b strings.Builder
+	b.WriteString(TitleStyle.Render("🏷  Release "+m.plan.Tag()) + "\n")
+
+	since := "first release"
+	if m.plan.Previous != "" {
+		since = "since " + m.plan.Previous
+	}
+	noun := "commits"
+	if len(m.plan.Commits) == 1 {
+		noun = "commit"
+	}
+	b.WriteString(SubtleStyle.Render(fmt.Sprintf("%s bump, %d %s %s", m.plan.Bump, len(m.plan.Commits), noun, since)) + "\n")
+	return b.String()
+}
+
+func (m ReleaseModel) renderReview() string {
+	var b strings.Builder
+	b.WriteString(m.renderTitle() + "\n")
+	b.WriteString(SubtleStyle.Render("Tag message:") + "\n")
+	b.WriteString(CommitMsgStyle.Render(m.notes) + "\n\n")
+
+	tag := "annotated"
+	if m.opts.Tag.Sign {
+		tag = "signed"
+	}
+	if m.opts.ProviderName != "" {
+		b.WriteString(SubtleStyle.Render("Provider: "+m.opts.ProviderName+" | Model: "+m.opts.ModelName) + "\n")
+	}
+	b.WriteString(SubtleStyle.Render("Tag: "+tag) + "\n\n")
+	b.WriteString(HelpStyle.Render("[y] create tag • [e] edit • [n] regenerate • [q] quit"))
+	return b.String()
+}
+
+func (m ReleaseModel) renderDone() string {
+	var b strings.Builder
+	b.WriteString("\n")
+	b.WriteString(SuccessStyle.Render("✓ Tagged "+m.plan.Tag()) + "\n\n")
+	b.WriteString(SubtleStyle.Render("Push it with: git push origin "+m.plan.Tag()) + "\n")
+	b.WriteString(HelpStyle.Render("Press any key to exit"))
+	return b.String()
+}
+
+func (m ReleaseModel) renderError() string {
+	var b strings.Builder
+	b.WriteString("\n")
+	b.WriteString(ErrorStyle.Render("❌ Error") + "\n\n")
+	b.WriteString(ErrorBoxStyle.Render(m.err.Error()) + "\n")
+	b.WriteString(HelpStyle.Render("Press any key to exit"))
+	return b.String()
+}
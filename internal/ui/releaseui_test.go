@@ -0,0 +1,45 @@
+package ui
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/samcharles93/commiter/internal/git"
+	"github.com/samcharles93/commiter/internal/release"
+)
+
+func TestReleaseModelTagsReviewedNotes(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repoDir := initRepoForModelHookTests(t)
+	repo := git.NewRepo(repoDir, git.ExecRunner{})
+	plan := &release.Plan{Next: release.Version{Prefix: "v", Major: 1}, Bump: release.BumpMajor}
+	m := NewReleaseModel(nil, repo, plan, ReleaseOptions{})
+
+	updated, _ := m.Update(releaseNotesMsg{notes: "First stable release\n\n## Highlights\n- everything"})
+	m = updated.(ReleaseModel)
+	if m.state != releaseStateReview || !strings.Contains(m.View(), "Release v1.0.0") {
+		t.Fatalf("expected the review screen, got state %q", m.state)
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = updated.(ReleaseModel)
+	if m.state != releaseStateTagging || cmd == nil {
+		t.Fatalf("expected tagging, got state %q", m.state)
+	}
+	updated, _ = m.Update(m.createTag()())
+	m = updated.(ReleaseModel)
+	if m.state != releaseStateDone {
+		t.Fatalf("expected done, got state %q (err %v)", m.state, m.err)
+	}
+
+	message := runGitForModelHookTest(t, repoDir, "tag", "-l", "--format=%(objecttype) %(contents)", "v1.0.0")
+	if message != "tag First stable release\n\n## Highlights\n- everything\n\n" {
+		t.Fatalf("unexpected tag: %q", message)
+	}
+}
@@ -0,0 +1,119 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/samcharles93/commiter/internal/graph"
+)
+
+// startReword opens the commit message flow for the commit shown in the
+// detail view. Commits already on a remote branch are refused.
+func (m HistoryModel) startReword() (tea.Model, tea.Cmd) {
+	if m.commitDetail == nil {
+		return m, nil
+	}
+	if m.opts.Provider == nil {
+		m.notice = "Rewording needs a configured provider and API key"
+		return m, nil
+	}
+
+	hash := m.commitDetail.Hash
+	pushed, err := m.repo.IsPushed(hash)
+	if err != nil {
+		m.state = historyStateError
+		m.err = err
+		return m, nil
+	}
+	if pushed {
+		m.notice = "⚠ " + hash[:8] + " is already on a remote branch. Rewording it would rewrite published history, so it is refused."
+		return m, nil
+	}
+	operation, err := m.repo.InProgress()
+	if err != nil {
+		m.state = historyStateError
+		m.err = err
+		return m, nil
+	}
+	if operation.InProgress() {
+		m.notice = operation.Title() + "; finish it before rewording"
+		return m, nil
+	}
+
+	commit := *m.commitDetail
+	model := NewModel(m.opts.Provider, nil, m.commitDiff, m.opts.Config, Options{
+		Repo:          m.repo,
+		ProviderName:  m.opts.ProviderName,
+		ModelName:     m.opts.ModelName,
+		HooksDisabled: true,
+		Language:      m.opts.Language,
+		Commit:        m.opts.Commit,
+		Reword:        &commit,
+	})
+	if m.windowSize.Width > 0 {
+		updated, _ := model.Update(m.windowSize)
+		model = updated.(Model)
+	}
+
+	m.reword = &model
+	m.state = historyStateReword
+	return m, model.Init()
+}
+
+// updateReword forwards messages to the embedded commit message flow, and
+// returns to the history when it finishes or is cancelled.
+func (m HistoryModel) updateReword(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		switch m.reword.state {
+		case StateError:
+			m.reword = nil
+			m.state = historyStateDetail
+			return m, nil
+		case StateReview, StateGenerating, StateTemplateSelection:
+			if msg.String() == "q" || msg.String() == "esc" {
+				m.reword = nil
+				m.state = historyStateDetail
+				return m, nil
+			}
+		}
+
+	case CommitSuccessMsg:
+		hash := m.reword.reword.Hash
+		m.reword = nil
+		if err := m.reloadCommits(); err != nil {
+			m.state = historyStateError
+			m.err = err
+			return m, nil
+		}
+		m.notice = "✓ Reworded " + hash[:8]
+		m.state = historyStateList
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		m.windowSize = msg
+	}
+
+	updated, cmd := m.reword.Update(msg)
+	model := updated.(Model)
+	m.reword = &model
+	return m, cmd
+}
+
+// reloadCommits reads the history again after it was rewritten.
+func (m *HistoryModel) reloadCommits() error {
+	commits, err := m.repo.CommitHistory(m.opts.Filter, 0, max(len(m.commits), 1))
+	if err != nil {
+		return err
+	}
+
+	m.commits = commits
+	m.graph, m.graphRows = graph.Builder{}, nil
+	m.addGraphRows(commits)
+	m.filterCommits("")
+	m.commitDetail = nil
+	m.commitDiff = ""
+	return nil
+}
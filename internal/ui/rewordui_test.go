@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/samcharles93/commiter/internal/config"
+	"github.com/samcharles93/commiter/internal/git"
+)
+
+func TestHistoryRewordsUnpushedCommit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repoDir := initRepoForModelHookTests(t)
+	writeFileForModelHookTest(t, filepath.Join(repoDir, "second.txt"), "second\n")
+	runGitForModelHookTest(t, repoDir, "add", "second.txt")
+	runGitForModelHookTest(t, repoDir, "commit", "-m", "wip")
+	writeFileForModelHookTest(t, filepath.Join(repoDir, "third.txt"), "third\n")
+	runGitForModelHookTest(t, repoDir, "add", "third.txt")
+	runGitForModelHookTest(t, repoDir, "commit", "-m", "feat: add third")
+	runGitForModelHookTest(t, repoDir, "update-ref", "refs/remotes/origin/main", "HEAD~2")
+
+	repo := git.NewRepo(repoDir, nil)
+	commits, err := repo.GetCommitHistory(10)
+	if err != nil {
+		t.Fatalf("GetCommitHistory() error = %v", err)
+	}
+	m := NewHistoryModel(repo, commits, HistoryOptions{Provider: stubProvider{}, Config: &config.Config{}})
+
+	open := func(hash string) {
+		t.Helper()
+		detail, diff, err := repo.GetCommitDetails(hash)
+		if err != nil {
+			t.Fatalf("GetCommitDetails() error = %v", err)
+		}
+		m.commitDetail, m.commitDiff = detail, diff
+		m.state = historyStateDetail
+	}
+	press := func(key string) tea.Cmd {
+		t.Helper()
+		updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		m = updated.(HistoryModel)
+		return cmd
+	}
+
+	open(commits[2].Hash)
+	press("r")
+	if m.state != historyStateDetail || !strings.Contains(m.notice, "remote branch") {
+		t.Fatalf("expected a pushed commit to be refused, got state %q notice %q", m.state, m.notice)
+	}
+
+	open(commits[1].Hash)
+	press("r")
+	if m.state != historyStateReword || m.reword.state != StateGenerating {
+		t.Fatalf("expected the reword flow to start generating, got state %q", m.state)
+	}
+
+	updated, _ := m.Update(m.reword.generateCommitMsg()())
+	m = updated.(HistoryModel)
+	if m.reword.state != StateReview || !strings.Contains(m.View(), "Rewording") {
+		t.Fatalf("expected the reword review, got %q", m.reword.state)
+	}
+
+	press("y")
+	if m.reword.state != StateCommitting {
+		t.Fatalf("expected the reword to start, got %q", m.reword.state)
+	}
+	updated, _ = m.Update(m.reword.commitChanges()())
+	m = updated.(HistoryModel)
+	if m.state != historyStateList || !strings.Contains(m.notice, "Reworded") {
+		t.Fatalf("expected to return to the list, got state %q notice %q", m.state, m.notice)
+	}
+
+	log := runGitForModelHookTest(t, repoDir, "log", "--format=%s")
+	if log != "feat: add third\ntest commit\nchore: init\n" {
+		t.Fatalf("unexpected history after reword:\n%s", log)
+	}
+	if len(m.commits) != 3 || m.commits[1].Subject != "test commit" {
+		t.Fatalf("expected the list to reload, got %+v", m.commits)
+	}
+}
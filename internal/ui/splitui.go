@@ -0,0 +1,489 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/samcharles93/commiter/internal/config"
+	"github.com/samcharles93/commiter/internal/git"
+	"github.com/samcharles93/commiter/internal/hooks"
+	"github.com/samcharles93/commiter/internal/llm"
+	"github.com/samcharles93/commiter/internal/split"
+)
+
+const (
+	splitStateProposing  = "proposing"
+	splitStateEditing    = "editing"
+	splitStateMessage    = "message"
+	splitStateCommitting = "committing"
+	splitStateDone       = "done"
+	splitStateError      = "error"
+)
+
+const splitProposeTimeout = 60 * time.Second
+
+// SplitOptions carries per-run settings for the split model.
+type SplitOptions struct {
+	Template      *config.CommitTemplate
+	Commit        git.CommitOptions
+	PreHooks      []string
+	PostHooks     []string
+	HookTimeout   time.Duration
+	HooksDisabled bool
+}
+
+// SplitModel is the TUI model for splitting changes into several commits.
+type SplitModel struct {
+	repo      *git.Repo
+	completer llm.Completer
+	plan      *split.Plan
+	opts      SplitOptions
+	state     string
+	cursor    int
+	editing   int
+	committed int
+	warnings  []string
+	notice    string
+	err       error
+	spinner   spinner.Model
+	textarea  textarea.Model
+	height    int
+}
+
+type splitProposedMsg struct {
+	groups []split.Group
+	err    error
+}
+
+type splitCommittedMsg struct {
+	index   int
+	warning string
+	err     error
+}
+
+// splitRow is a line of the plan: a commit heading when unit is empty,
+// otherwise one of its units.
+type splitRow struct {
+	group int
+	unit  string
+}
+
+// NewSplitModel creates the split screen for a plan of uncommitted changes.
+func NewSplitModel(completer llm.Completer, repo *git.Repo, plan *split.Plan, opts SplitOptions) SplitModel {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = SelectedFileStyle
+
+	ta := textarea.New()
+	ta.Placeholder = "Commit message..."
+	ta.ShowLineNumbers = false
+	ta.SetWidth(72)
+	ta.SetHeight(8)
+
+	return SplitModel{
+		repo:      repo,
+		completer: completer,
+		plan:      plan,
+		opts:      opts,
+		state:     splitStateProposing,
+		spinner:   s,
+		textarea:  ta,
+	}
+}
+
+func (m SplitModel) Init() tea.Cmd {
+	return tea.Batch(m.spinner.Tick, m.propose())
+}
+
+// propose asks the model for a grouping. It works on a copy so the plan is
+// only changed from Update.
+func (m SplitModel) propose() tea.Cmd {
+	plan, memory := *m.plan, readMemory(m.repo)
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), splitProposeTimeout)
+		defer cancel()
+
+		err := split.Propose(ctx, m.completer, &plan, m.opts.Template, memory)
+		return splitProposedMsg{groups: plan.Groups, err: err}
+	}
+}
+
+// commitGroup stages and commits the group at index i. The index is reset
+// before the first group, and a failed group is unstaged again so the tree
+// is left as it was before that commit.
+func (m SplitModel) commitGroup(i int) tea.Cmd {
+	plan := m.plan
+	message := plan.Groups[i].Message
+	return func() tea.Msg {
+		if i == 0 {
+			if err := plan.Reset(m.repo); err != nil {
+				return splitCommittedMsg{index: i, err: err}
+			}
+		}
+		if err := plan.Stage(m.repo, i); err != nil {
+			_ = m.repo.UnstageFiles(plan.GroupPaths(i))
+			return splitCommittedMsg{index: i, err: err}
+		}
+
+		if !m.opts.HooksDisabled {
+			if err := hooks.Run(context.Background(), hooks.RunOptions{
+				Phase:         hooks.PhasePreCommit,
+				Dir:           m.repo.Dir(),
+				Commands:      m.opts.PreHooks,
+				Timeout:       m.opts.HookTimeout,
+				CommitMessage: message,
+			}); err != nil {
+				_ = m.repo.UnstageFiles(plan.GroupPaths(i))
+				return splitCommittedMsg{index: i, err: fmt.Errorf("pre-commit hook failed: %w", err)}
+			}
+		}
+
+		if err := m.repo.Commit(message, m.opts.Commit); err != nil {
+			_ = m.repo.UnstageFiles(plan.GroupPaths(i))
+			return splitCommittedMsg{index: i, err: err}
+		}
+
+		var warning string
+		if !m.opts.HooksDisabled {
+			if err := hooks.Run(context.Background(), hooks.RunOptions{
+				Phase:         hooks.PhasePostCommit,
+				Dir:           m.repo.Dir(),
+				Commands:      m.opts.PostHooks,
+				Timeout:       m.opts.HookTimeout,
+				CommitMessage: message,
+			}); err != nil {
+				warning = fmt.Sprintf("commit %d: post-commit hook failed: %v", i+1, err)
+			}
+		}
+		return splitCommittedMsg{index: i, warning: warning}
+	}
+}
+
+// rows lists the plan as commit headings followed by their units.
+func (m SplitModel) rows() []splitRow {
+	var rows []splitRow
+	for i, group := range m.plan.Groups {
+		rows = append(rows, splitRow{group: i})
+		for _, id := range group.Units {
+			rows = append(rows, splitRow{group: i, unit: id})
+		}
+	}
+	return rows
+}
+
+func (m SplitModel) currentRow() (splitRow, bool) {
+	rows := m.rows()
+	if m.cursor < 0 || m.cursor >= len(rows) {
+		return splitRow{}, false
+	}
+	return rows[m.cursor], true
+}
+
+// moveUnit moves the unit under the cursor to the group at index to and keeps
+// the cursor on it.
+func (m *SplitModel) moveUnit(to int) {
+	row, ok := m.currentRow()
+	if !ok || row.unit == "" {
+		m.notice = "Select a file or hunk to move"
+		return
+	}
+	if err := m.plan.Move(row.unit, to); err != nil {
+		m.notice = err.Error()
+		return
+	}
+
+	m.notice = ""
+	for i, r := range m.rows() {
+		if r.unit == row.unit {
+			m.cursor = i
+			return
+		}
+	}
+}
+
+// moveUnitBy moves the unit under the cursor to the commit before (delta -1)
+// or after (delta 1) its own. Past the last commit it starts a new one, so
+// every commit can be reached, not only the first nine.
+func (m *SplitModel) moveUnitBy(delta int) {
+	row, ok := m.currentRow()
+	if !ok || row.unit == "" {
+		m.notice = "Select a file or hunk to move"
+		return
+	}
+	to := row.group + delta
+	if to < 0 {
+		m.notice = "Already in the first commit"
+		return
+	}
+	m.moveUnit(to)
+}
+
+func (m SplitModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+
+		switch m.state {
+		case splitStateEditing:
+			key := msg.String()
+			switch key {
+			case "up", "k":
+				if m.cursor > 0 {
+					m.cursor--
+				}
+				return m, nil
+			case "down", "j":
+				if m.cursor < len(m.rows())-1 {
+					m.cursor++
+				}
+				return m, nil
+			case "n":
+				m.moveUnit(len(m.plan.Groups))
+				return m, nil
+			case "<":
+				m.moveUnitBy(-1)
+				return m, nil
+			case ">":
+				m.moveUnitBy(1)
+				return m, nil
+			case "e", "enter":
+				row, ok := m.currentRow()
+				if !ok {
+					return m, nil
+				}
+				m.editing = row.group
+				m.textarea.SetValue(m.plan.Groups[row.group].Message)
+				m.textarea.Focus()
+				m.state = splitStateMessage
+				return m, textarea.Blink
+			case "r":
+				m.notice = ""
+				m.state = splitStateProposing
+				return m, tea.Batch(m.spinner.Tick, m.propose())
+			case "c":
+				if err := m.plan.Validate(); err != nil {
+					m.notice = err.Error()
+					return m, nil
+				}
+				m.notice = ""
+				m.committed = 0
+				m.state = splitStateCommitting
+				return m, tea.Batch(m.spinner.Tick, m.commitGroup(0))
+			case "q", "esc":
+				return m, tea.Quit
+			}
+			if len(key) == 1 && key[0] >= '1' && key[0] <= '9' {
+				m.moveUnit(int(key[0] - '1'))
+				return m, nil
+			}
+			return m, nil
+
+		case splitStateMessage:
+			switch msg.String() {
+			case "esc":
+				m.textarea.Blur()
+				m.state = splitStateEditing
+				return m, nil
+			case "ctrl+s":
+				m.plan.Groups[m.editing].Message = strings.TrimSpace(m.textarea.Value())
+				m.textarea.Blur()
+				m.state = splitStateEditing
+				return m, nil
+			}
+
+		case splitStateDone, splitStateError:
+			return m, tea.Quit
+		}
+
+	case splitProposedMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			m.state = splitStateError
+			return m, nil
+		}
+		m.plan.Groups = msg.groups
+		m.cursor = 0
+		m.state = splitStateEditing
+		return m, nil
+
+	case splitCommittedMsg:
+		if msg.warning != "" {
+			m.warnings = append(m.warnings, msg.warning)
+		}
+		if msg.err != nil {
+			m.err = fmt.Errorf("stopped at commit %d of %d after %d succeeded: %w; its changes are back in the working tree",
+				msg.index+1, len(m.plan.Groups), m.committed, msg.err)
+			m.state = splitStateError
+			return m, nil
+		}
+		m.committed++
+		if m.committed == len(m.plan.Groups) {
+			m.state = splitStateDone
+			return m, nil
+		}
+		return m, m.commitGroup(m.committed)
+
+	case spinner.TickMsg:
+		if m.state == splitStateProposing || m.state == splitStateCommitting {
+			m.spinner, cmd = m.spinner.Update(msg)
+			return m, cmd
+		}
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		appH, _ := AppStyle.GetFrameSize()
+		h, _ := BoxStyle.GetFrameSize()
+		m.textarea.SetWidth(max(msg.Width-appH-h-4, 20))
+		m.height = msg.Height
+	}
+
+	if m.state == splitStateMessage {
+		m.textarea, cmd = m.textarea.Update(msg)
+	}
+	return m, cmd
+}
+
+func (m SplitModel) View() string {
+	var content string
+	switch m.state {
+	case splitStateProposing:
+		content = m.renderProposing()
+	case splitStateEditing:
+		content = m.renderPlan()
+	case splitStateMessage:
+		content = m.renderMessage()
+	case splitStateCommitting:
+		content = m.renderCommitting()
+	case splitStateDone:
+		content = m.renderDone()
+	case splitStateError:
+		content = m.renderError()
+	}
+	return AppStyle.Render(content)
+}
+
+func (m SplitModel) renderProposing() string {
+	var b strings.Builder
+	b.WriteString(TitleStyle.Render("✂️  Split Changes") + "\n\n")
+	b.WriteString(fmt.Sprintf("%s Grouping %d changes into commits...\n", m.spinner.View(), len(m.plan.Units)))
+	return b.String()
+}
+
+func (m SplitModel) renderPlan() string {
+	var b strings.Builder
+	b.WriteString(TitleStyle.Render("✂️  Split Changes") + "\n")
+	b.WriteString(SubtleStyle.Render(fmt.Sprintf("%d changes in %d commits", len(m.plan.Units), len(m.plan.Groups))) + "\n\n")
+
+	rows := m.rows()
+	start, end := visibleRange(len(rows), m.cursor, m.height-10)
+	for i := start; i < end; i++ {
+		row := rows[i]
+		prefix := "  "
+		if i == m.cursor {
+			prefix = "> "
+		}
+
+		var line string
+		if row.unit == "" {
+			subject, _, _ := strings.Cut(m.plan.Groups[row.group].Message, "\n")
+			if subject == "" {
+				subject = WarningStyle.Render("(no message)")
+			}
+			line = fmt.Sprintf("%d. %s", row.group+1, subject)
+			if i == m.cursor {
+				line = SelectedFileStyle.Render(line)
+			} else {
+				line = TitleStyle.UnsetMargins().Render(line)
+			}
+		} else {
+			unit, _ := m.plan.Unit(row.unit)
+			line = "   " + unit.Title()
+			if i == m.cursor {
+				line = SelectedFileStyle.Render(line)
+			}
+		}
+		b.WriteString(prefix + line + "\n")
+	}
+
+	if m.notice != "" {
+		b.WriteString("\n" + WarningStyle.Render(m.notice) + "\n")
+	}
+	b.WriteString(HelpStyle.Render("↑↓: navigate • 1-9: move to commit • </>: move to previous/next commit • n: move to new commit • e: edit message • r: propose again • c: commit all • q: quit"))
+	return b.String()
+}
+
+// visibleRange returns the slice of rows to draw so the cursor stays on screen.
+func visibleRange(total, cursor, height int) (int, int) {
+	if height <= 0 || total <= height {
+		return 0, total
+	}
+	start := max(cursor-height/2, 0)
+	end := min(start+height, total)
+	return end - height, end
+}
+
+func (m SplitModel) renderMessage() string {
+	var b strings.Builder
+	b.WriteString(TitleStyle.Render(fmt.Sprintf("✏️  Message for commit %d", m.editing+1)) + "\n\n")
+	for _, id := range m.plan.Groups[m.editing].Units {
+		unit, _ := m.plan.Unit(id)
+		b.WriteString(SubtleStyle.Render("  "+unit.Title()) + "\n")
+	}
+	b.WriteString("\n" + m.textarea.View() + "\n")
+	b.WriteString(HelpStyle.Render("ctrl+s: save • esc: cancel"))
+	return b.String()
+}
+
+func (m SplitModel) renderCommitting() string {
+	var b strings.Builder
+	b.WriteString(TitleStyle.Render("✂️  Split Changes") + "\n\n")
+	for i, group := range m.plan.Groups {
+		subject, _, _ := strings.Cut(group.Message, "\n")
+		switch {
+		case i < m.committed:
+			b.WriteString(SuccessStyle.Render("✓ ") + subject + "\n")
+		case i == m.committed:
+			b.WriteString(m.spinner.View() + " " + subject + "\n")
+		default:
+			b.WriteString(SubtleStyle.Render("  "+subject) + "\n")
+		}
+	}
+	return b.String()
+}
+
+func (m SplitModel) renderDone() string {
+	var b strings.Builder
+	b.WriteString("\n")
+	b.WriteString(SuccessStyle.Render(fmt.Sprintf("✓ Created %d commits", m.committed)) + "\n\n")
+	for _, group := range m.plan.Groups {
+		subject, _, _ := strings.Cut(group.Message, "\n")
+		b.WriteString("  " + subject + "\n")
+	}
+	for _, warning := range m.warnings {
+		b.WriteString("\n" + WarningStyle.Render("⚠ "+warning) + "\n")
+	}
+	b.WriteString(HelpStyle.Render("Press any key to exit"))
+	return b.String()
+}
+
+func (m SplitModel) renderError() string {
+	var b strings.Builder
+	b.WriteString("\n")
+	b.WriteString(ErrorStyle.Render("❌ Error") + "\n\n")
+	b.WriteString(ErrorBoxStyle.Render(m.err.Error()) + "\n")
+	for _, warning := range m.warnings {
+		b.WriteString(WarningStyle.Render("⚠ "+warning) + "\n")
+	}
+	b.WriteString(HelpStyle.Render("Press any key to exit"))
+	return b.String()
+}
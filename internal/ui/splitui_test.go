@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"fmt"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/samcharles93/commiter/internal/split"
+)
+
+func TestSplitModelMovesUnitsBetweenCommits(t *testing.T) {
+	plan := &split.Plan{Units: []split.Unit{
+		{ID: "u1", Path: "a.go", Label: "modified"},
+		{ID: "u2", Path: "b.go", Label: "modified"},
+	}}
+	m := NewSplitModel(nil, nil, plan, SplitOptions{})
+
+	updated, _ := m.Update(splitProposedMsg{groups: []split.Group{
+		{Message: "feat: a and b", Units: []string{"u1", "u2"}},
+	}})
+	m = updated.(SplitModel)
+	if m.state != splitStateEditing {
+		t.Fatalf("expected editing state, got %q", m.state)
+	}
+
+	press := func(key string) {
+		t.Helper()
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		m = updated.(SplitModel)
+	}
+
+	// Rows: heading 1, u1, u2. Move u2 to a new commit.
+	press("j")
+	press("j")
+	press("n")
+	if plan.GroupOf("u2") != 1 {
+		t.Fatalf("expected u2 in commit 2, got %+v", plan.Groups)
+	}
+	if row, _ := m.currentRow(); row.unit != "u2" {
+		t.Fatalf("expected the cursor to follow u2, got %+v", row)
+	}
+
+	press("c")
+	if m.state != splitStateEditing || m.notice != "commit 2 has no message" {
+		t.Fatalf("expected a missing message notice, got state %q notice %q", m.state, m.notice)
+	}
+
+	press("1")
+	if len(plan.Groups) != 1 || plan.GroupOf("u2") != 0 {
+		t.Fatalf("expected u2 back in commit 1, got %+v", plan.Groups)
+	}
+}
+
+func TestSplitModelReachesCommitsPastNine(t *testing.T) {
+	plan := &split.Plan{}
+	var groups []split.Group
+	for i := range 10 {
+		a, b := fmt.Sprintf("a%d", i), fmt.Sprintf("b%d", i)
+		plan.Units = append(plan.Units, split.Unit{ID: a, Path: a + ".go"}, split.Unit{ID: b, Path: b + ".go"})
+		groups = append(groups, split.Group{Message: fmt.Sprintf("feat: commit %d", i+1), Units: []string{a, b}})
+	}
+	m := NewSplitModel(nil, nil, plan, SplitOptions{})
+	updated, _ := m.Update(splitProposedMsg{groups: groups})
+	m = updated.(SplitModel)
+
+	press := func(key string) {
+		t.Helper()
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		m = updated.(SplitModel)
+	}
+
+	// Rows: heading 1, a0, b0, ... Move a0 down to the tenth commit.
+	press("j")
+	press("<")
+	if m.notice != "Already in the first commit" {
+		t.Fatalf("expected a notice at the first commit, got %q", m.notice)
+	}
+	for range 9 {
+		press(">")
+	}
+	if plan.GroupOf("a0") != 9 {
+		t.Fatalf("expected a0 in commit 10, got commit %d", plan.GroupOf("a0")+1)
+	}
+	if row, _ := m.currentRow(); row.unit != "a0" {
+		t.Fatalf("expected the cursor to follow a0, got %+v", row)
+	}
+
+	press("<")
+	if plan.GroupOf("a0") != 8 {
+		t.Fatalf("expected a0 back in commit 9, got commit %d", plan.GroupOf("a0")+1)
+	}
+}
@@ -3,10 +3,12 @@ package ui
 // State constants
 const (
 	StateFileSelection     = "file-selection"
+	StateHunkSelection     = "hunk-selection"
 	StateTemplateSelection = "template-selection"
 	StateGenerating        = "generating"
 	StateReview            = "review"
 	StateRefining          = "refining"
+	StateCoAuthors         = "co-authors"
 	StateSummary           = "summary"
 	StateCommitting        = "committing"
 	StateError             = "error"
@@ -15,4 +17,5 @@ const (
 	StateDiffPreview       = "diff-preview"
 	StateAmendConfirm      = "amend-confirm"
 	StateQuitConfirm       = "quit-confirm"
+	StateBranch            = "branch"
 )
@@ -20,6 +20,10 @@ var (
 			Foreground(lipgloss.Color("#FF5F87")).
 			Bold(true)
 
+	WarningStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FFD700")).
+			Bold(true)
+
 	SubtleStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#626262"))
 
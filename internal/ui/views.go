@@ -3,8 +3,6 @@ package ui
 import (
 	"fmt"
 	"strings"
-
-	"github.com/samcharles93/commiter/internal/git"
 )
 
 func (m Model) renderTemplateSelection() string {
@@ -19,9 +17,28 @@ func (m Model) renderTemplateSelection() string {
 func (m Model) renderFileSelection() string {
 	var b strings.Builder
 	b.WriteString(TitleStyle.Render("🔍 Commiter") + "\n")
-	b.WriteString(SubtleStyle.Render("No staged changes detected") + "\n\n")
+	b.WriteString(SubtleStyle.Render(m.stagedSummary()) + "\n")
+	if banner := m.operationBanner(); banner != "" {
+		b.WriteString(WarningStyle.Render(banner) + "\n")
+	}
+	b.WriteString("\n")
 	b.WriteString(m.fileList.View() + "\n")
-	b.WriteString(HelpStyle.Render("↑↓: navigate • space: toggle • enter: stage • a: stage all • u: unselect all • d: diff • q: quit"))
+	if m.notice != "" {
+		b.WriteString(WarningStyle.Render(m.notice) + "\n")
+	}
+	b.WriteString(HelpStyle.Render("↑↓: navigate • space: select • s: stage/unstage • enter: generate • a: stage all • u: unselect all • p: hunks • d: diff • A: amend • q: quit"))
+	return b.String()
+}
+
+func (m Model) renderHunkSelection() string {
+	var b strings.Builder
+	b.WriteString(TitleStyle.Render("✂️  Select Hunks") + "\n")
+	b.WriteString(SubtleStyle.Render(m.hunks.file.Path()) + "\n\n")
+	b.WriteString(m.hunks.view() + "\n")
+	if m.notice != "" {
+		b.WriteString(WarningStyle.Render(m.notice) + "\n")
+	}
+	b.WriteString(HelpStyle.Render("↑↓: navigate • space: toggle • s: split into lines • a: toggle all • enter: stage selection • esc: back"))
 	return b.String()
 }
 
@@ -34,16 +51,107 @@ func (m Model) renderGenerating() string {
 
 func (m Model) renderReview() string {
 	var b strings.Builder
-	b.WriteString(TitleStyle.Render("📝 Proposed Commit Message") + "\n")
+	switch {
+	case m.isAmending && m.lastCommit != nil:
+		b.WriteString(TitleStyle.Render("🔄 Amending "+m.lastCommit.Hash[:8]) + "\n")
+		b.WriteString(SubtleStyle.Render("Current message:") + "\n")
+		b.WriteString(BoxStyle.Render(m.markdown.Render(lastCommitMessage(m.lastCommit))) + "\n")
+		b.WriteString(SubtleStyle.Render("New message for the whole commit:") + "\n")
+	case m.reword != nil:
+		b.WriteString(TitleStyle.Render("✏️  Rewording "+m.reword.Hash[:8]) + "\n")
+		b.WriteString(SubtleStyle.Render("Current message:") + "\n")
+		b.WriteString(BoxStyle.Render(m.markdown.Render(lastCommitMessage(m.reword))) + "\n")
+		b.WriteString(SubtleStyle.Render("New message:") + "\n")
+	case m.pick != nil:
+		b.WriteString(TitleStyle.Render(pickTitle(*m.pick)) + "\n")
+		if m.pickReason != "" {
+			b.WriteString(SubtleStyle.Render("Reason: "+m.pickReason) + "\n")
+		}
+	default:
+		b.WriteString(TitleStyle.Render("📝 Proposed Commit Message") + "\n")
+	}
 	b.WriteString(CommitMsgStyle.Render(m.markdown.Render(m.commitMsg)) + "\n\n")
-	b.WriteString(SubtleStyle.Render("Provider: "+m.providerName+" | Model: "+m.modelName) + "\n\n")
+	if len(m.lintIssues) > 0 {
+		b.WriteString(WarningStyle.Render("⚠ Template rule warnings:") + "\n")
+		for _, issue := range m.lintIssues {
+			b.WriteString(SubtleStyle.Render("  • "+issue.String()) + "\n")
+		}
+		b.WriteString("\n")
+	}
+	if banner := m.operationBanner(); banner != "" {
+		b.WriteString(WarningStyle.Render(banner) + "\n")
+	}
+	if m.notice != "" {
+		b.WriteString(ErrorStyle.Render(m.notice) + "\n")
+	}
+	b.WriteString(SubtleStyle.Render("Provider: "+m.providerName+" | Model: "+m.modelName) + "\n")
+	b.WriteString(SubtleStyle.Render("Commit: "+m.commitOptionsSummary()) + "\n")
+	if len(m.coAuthors) > 0 {
+		b.WriteString(SubtleStyle.Render("Co-authors: "+strings.Join(m.coAuthors, ", ")) + "\n")
+	}
+	if m.branch != "" {
+		b.WriteString(SubtleStyle.Render("Branch: "+m.branch) + "\n")
+	}
+	b.WriteString(m.copied.View())
+	b.WriteString("\n")
 
 	amendOption := ""
-	if git.CanAmend() {
+	if m.canAmend {
 		amendOption = " • [a] amend"
 	}
 
-	b.WriteString(HelpStyle.Render("[y] accept • [n] regenerate • [r] refine • [s] summary • [d] diff" + amendOption + " • [?] help • [q] quit"))
+	acceptOption := "[y] accept"
+	switch {
+	case m.operation.InProgress():
+		acceptOption = "[y] continue " + string(m.operation.Kind)
+	case m.isAmending:
+		acceptOption = "[y] amend"
+		amendOption = " • [a] new commit instead"
+	case m.reword != nil:
+		acceptOption = "[y] reword"
+	case m.pick != nil:
+		acceptOption = "[y] commit " + string(m.pick.Kind)
+	}
+
+	b.WriteString(HelpStyle.Render(acceptOption + " • [n] regenerate • [r] refine • [s] summary • [d] diff" + amendOption + " • [b] branch • [Y] copy • [c] co-authors • [g] sign • [o] signoff • [?] help • [q] quit"))
+	return b.String()
+}
+
+// commitOptionsSummary lists the options the next commit will be created with.
+func (m Model) commitOptionsSummary() string {
+	opts := m.commitOpts
+	parts := []string{"unsigned"}
+	if opts.Sign {
+		parts[0] = "signed"
+		if opts.SigningKey != "" {
+			parts[0] += " (" + opts.SigningKey + ")"
+		}
+	}
+	if opts.Signoff {
+		parts = append(parts, "signoff")
+	}
+	if opts.Author != "" {
+		parts = append(parts, "author "+opts.Author)
+	}
+	if opts.Date != "" {
+		parts = append(parts, "date "+opts.Date)
+	}
+	if len(opts.Trailers) > 0 {
+		parts = append(parts, fmt.Sprintf("%d trailer(s)", len(opts.Trailers)))
+	}
+	if opts.NoVerify {
+		parts = append(parts, "no-verify")
+	}
+	return strings.Join(parts, " • ")
+}
+
+func (m Model) renderCoAuthors() string {
+	var b strings.Builder
+	b.WriteString(m.authorList.View() + "\n")
+	if m.notice != "" {
+		b.WriteString(WarningStyle.Render(m.notice) + "\n")
+	}
+	b.WriteString(HelpStyle.Render("↑↓: navigate • /: filter • space: toggle • enter: done • esc: cancel"))
 	return b.String()
 }
 
@@ -129,8 +237,12 @@ func (m Model) renderError() string {
 func (m Model) renderDiffPreview() string {
 	var b strings.Builder
 	b.WriteString(TitleStyle.Render("📄 Diff Preview") + "\n")
-	b.WriteString(m.diffViewer.View() + "\n")
-	b.WriteString(HelpStyle.Render("↑↓/jk: scroll • pgup/pgdn: page • g/G: top/bottom • q/esc: exit"))
+	b.WriteString(m.diffBrowser.View() + "\n")
+	files := ""
+	if len(m.diffBrowser.Files()) > 1 {
+		files = " • ]/[: next/prev file"
+	}
+	b.WriteString(HelpStyle.Render("↑↓/jk: scroll • pgup/pgdn: page • g/G: top/bottom" + files + " • q/esc: exit"))
 	return b.String()
 }
 
@@ -167,23 +279,39 @@ func (m Model) renderHelp() string {
 │  File Selection                              │
 │    ↑↓        Navigate files                  │
 │    Space     Toggle file selection           │
-│    Enter     Stage selected files            │
+│    s         Stage/unstage selected files    │
+│    Enter     Stage selection and generate    │
 │    a         Stage all files                 │
 │    u         Unselect all files              │
+│    p         Pick hunks/lines of file        │
 │    d         Preview file diff               │
+│    A         Amend last commit               │
+│                                              │
+│  Hunk Selection                              │
+│    Space     Toggle hunk or line             │
+│    s/Tab     Split hunk into lines           │
+│    a         Toggle all hunks                │
+│    Enter     Stage selection, back to files  │
+│    Esc       Back to files                   │
 │                                              │
 │  Commit Review                               │
 │    y         Accept commit message           │
 │    n         Regenerate (different option)   │
 │    r         Refine with feedback            │
 │    s         Show change summary             │
-│    a         Amend last commit               │
+│    a         Toggle amending last commit     │
+│    c         Pick co-authors                 │
+│    g         Toggle commit signing           │
+│    o         Toggle Signed-off-by            │
 │    d         Preview full diff               │
+│    b         Create a branch for this work   │
+│    Y         Copy message to the clipboard   │
 │                                              │
 │  Diff Preview                                │
 │    ↑↓/jk     Scroll up/down                  │
 │    PgUp/Dn   Page up/down                    │
 │    g/G       Jump to top/bottom              │
+│    ] / [     Next/previous file              │
 │    q/Esc     Exit diff view                  │
 │                                              │
 │  Refining                                    │